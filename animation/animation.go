@@ -0,0 +1,176 @@
+// Package animation provides sprite animations built on image.Selection
+//
+//	walk := animation.New(animation.Loop,
+//		animation.SpriteSheetFrames(sheet, 16, 16, 100*time.Millisecond)...)
+//	for {
+//		walk.Update(elapsed)
+//		blender.BlendSourceToTarget(walk.CurrentFrame(), screen.Selection(x, y))
+//	}
+package animation
+
+import (
+	"time"
+
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Frame is a single frame of the animation
+type Frame struct {
+	// Selection contains pixels of the frame, usually a part of the sprite sheet
+	Selection image.Selection
+	// Duration is the time the frame is displayed. Must be positive.
+	Duration time.Duration
+}
+
+// Mode defines what happens when the animation reaches the last frame
+type Mode int
+
+const (
+	// Loop starts the animation from the first frame again
+	Loop Mode = iota
+	// PingPong plays frames backwards until the first frame is reached, then
+	// forward again
+	PingPong
+	// Once stops the animation at the last frame
+	Once
+)
+
+// New creates a new Animation which starts at the first frame.
+// Panics when there are no frames or any frame has non-positive duration.
+func New(mode Mode, frames ...Frame) *Animation {
+	if len(frames) == 0 {
+		panic("no frames")
+	}
+	for _, frame := range frames {
+		if frame.Duration <= 0 {
+			panic("non-positive frame duration")
+		}
+	}
+	framesCopy := make([]Frame, len(frames))
+	copy(framesCopy, frames)
+	return &Animation{
+		frames:    framesCopy,
+		mode:      mode,
+		direction: 1,
+	}
+}
+
+// SpriteSheetFrames splits the sprite sheet into frames of given size. Frames are
+// taken row by row, from left to right. Incomplete frames on the right and bottom
+// edge of the sheet are skipped. Each frame has the same duration.
+func SpriteSheetFrames(sheet image.Selection, frameWidth, frameHeight int, duration time.Duration) []Frame {
+	if frameWidth <= 0 || frameHeight <= 0 {
+		panic("non-positive frame size")
+	}
+	var frames []Frame
+	for y := 0; y+frameHeight <= sheet.Height(); y += frameHeight {
+		for x := 0; x+frameWidth <= sheet.Width(); x += frameWidth {
+			frames = append(frames, Frame{
+				Selection: sheet.Selection(x, y).WithSize(frameWidth, frameHeight),
+				Duration:  duration,
+			})
+		}
+	}
+	return frames
+}
+
+// Animation is a sequence of frames played in time. Animation does not use any
+// clock, the time is advanced by calling Update method.
+type Animation struct {
+	frames    []Frame
+	mode      Mode
+	current   int
+	elapsed   time.Duration // time elapsed since the current frame was shown
+	direction int           // 1 when frames are played forward, -1 otherwise
+	finished  bool
+}
+
+// Update advances the animation by a given amount of time. Many frames can be
+// skipped when elapsed time is longer than frame duration. Negative time is ignored.
+func (a *Animation) Update(elapsed time.Duration) {
+	if elapsed <= 0 || a.finished {
+		return
+	}
+	a.elapsed += elapsed
+	if cycle := a.cycleDuration(); cycle > 0 && a.elapsed >= cycle {
+		// after the whole cycle the animation is in exactly the same state
+		a.elapsed %= cycle
+	}
+	for a.elapsed >= a.frames[a.current].Duration {
+		a.elapsed -= a.frames[a.current].Duration
+		a.nextFrame()
+		if a.finished {
+			a.elapsed = 0
+			return
+		}
+	}
+}
+
+// cycleDuration returns the time after which the animation returns to the same
+// frame playing in the same direction. Returns 0 for animations which never do.
+func (a *Animation) cycleDuration() time.Duration {
+	var total time.Duration
+	for _, frame := range a.frames {
+		total += frame.Duration
+	}
+	switch {
+	case a.mode == Loop:
+		return total
+	case a.mode == PingPong && len(a.frames) > 1:
+		first := a.frames[0].Duration
+		last := a.frames[len(a.frames)-1].Duration
+		return 2*total - first - last
+	case a.mode == PingPong:
+		return total
+	default:
+		return 0
+	}
+}
+
+func (a *Animation) nextFrame() {
+	last := len(a.frames) - 1
+	switch a.mode {
+	case Once:
+		if a.current == last {
+			a.finished = true
+			return
+		}
+		a.current++
+	case PingPong:
+		if last == 0 {
+			return
+		}
+		if a.current+a.direction < 0 || a.current+a.direction > last {
+			a.direction = -a.direction
+		}
+		a.current += a.direction
+	default:
+		a.current = (a.current + 1) % len(a.frames)
+	}
+}
+
+// CurrentFrame returns the selection of the frame which should be displayed now.
+// The selection can be passed directly to blending tools, for example
+// blend.SourceOver.BlendSourceToTarget.
+func (a *Animation) CurrentFrame() image.Selection {
+	return a.frames[a.current].Selection
+}
+
+// CurrentFrameIndex returns the index of the frame which should be displayed now
+func (a *Animation) CurrentFrameIndex() int {
+	return a.current
+}
+
+// Finished returns true when the animation in Once mode has reached the end of
+// the last frame. Animations in other modes never finish.
+func (a *Animation) Finished() bool {
+	return a.finished
+}
+
+// Reset rewinds the animation to the beginning of the first frame
+func (a *Animation) Reset() {
+	a.current = 0
+	a.elapsed = 0
+	a.direction = 1
+	a.finished = false
+}
@@ -0,0 +1,242 @@
+package animation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/animation"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should panic when there are no frames", func(t *testing.T) {
+		assert.Panics(t, func() {
+			animation.New(animation.Loop)
+		})
+	})
+	t.Run("should panic when frame duration is not positive", func(t *testing.T) {
+		durations := []time.Duration{0, -1}
+		for _, duration := range durations {
+			t.Run(duration.String(), func(t *testing.T) {
+				frame := animation.Frame{Selection: newSheet(1, 1), Duration: duration}
+				assert.Panics(t, func() {
+					animation.New(animation.Loop, frame)
+				})
+			})
+		}
+	})
+	t.Run("should create animation starting at first frame", func(t *testing.T) {
+		frames := newFrames(2, time.Second)
+		// when
+		anim := animation.New(animation.Loop, frames...)
+		// then
+		require.NotNil(t, anim)
+		assert.Equal(t, frames[0].Selection, anim.CurrentFrame())
+		assert.Equal(t, 0, anim.CurrentFrameIndex())
+		assert.False(t, anim.Finished())
+	})
+	t.Run("should not be affected by changes in passed slice", func(t *testing.T) {
+		frames := newFrames(2, time.Second)
+		anim := animation.New(animation.Loop, frames...)
+		expected := frames[0].Selection
+		// when
+		frames[0] = frames[1]
+		// then
+		assert.Equal(t, expected, anim.CurrentFrame())
+	})
+}
+
+func TestAnimation_Update(t *testing.T) {
+	tests := map[string]struct {
+		mode           animation.Mode
+		frames         int
+		updates        []time.Duration
+		expectedFrames []int
+		expectedFinish bool
+	}{
+		"less than frame duration": {
+			mode:           animation.Loop,
+			frames:         3,
+			updates:        []time.Duration{99 * time.Millisecond},
+			expectedFrames: []int{0},
+		},
+		"accumulate time": {
+			mode:           animation.Loop,
+			frames:         3,
+			updates:        []time.Duration{50 * time.Millisecond, 50 * time.Millisecond},
+			expectedFrames: []int{0, 1},
+		},
+		"zero time": {
+			mode:           animation.Loop,
+			frames:         3,
+			updates:        []time.Duration{0},
+			expectedFrames: []int{0},
+		},
+		"negative time": {
+			mode:           animation.Loop,
+			frames:         3,
+			updates:        []time.Duration{-time.Second},
+			expectedFrames: []int{0},
+		},
+		"loop": {
+			mode:   animation.Loop,
+			frames: 3,
+			updates: []time.Duration{
+				100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond,
+				100 * time.Millisecond,
+			},
+			expectedFrames: []int{1, 2, 0, 1},
+		},
+		"loop skipping frames": {
+			mode:           animation.Loop,
+			frames:         3,
+			updates:        []time.Duration{250 * time.Millisecond, 100 * time.Millisecond},
+			expectedFrames: []int{2, 0},
+		},
+		"loop many cycles": {
+			mode:           animation.Loop,
+			frames:         3,
+			updates:        []time.Duration{time.Hour + 150*time.Millisecond, 50 * time.Millisecond},
+			expectedFrames: []int{1, 2},
+		},
+		"ping pong": {
+			mode:   animation.PingPong,
+			frames: 3,
+			updates: []time.Duration{
+				100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond,
+				100 * time.Millisecond, 100 * time.Millisecond,
+			},
+			expectedFrames: []int{1, 2, 1, 0, 1},
+		},
+		"ping pong many cycles": {
+			mode:           animation.PingPong,
+			frames:         3,
+			updates:        []time.Duration{time.Hour + 300*time.Millisecond},
+			expectedFrames: []int{1},
+		},
+		"ping pong single frame": {
+			mode:           animation.PingPong,
+			frames:         1,
+			updates:        []time.Duration{100 * time.Millisecond, time.Hour},
+			expectedFrames: []int{0, 0},
+		},
+		"once": {
+			mode:   animation.Once,
+			frames: 3,
+			updates: []time.Duration{
+				100 * time.Millisecond, 100 * time.Millisecond, 99 * time.Millisecond,
+			},
+			expectedFrames: []int{1, 2, 2},
+		},
+		"once finished": {
+			mode:           animation.Once,
+			frames:         3,
+			updates:        []time.Duration{time.Hour},
+			expectedFrames: []int{2},
+			expectedFinish: true,
+		},
+		"once finished exactly at the end of last frame": {
+			mode:           animation.Once,
+			frames:         2,
+			updates:        []time.Duration{100 * time.Millisecond, 100 * time.Millisecond},
+			expectedFrames: []int{1, 1},
+			expectedFinish: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			frames := newFrames(test.frames, 100*time.Millisecond)
+			anim := animation.New(test.mode, frames...)
+			for i, elapsed := range test.updates {
+				// when
+				anim.Update(elapsed)
+				// then
+				expectedFrame := test.expectedFrames[i]
+				assert.Equal(t, expectedFrame, anim.CurrentFrameIndex(), "update %d", i)
+				assert.Equal(t, frames[expectedFrame].Selection, anim.CurrentFrame(), "update %d", i)
+			}
+			assert.Equal(t, test.expectedFinish, anim.Finished())
+		})
+	}
+	t.Run("should use duration of each frame", func(t *testing.T) {
+		frames := newFrames(2, time.Second)
+		frames[0].Duration = 10 * time.Millisecond
+		anim := animation.New(animation.Loop, frames...)
+		// when
+		anim.Update(10 * time.Millisecond)
+		// then
+		assert.Equal(t, 1, anim.CurrentFrameIndex())
+		// when
+		anim.Update(999 * time.Millisecond)
+		// then
+		assert.Equal(t, 1, anim.CurrentFrameIndex())
+	})
+}
+
+func TestAnimation_Reset(t *testing.T) {
+	t.Run("should rewind finished animation", func(t *testing.T) {
+		anim := animation.New(animation.Once, newFrames(2, time.Second)...)
+		anim.Update(time.Hour)
+		// when
+		anim.Reset()
+		// then
+		assert.Equal(t, 0, anim.CurrentFrameIndex())
+		assert.False(t, anim.Finished())
+	})
+	t.Run("should reset elapsed time and direction", func(t *testing.T) {
+		anim := animation.New(animation.PingPong, newFrames(3, time.Second)...)
+		anim.Update(2500 * time.Millisecond)
+		// when
+		anim.Reset()
+		anim.Update(time.Second)
+		// then
+		assert.Equal(t, 1, anim.CurrentFrameIndex())
+		// when
+		anim.Update(time.Second)
+		// then
+		assert.Equal(t, 2, anim.CurrentFrameIndex())
+	})
+}
+
+func TestSpriteSheetFrames(t *testing.T) {
+	t.Run("should panic when frame size is not positive", func(t *testing.T) {
+		sizes := [][2]int{{0, 1}, {1, 0}, {-1, 1}, {1, -1}}
+		for _, size := range sizes {
+			assert.Panics(t, func() {
+				animation.SpriteSheetFrames(newSheet(2, 2), size[0], size[1], time.Second)
+			})
+		}
+	})
+	t.Run("should split sheet row by row", func(t *testing.T) {
+		sheet := newSheet(5, 5).Selection(1, 1).WithSize(4, 5)
+		// when
+		frames := animation.SpriteSheetFrames(sheet, 2, 2, time.Second)
+		// then
+		require.Len(t, frames, 4)
+		expectedPositions := [][2]int{{1, 1}, {3, 1}, {1, 3}, {3, 3}}
+		for i, frame := range frames {
+			assert.Equal(t, expectedPositions[i][0], frame.Selection.ImageX())
+			assert.Equal(t, expectedPositions[i][1], frame.Selection.ImageY())
+			assert.Equal(t, 2, frame.Selection.Width())
+			assert.Equal(t, 2, frame.Selection.Height())
+			assert.Equal(t, time.Second, frame.Duration)
+		}
+	})
+	t.Run("should return no frames when sheet is smaller than frame", func(t *testing.T) {
+		frames := animation.SpriteSheetFrames(newSheet(1, 1), 2, 2, time.Second)
+		assert.Empty(t, frames)
+	})
+}
+
+func newSheet(width, height int) image.Selection {
+	return image.New(fake.NewAcceleratedImage(width, height)).WholeImageSelection()
+}
+
+// newFrames returns frames with distinct selections
+func newFrames(count int, duration time.Duration) []animation.Frame {
+	return animation.SpriteSheetFrames(newSheet(count, 1), 1, 1, duration)
+}
@@ -0,0 +1,67 @@
+package blend
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// NewAdd (aka Additive or Linear Dodge) creates a new blending tool which adds
+// source and target colors. Components are saturated at 255.
+func NewAdd() *Add {
+	return &Add{lines: newLines(add)}
+}
+
+// Add is a blending tool which adds source and target colors.
+type Add struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (a *Add) BlendSourceToTarget(source, target image.Selection) {
+	a.lines.blendSourceToTarget(source, target)
+}
+
+func add(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, srcA := s.RGBAi()
+		dstR, dstG, dstB, dstA := target[i].RGBAi()
+		target[i] = image.RGBAi(
+			minInt(srcR+dstR, 255),
+			minInt(srcG+dstG, 255),
+			minInt(srcB+dstB, 255),
+			minInt(srcA+dstA, 255),
+		)
+	}
+}
+
+// NewSubtract creates a new blending tool which subtracts source colors from
+// target colors. Components are saturated at 0. Target alpha is preserved.
+func NewSubtract() *Subtract {
+	return &Subtract{lines: newLines(subtract)}
+}
+
+// Subtract is a blending tool which subtracts source colors from target colors.
+type Subtract struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *Subtract) BlendSourceToTarget(source, target image.Selection) {
+	s.lines.blendSourceToTarget(source, target)
+}
+
+func subtract(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, _ := s.RGBAi()
+		dstR, dstG, dstB, dstA := target[i].RGBAi()
+		target[i] = image.RGBAi(
+			maxInt(dstR-srcR, 0),
+			maxInt(dstG-srcG, 0),
+			maxInt(dstB-srcB, 0),
+			dstA,
+		)
+	}
+}
@@ -6,6 +6,7 @@ package blend
 
 import (
 	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/parallel"
 )
 
 // ColorBlender blends source and target colors together. It is executed by Tool
@@ -14,13 +15,37 @@ type ColorBlender interface {
 	BlendSourceToTargetColor(source, target image.Color) image.Color
 }
 
+// LineBlender blends source and target lines together. It is executed by Tool
+// for each line in source and target selection. It is much faster than ColorBlender,
+// because there is no function call for each pixel.
+type LineBlender interface {
+	// BlendSourceToTargetLine blends source colors into target colors. Both
+	// slices have the same length. Source pixels outside the source image are
+	// passed as transparent colors. Implementations must not retain slices.
+	BlendSourceToTargetLine(source, target []image.Color)
+}
+
 // New creates a blending Tool with given ColorBlender implementation.
 func New(colorBlender ColorBlender) *Tool {
 	if colorBlender == nil {
 		panic("nil colorBlender")
 	}
 	return &Tool{
-		colorBlender: colorBlender,
+		lines: newLines(func(source, target []image.Color) {
+			for i, sourceColor := range source {
+				target[i] = colorBlender.BlendSourceToTargetColor(sourceColor, target[i])
+			}
+		}),
+	}
+}
+
+// NewWithLineBlender creates a blending Tool with given LineBlender implementation.
+func NewWithLineBlender(lineBlender LineBlender) *Tool {
+	if lineBlender == nil {
+		panic("nil lineBlender")
+	}
+	return &Tool{
+		lines: newLines(lineBlender.BlendSourceToTargetLine),
 	}
 }
 
@@ -32,12 +57,24 @@ func NewSource() *Source {
 
 // Source is a blending tool which replaces target selection with source
 // colors. It is like coping of source selection colors into target.
-type Source struct{}
+type Source struct {
+	transform Transform
+}
+
+// SetTransform sets the transformation (such as flip or rotation) of the source
+// applied during blending. Default is NoTransform.
+func (s *Source) SetTransform(transform Transform) {
+	s.transform = transform
+}
 
 // BlendSourceToTarget blends source into target selection.
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
 func (s *Source) BlendSourceToTarget(source, target image.Selection) {
+	if s.transform != NoTransform {
+		blendTransformed(source, target, s.transform, replaceColor)
+		return
+	}
 	source = clampSourceToTargetImage(source, target)
 	target = target.WithSize(source.Width(), source.Height())
 	var (
@@ -75,6 +112,10 @@ func (s *Source) BlendSourceToTarget(source, target image.Selection) {
 	}
 }
 
+func replaceColor(source, _ image.Color) image.Color {
+	return source
+}
+
 func clampSourceToTargetImage(source image.Selection, target image.Selection) image.Selection {
 	width := source.Width()
 	if width+target.ImageX() > target.Image().Width() {
@@ -90,6 +131,9 @@ func clampSourceToTargetImage(source image.Selection, target image.Selection) im
 // NewSourceOver creates a new blending tool which blends together source and target
 // taking into account alpha channel of both. Source-over means that source will be
 // painted on top of the target.
+//
+// By default source colors are used as they are. Use SetOpacity and SetTint to
+// modify them during blending.
 func NewSourceOver() *SourceOver {
 	return &SourceOver{}
 }
@@ -97,13 +141,89 @@ func NewSourceOver() *SourceOver {
 // SourceOver (aka Normal) is a blending tool which blends together source and target
 // taking into account alpha channel of both. Source-over means that source will be
 // painted on top of the target.
-type SourceOver struct{}
+//
+// The zero value is ready to use.
+type SourceOver struct {
+	pool      *parallel.Pool
+	transform Transform
+	// inverted opacity and tint are stored, so the zero value does not change
+	// source colors
+	transparency byte
+	inverseTint  image.Color
+	// modulated is true when opacity or tint changes source colors
+	modulated                                          bool
+	modulationR, modulationG, modulationB, modulationA int
+	// fields below are reused by BlendSourceToTarget when the pool is set
+	// to avoid allocations
+	pairs                        []linePair
+	sourceXOffset, targetXOffset int
+	blendPairs                   func(start, end int)
+}
+
+// SetOpacity sets the opacity of the source, where 0 is fully transparent and
+// 255 (default) is fully opaque. Source colors (including alpha) are multiplied by
+// opacity/255 before blending.
+func (s *SourceOver) SetOpacity(opacity byte) {
+	s.transparency = 255 - opacity
+	s.updateModulation()
+}
+
+// SetTint sets the color which is multiplied with each source color before blending.
+// Default tint is opaque white, which does not change source colors. Tint is
+// a premultiplied color, therefore translucent tint makes the source translucent too.
+func (s *SourceOver) SetTint(tint image.Color) {
+	r, g, b, a := tint.RGBA()
+	s.inverseTint = image.RGBA(255-r, 255-g, 255-b, 255-a)
+	s.updateModulation()
+}
+
+// SetPool sets the pool of goroutines used for blending. Lines of the target are
+// split into continuous chunks and blended in parallel. Lines are taken from
+// the images (which marks the target image as modified in RAM) in the goroutine
+// calling BlendSourceToTarget, before any worker starts. BlendSourceToTarget
+// returns when all workers are done. Source and target selections must not overlap.
+//
+// Pool is not used when the transform is set. By default (nil pool) blending
+// is done in the current goroutine.
+//
+// SourceOver with a pool set must not be used from many goroutines at the same
+// time.
+func (s *SourceOver) SetPool(pool *parallel.Pool) {
+	s.pool = pool
+	if s.blendPairs == nil {
+		s.blendPairs = func(start, end int) {
+			for _, pair := range s.pairs[start:end] {
+				s.blendLine(pair.source, pair.target, s.sourceXOffset, s.targetXOffset)
+			}
+		}
+	}
+}
+
+// SetTransform sets the transformation (such as flip or rotation) of the source
+// applied during blending. Default is NoTransform.
+func (s *SourceOver) SetTransform(transform Transform) {
+	s.transform = transform
+}
+
+func (s *SourceOver) updateModulation() {
+	opacity := 255 - int(s.transparency)
+	inverseR, inverseG, inverseB, inverseA := s.inverseTint.RGBAi()
+	s.modulationR = mul(255-inverseR, opacity)
+	s.modulationG = mul(255-inverseG, opacity)
+	s.modulationB = mul(255-inverseB, opacity)
+	s.modulationA = mul(255-inverseA, opacity)
+	s.modulated = s.modulationR != 255 || s.modulationG != 255 || s.modulationB != 255 || s.modulationA != 255
+}
 
 // BlendSourceToTarget blends source into target selection. Results will be stored
 // in the image pointed by target selection
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
 func (s *SourceOver) BlendSourceToTarget(source, target image.Selection) {
+	if s.transform != NoTransform {
+		blendTransformed(source, target, s.transform, s.BlendSourceToTargetColor)
+		return
+	}
 	source = clampSourceToTargetImage(source, target)
 	target = target.WithSize(source.Width(), source.Height())
 	var (
@@ -122,35 +242,95 @@ func (s *SourceOver) BlendSourceToTarget(source, target image.Selection) {
 	if startY < sourceYOffset {
 		startY = sourceYOffset
 	}
+	if s.pool == nil || startY >= height {
+		for y := startY; y < height; y++ {
+			sourceLine := sourceLines.LineForRead(y - sourceYOffset)
+			targetLine := targetLines.LineForWrite(y - targetYOffset)
+			s.blendLine(sourceLine, targetLine, sourceXOffset, targetXOffset)
+		}
+		return
+	}
+	// lines are taken in the current goroutine, so the image is not accessed
+	// by workers
 	for y := startY; y < height; y++ {
-		sourceLine := sourceLines.LineForRead(y - sourceYOffset)
-		targetLine := targetLines.LineForWrite(y - targetYOffset)
-		for x := targetXOffset + sourceXOffset; x < len(sourceLine); x++ {
-			// blend source with target color (following block of code is inlined to improve performance)
-			source := sourceLine[x-sourceXOffset]
-			target := targetLine[x-targetXOffset]
-			srcR, srcG, srcB, srcA := source.RGBAi()
-			dstR, dstG, dstB, dstA := target.RGBAi()
-			dstFactor := 255 - srcA
-			outR := srcR + mul(dstR, dstFactor)
-			outG := srcG + mul(dstG, dstFactor)
-			outB := srcB + mul(dstB, dstFactor)
-			outA := srcA + mul(dstA, dstFactor)
-			targetLine[x-targetXOffset] = image.RGBAi(outR, outG, outB, outA)
+		s.pairs = append(s.pairs, linePair{
+			source: sourceLines.LineForRead(y - sourceYOffset),
+			target: targetLines.LineForWrite(y - targetYOffset),
+		})
+	}
+	s.sourceXOffset, s.targetXOffset = sourceXOffset, targetXOffset
+	s.pool.Run(len(s.pairs), s.blendPairs)
+	// do not retain image pixels
+	for i := range s.pairs {
+		s.pairs[i] = linePair{}
+	}
+	s.pairs = s.pairs[:0]
+}
+
+func (s *SourceOver) blendLine(sourceLine, targetLine []image.Color, sourceXOffset, targetXOffset int) {
+	for x := targetXOffset + sourceXOffset; x < len(sourceLine); x++ {
+		// blend source with target color (following block of code is inlined to improve performance)
+		source := sourceLine[x-sourceXOffset]
+		target := targetLine[x-targetXOffset]
+		srcR, srcG, srcB, srcA := source.RGBAi()
+		if s.modulated {
+			srcR = mul(srcR, s.modulationR)
+			srcG = mul(srcG, s.modulationG)
+			srcB = mul(srcB, s.modulationB)
+			srcA = mul(srcA, s.modulationA)
 		}
+		dstR, dstG, dstB, dstA := target.RGBAi()
+		dstFactor := 255 - srcA
+		outR := srcR + mul(dstR, dstFactor)
+		outG := srcG + mul(dstG, dstFactor)
+		outB := srcB + mul(dstB, dstFactor)
+		outA := srcA + mul(dstA, dstFactor)
+		targetLine[x-targetXOffset] = image.RGBAi(outR, outG, outB, outA)
 	}
 }
 
+// linePair contains source and target lines processed together
+type linePair struct {
+	source, target []image.Color
+}
+
+// BlendSourceToTargetColor blends a single source color into target color,
+// taking into account opacity and tint. It makes SourceOver a ColorBlender.
+func (s *SourceOver) BlendSourceToTargetColor(source, target image.Color) image.Color {
+	srcR, srcG, srcB, srcA := source.RGBAi()
+	if s.modulated {
+		srcR = mul(srcR, s.modulationR)
+		srcG = mul(srcG, s.modulationG)
+		srcB = mul(srcB, s.modulationB)
+		srcA = mul(srcA, s.modulationA)
+	}
+	dstR, dstG, dstB, dstA := target.RGBAi()
+	dstFactor := 255 - srcA
+	return image.RGBAi(
+		srcR+mul(dstR, dstFactor),
+		srcG+mul(dstG, dstFactor),
+		srcB+mul(dstB, dstFactor),
+		srcA+mul(dstA, dstFactor),
+	)
+}
+
 // mul is an optimized version of round(a * b / 255)
 func mul(a, b int) int {
 	t := a*b + 0x80
 	return ((t >> 8) + t) >> 8
 }
 
+// mulAdd returns (a*b + c*d)/255 rounded once, the same way as video card does.
+// The sum must not exceed 255*255.
+func mulAdd(a, b, c, d int) int {
+	t := a*b + c*d + 0x80
+	return ((t >> 8) + t) >> 8
+}
+
 // Tool is a customizable blending tool which blends together two selections. It uses
-// ColorBlender implementation for actual blending of two pixel colors.
+// ColorBlender or LineBlender implementation for actual blending of colors.
 type Tool struct {
-	colorBlender ColorBlender
+	lines lines
 }
 
 // BlendSourceToTarget blends source into target selection. Results will be stored
@@ -158,12 +338,5 @@ type Tool struct {
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
 func (t *Tool) BlendSourceToTarget(source, target image.Selection) {
-	for y := 0; y < source.Height(); y++ {
-		for x := 0; x < source.Width(); x++ {
-			sourceColor := source.Color(x, y)
-			targetColor := target.Color(x, y)
-			color := t.colorBlender.BlendSourceToTargetColor(sourceColor, targetColor)
-			target.SetColor(x, y, color)
-		}
-	}
+	t.lines.blendSourceToTarget(source, target)
 }
@@ -1,11 +1,13 @@
 package blend_test
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/jacekolszak/pixiq/blend"
 	"github.com/jacekolszak/pixiq/image"
 	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/parallel"
 )
 
 var resolutions = map[string]struct {
@@ -56,8 +58,54 @@ func BenchmarkSourceOver_BlendSourceToTarget(b *testing.B) {
 	}
 }
 
-// 1920x1080 - 34ms
-// 32x32     - 17us
+// Measured on 1 CPU, where the pool has only one worker:
+// 1920x1080 - 18ms (19ms without the pool)
+// 32x32     - 11us (11us without the pool)
+func BenchmarkSourceOver_BlendSourceToTarget_WithPool(b *testing.B) {
+	pool := parallel.NewPool(runtime.NumCPU())
+	defer pool.Close()
+	tool := blend.NewSourceOver()
+	tool.SetPool(pool)
+	for name, resolution := range resolutions {
+		b.Run(name, func(b *testing.B) {
+			source := newImageSelection(resolution.width, resolution.height)
+			target := newImageSelection(resolution.width, resolution.height)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tool.BlendSourceToTarget(source, target)
+			}
+		})
+	}
+}
+
+// Xor      1920x1080 - 17ms
+// Multiply 1920x1080 - 70ms
+func BenchmarkModes_BlendSourceToTarget(b *testing.B) {
+	tools := map[string]interface {
+		BlendSourceToTarget(source, target image.Selection)
+	}{
+		"Xor":      blend.NewXor(),
+		"Multiply": blend.NewMultiply(),
+		"Overlay":  blend.NewOverlay(),
+	}
+	for toolName, tool := range tools {
+		for name, resolution := range resolutions {
+			b.Run(toolName+" "+name, func(b *testing.B) {
+				source := newImageSelection(resolution.width, resolution.height)
+				target := newImageSelection(resolution.width, resolution.height)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					tool.BlendSourceToTarget(source, target)
+				}
+			})
+		}
+	}
+}
+
+// 1920x1080 - 14ms
+// 32x32     - 8us
 func BenchmarkTool_BlendSourceToTarget(b *testing.B) {
 	tool := blend.New(blenderStub{})
 	for name, resolution := range resolutions {
@@ -73,6 +121,23 @@ func BenchmarkTool_BlendSourceToTarget(b *testing.B) {
 	}
 }
 
+// 1920x1080 - 1ms
+// 32x32     - 1us
+func BenchmarkTool_BlendSourceToTarget_WithLineBlender(b *testing.B) {
+	tool := blend.NewWithLineBlender(blenderStub{})
+	for name, resolution := range resolutions {
+		b.Run(name, func(b *testing.B) {
+			source := newImageSelection(resolution.width, resolution.height)
+			target := newImageSelection(resolution.width, resolution.height)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tool.BlendSourceToTarget(source, target)
+			}
+		})
+	}
+}
+
 type blenderStub struct {
 }
 
@@ -80,6 +145,10 @@ func (b blenderStub) BlendSourceToTargetColor(source, target image.Color) image.
 	return source
 }
 
+func (b blenderStub) BlendSourceToTargetLine(source, target []image.Color) {
+	copy(target, source)
+}
+
 func newImageSelection(width, height int) image.Selection {
 	img := image.New(fake.NewAcceleratedImage(width, height))
 	selection := img.WholeImageSelection()
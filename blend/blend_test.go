@@ -8,6 +8,7 @@ import (
 	"github.com/jacekolszak/pixiq/blend"
 	"github.com/jacekolszak/pixiq/image"
 	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/parallel"
 )
 
 func TestNew(t *testing.T) {
@@ -22,7 +23,23 @@ func TestNew(t *testing.T) {
 	})
 }
 
+func TestNewWithLineBlender(t *testing.T) {
+	t.Run("should panic when lineBlender is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			blend.NewWithLineBlender(nil)
+		})
+	})
+	t.Run("should create tool", func(t *testing.T) {
+		tool := blend.NewWithLineBlender(multiplyLines{})
+		assert.NotNil(t, tool)
+	})
+}
+
 func TestBlendSourceToTarget(t *testing.T) {
+	pool := parallel.NewPool(3)
+	defer pool.Close()
+	sourceOverWithPool := blend.NewSourceOver()
+	sourceOverWithPool.SetPool(pool)
 	var (
 		color1 = image.RGBA(1, 2, 3, 4)
 		color2 = image.RGBA(5, 6, 7, 8)
@@ -44,6 +61,13 @@ func TestBlendSourceToTarget(t *testing.T) {
 			color3x4: image.RGBA(54, 70, 88, 108),
 			colorTx2: image.Transparent,
 		},
+		"Tool with LineBlender": {
+			tool:     blend.NewWithLineBlender(multiplyLines{}),
+			color1x2: image.RGBA(5, 12, 21, 32),
+			color1x3: image.RGBA(9, 20, 33, 48),
+			color3x4: image.RGBA(54, 70, 88, 108),
+			colorTx2: image.Transparent,
+		},
 		"Source": {
 			tool:     blend.NewSource(),
 			color1x2: color1,
@@ -58,6 +82,13 @@ func TestBlendSourceToTarget(t *testing.T) {
 			color3x4: image.RGBA(15, 17, 19, 21),
 			colorTx2: color2,
 		},
+		"SourceOver with pool": {
+			tool:     sourceOverWithPool,
+			color1x2: image.RGBA(6, 8, 10, 12),
+			color1x3: image.RGBA(10, 12, 14, 16),
+			color3x4: image.RGBA(15, 17, 19, 21),
+			colorTx2: color2,
+		},
 	}
 	for name, blender := range blenders {
 
@@ -526,6 +557,166 @@ func TestSourceOver_BlendSourceToTarget(t *testing.T) {
 	})
 }
 
+func TestSourceOver_SetOpacity(t *testing.T) {
+	tests := map[string]struct {
+		opacity  byte
+		source   image.Color
+		target   image.Color
+		expected image.Color
+	}{
+		"opaque": {
+			opacity:  255,
+			source:   image.RGB(200, 100, 50),
+			target:   image.RGB(0, 0, 255),
+			expected: image.RGB(200, 100, 50),
+		},
+		"transparent": {
+			opacity:  0,
+			source:   image.RGB(200, 100, 50),
+			target:   image.RGB(0, 0, 255),
+			expected: image.RGB(0, 0, 255),
+		},
+		"half transparent": {
+			opacity:  128,
+			source:   image.RGB(200, 100, 50),
+			target:   image.RGB(0, 0, 255),
+			expected: image.RGB(100, 50, 152),
+		},
+		"half transparent on transparent target": {
+			opacity:  128,
+			source:   image.RGB(200, 100, 50),
+			target:   image.Transparent,
+			expected: image.RGBA(100, 50, 25, 128),
+		},
+		"half transparent source": {
+			opacity:  128,
+			source:   image.RGBA(100, 50, 0, 128),
+			target:   image.Transparent,
+			expected: image.RGBA(50, 25, 0, 64),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			source := newImage([][]image.Color{{test.source}}).WholeImageSelection()
+			target := newImage([][]image.Color{{test.target}}).WholeImageSelection()
+			tool := blend.NewSourceOver()
+			tool.SetOpacity(test.opacity)
+			// when
+			tool.BlendSourceToTarget(source, target)
+			// then
+			assert.Equal(t, test.expected, target.Color(0, 0))
+			assert.Equal(t, test.source, source.Color(0, 0))
+		})
+	}
+}
+
+func TestSourceOver_SetTint(t *testing.T) {
+	tests := map[string]struct {
+		tint     image.Color
+		opacity  byte
+		source   image.Color
+		target   image.Color
+		expected image.Color
+	}{
+		"white": {
+			tint:     image.RGB(255, 255, 255),
+			opacity:  255,
+			source:   image.RGB(200, 100, 50),
+			target:   image.RGB(0, 0, 255),
+			expected: image.RGB(200, 100, 50),
+		},
+		"red": {
+			tint:     image.RGB(255, 0, 0),
+			opacity:  255,
+			source:   image.RGB(200, 100, 50),
+			target:   image.RGB(0, 0, 255),
+			expected: image.RGB(200, 0, 0),
+		},
+		"gray": {
+			tint:     image.RGB(128, 128, 128),
+			opacity:  255,
+			source:   image.RGB(200, 100, 50),
+			target:   image.Transparent,
+			expected: image.RGB(100, 50, 25),
+		},
+		"translucent": {
+			tint:     image.RGBA(128, 128, 128, 128),
+			opacity:  255,
+			source:   image.RGB(200, 100, 50),
+			target:   image.RGB(0, 0, 255),
+			expected: image.RGB(100, 50, 152),
+		},
+		"with opacity": {
+			tint:     image.RGB(255, 0, 0),
+			opacity:  128,
+			source:   image.RGB(200, 100, 50),
+			target:   image.Transparent,
+			expected: image.RGBA(100, 0, 0, 128),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			source := newImage([][]image.Color{{test.source}}).WholeImageSelection()
+			target := newImage([][]image.Color{{test.target}}).WholeImageSelection()
+			tool := blend.NewSourceOver()
+			tool.SetTint(test.tint)
+			tool.SetOpacity(test.opacity)
+			// when
+			tool.BlendSourceToTarget(source, target)
+			// then
+			assert.Equal(t, test.expected, target.Color(0, 0))
+		})
+	}
+}
+
+func TestSourceOver_ZeroValue(t *testing.T) {
+	var (
+		sourceColor = image.RGB(200, 100, 50)
+		targetColor = image.RGB(0, 0, 255)
+	)
+	tests := map[string]struct {
+		configure func(tool *blend.SourceOver)
+		expected  image.Color
+	}{
+		"no options": {
+			configure: func(tool *blend.SourceOver) {},
+			expected:  sourceColor,
+		},
+		"opacity": {
+			configure: func(tool *blend.SourceOver) {
+				tool.SetOpacity(128)
+			},
+			expected: image.RGB(100, 50, 152),
+		},
+		"tint": {
+			configure: func(tool *blend.SourceOver) {
+				tool.SetTint(image.RGB(255, 0, 0))
+			},
+			expected: image.RGB(200, 0, 0),
+		},
+		"pool": {
+			configure: func(tool *blend.SourceOver) {
+				pool := parallel.NewPool(2)
+				t.Cleanup(pool.Close)
+				tool.SetPool(pool)
+			},
+			expected: sourceColor,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			source := newImage([][]image.Color{{sourceColor}}).WholeImageSelection()
+			target := newImage([][]image.Color{{targetColor}}).WholeImageSelection()
+			var tool blend.SourceOver
+			test.configure(&tool)
+			// when
+			tool.BlendSourceToTarget(source, target)
+			// then
+			assert.Equal(t, test.expected, target.Color(0, 0))
+		})
+	}
+}
+
 type multiplyColors struct{}
 
 func (c multiplyColors) BlendSourceToTargetColor(source, target image.Color) image.Color {
@@ -536,6 +727,14 @@ func (c multiplyColors) BlendSourceToTargetColor(source, target image.Color) ima
 		source.A()*target.A())
 }
 
+type multiplyLines struct{}
+
+func (l multiplyLines) BlendSourceToTargetLine(source, target []image.Color) {
+	for i := range source {
+		target[i] = multiplyColors{}.BlendSourceToTargetColor(source[i], target[i])
+	}
+}
+
 func assertColors(t *testing.T, img *image.Image, expectedColorLines [][]image.Color) {
 	selection := img.WholeImageSelection()
 	for y := 0; y < selection.Height(); y++ {
@@ -0,0 +1,84 @@
+package blend
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// lineBlender blends source pixels into target pixels. Both slices have the
+// same length.
+type lineBlender func(source, target []image.Color)
+
+// lines executes lineBlender for each line of source and target selections.
+// Source pixels outside the source image are passed as transparent colors.
+type lines struct {
+	blend       lineBlender
+	transparent []image.Color
+}
+
+func newLines(blend lineBlender) lines {
+	return lines{blend: blend}
+}
+
+func (l *lines) blendSourceToTarget(source, target image.Selection) {
+	source = clampSourceToTargetImage(source, target)
+	target = target.WithSize(source.Width(), source.Height())
+	if horizontallyOutsideImage(target) {
+		return
+	}
+	var (
+		sourceLines     = source.Lines()
+		targetLines     = target.Lines()
+		sourceXOffset   = sourceLines.XOffset()
+		sourceYOffset   = sourceLines.YOffset()
+		targetXOffset   = targetLines.XOffset()
+		targetYOffset   = targetLines.YOffset()
+		sourceInvisible = horizontallyOutsideImage(source)
+	)
+	for y := 0; y < targetLines.Length(); y++ {
+		targetLine := targetLines.LineForWrite(y)
+		var sourceLine []image.Color
+		sourceY := y + targetYOffset - sourceYOffset
+		if !sourceInvisible && sourceY >= 0 && sourceY < sourceLines.Length() {
+			sourceLine = sourceLines.LineForRead(sourceY)
+		}
+		var (
+			targetStart = targetXOffset
+			targetEnd   = targetXOffset + len(targetLine)
+			sourceStart = clamp(sourceXOffset, targetStart, targetEnd)
+			sourceEnd   = clamp(sourceXOffset+len(sourceLine), sourceStart, targetEnd)
+		)
+		l.blendTransparent(targetLine[:sourceStart-targetStart])
+		if sourceStart < sourceEnd {
+			l.blend(
+				sourceLine[sourceStart-sourceXOffset:sourceEnd-sourceXOffset],
+				targetLine[sourceStart-targetStart:sourceEnd-targetStart],
+			)
+		}
+		l.blendTransparent(targetLine[sourceEnd-targetStart:])
+	}
+}
+
+func (l *lines) blendTransparent(target []image.Color) {
+	if len(target) == 0 {
+		return
+	}
+	if len(l.transparent) < len(target) {
+		l.transparent = make([]image.Color, len(target))
+	}
+	l.blend(l.transparent[:len(target)], target)
+}
+
+func horizontallyOutsideImage(selection image.Selection) bool {
+	x := selection.ImageX()
+	return x >= selection.Image().Width() || x+selection.Width() <= 0
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,247 @@
+package blend_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/blend"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+type blender interface {
+	BlendSourceToTarget(source, target image.Selection)
+}
+
+var modes = map[string]func() blender{
+	"DestinationOver": func() blender { return blend.NewDestinationOver() },
+	"SourceIn":        func() blender { return blend.NewSourceIn() },
+	"SourceOut":       func() blender { return blend.NewSourceOut() },
+	"SourceAtop":      func() blender { return blend.NewSourceAtop() },
+	"Xor":             func() blender { return blend.NewXor() },
+	"Clear":           func() blender { return blend.NewClear() },
+	"Multiply":        func() blender { return blend.NewMultiply() },
+	"Screen":          func() blender { return blend.NewScreen() },
+	"Overlay":         func() blender { return blend.NewOverlay() },
+	"Darken":          func() blender { return blend.NewDarken() },
+	"Lighten":         func() blender { return blend.NewLighten() },
+	"Add":             func() blender { return blend.NewAdd() },
+	"Subtract":        func() blender { return blend.NewSubtract() },
+	"Difference":      func() blender { return blend.NewDifference() },
+}
+
+func TestModes_BlendSourceToTarget(t *testing.T) {
+	var (
+		red             = image.RGB(255, 0, 0)
+		blue            = image.RGB(0, 0, 255)
+		halfRed         = image.RGBA(127, 0, 0, 127)
+		halfTransparent = image.RGBA(0, 0, 0, 127)
+		orange          = image.RGB(255, 128, 0)
+		violet          = image.RGB(128, 128, 255)
+		darkGray        = image.RGB(64, 64, 64)
+		darkOrange      = image.RGB(100, 50, 0)
+		lightBlue       = image.RGB(100, 100, 255)
+	)
+	type blendCase struct {
+		source, target, expected image.Color
+	}
+	tests := map[string][]blendCase{
+		"DestinationOver": {
+			{source: red, target: image.Transparent, expected: red},
+			{source: red, target: blue, expected: blue},
+			{source: red, target: halfTransparent, expected: image.RGBA(128, 0, 0, 255)},
+		},
+		"SourceIn": {
+			{source: red, target: blue, expected: red},
+			{source: red, target: image.Transparent, expected: image.Transparent},
+			{source: red, target: halfTransparent, expected: halfRed},
+		},
+		"SourceOut": {
+			{source: red, target: blue, expected: image.Transparent},
+			{source: red, target: image.Transparent, expected: red},
+			{source: red, target: halfTransparent, expected: image.RGBA(128, 0, 0, 128)},
+		},
+		"SourceAtop": {
+			{source: red, target: blue, expected: red},
+			{source: red, target: image.Transparent, expected: image.Transparent},
+			{source: halfRed, target: blue, expected: image.RGB(127, 0, 128)},
+		},
+		"Xor": {
+			{source: red, target: blue, expected: image.Transparent},
+			{source: red, target: image.Transparent, expected: red},
+			{source: image.Transparent, target: blue, expected: blue},
+		},
+		"Clear": {
+			{source: red, target: blue, expected: image.Transparent},
+			{source: image.Transparent, target: blue, expected: image.Transparent},
+		},
+		"Multiply": {
+			{source: orange, target: violet, expected: image.RGB(128, 64, 0)},
+			{source: image.Transparent, target: violet, expected: violet},
+			{source: orange, target: image.Transparent, expected: orange},
+			{source: halfRed, target: blue, expected: image.RGB(0, 0, 128)},
+		},
+		"Screen": {
+			{source: orange, target: violet, expected: image.RGB(255, 192, 255)},
+			{source: image.Transparent, target: violet, expected: violet},
+			{source: orange, target: image.Transparent, expected: orange},
+		},
+		"Overlay": {
+			{source: orange, target: violet, expected: image.RGB(255, 129, 255)},
+			{source: orange, target: darkGray, expected: image.RGB(128, 64, 0)},
+			{source: image.Transparent, target: violet, expected: violet},
+			{source: orange, target: image.Transparent, expected: orange},
+		},
+		"Darken": {
+			{source: orange, target: violet, expected: image.RGB(128, 128, 0)},
+			{source: image.Transparent, target: violet, expected: violet},
+			{source: orange, target: image.Transparent, expected: orange},
+		},
+		"Lighten": {
+			{source: orange, target: violet, expected: image.RGB(255, 128, 255)},
+			{source: image.Transparent, target: violet, expected: violet},
+			{source: orange, target: image.Transparent, expected: orange},
+		},
+		"Add": {
+			{source: darkOrange, target: lightBlue, expected: image.RGB(200, 150, 255)},
+			{source: orange, target: violet, expected: image.RGB(255, 255, 255)},
+			{source: halfRed, target: halfTransparent, expected: image.RGBA(127, 0, 0, 254)},
+			{source: image.Transparent, target: violet, expected: violet},
+			{source: orange, target: image.Transparent, expected: orange},
+		},
+		"Subtract": {
+			{source: darkOrange, target: lightBlue, expected: image.RGB(0, 50, 255)},
+			{source: image.Transparent, target: violet, expected: violet},
+			{source: orange, target: image.Transparent, expected: image.Transparent},
+			{source: halfRed, target: orange, expected: image.RGB(128, 128, 0)},
+		},
+		"Difference": {
+			{source: orange, target: violet, expected: image.RGB(127, 0, 255)},
+			{source: image.Transparent, target: violet, expected: violet},
+			{source: orange, target: image.Transparent, expected: orange},
+		},
+	}
+	for name, newBlender := range modes {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should blend color", func(t *testing.T) {
+				for _, test := range tests[name] {
+					source := newImage([][]image.Color{{test.source}}).WholeImageSelection()
+					target := newImage([][]image.Color{{test.target}}).WholeImageSelection()
+					// when
+					newBlender().BlendSourceToTarget(source, target)
+					// then
+					result := target.Color(0, 0)
+					const delta = 1
+					assert.InDelta(t, test.expected.R(), result.R(), delta, "Red %v x %v", test.source, test.target)
+					assert.InDelta(t, test.expected.G(), result.G(), delta, "Green %v x %v", test.source, test.target)
+					assert.InDelta(t, test.expected.B(), result.B(), delta, "Blue %v x %v", test.source, test.target)
+					assert.InDelta(t, test.expected.A(), result.A(), delta, "Alpha %v x %v", test.source, test.target)
+				}
+			})
+
+			t.Run("should blend selections", func(t *testing.T) {
+				selections := map[string]struct {
+					source, target func(source, target *image.Image) (image.Selection, image.Selection)
+				}{
+					"whole images": {
+						source: func(source, target *image.Image) (image.Selection, image.Selection) {
+							return source.WholeImageSelection(), target.WholeImageSelection()
+						},
+					},
+					"target position": {
+						source: func(source, target *image.Image) (image.Selection, image.Selection) {
+							return source.Selection(0, 0).WithSize(2, 2), target.Selection(1, 2)
+						},
+					},
+					"source partially outside the image": {
+						source: func(source, target *image.Image) (image.Selection, image.Selection) {
+							return source.Selection(-1, -2).WithSize(3, 4), target.WholeImageSelection()
+						},
+					},
+					"source partially outside the image on the right": {
+						source: func(source, target *image.Image) (image.Selection, image.Selection) {
+							return source.Selection(2, 1).WithSize(3, 3), target.WholeImageSelection()
+						},
+					},
+					"source outside the image": {
+						source: func(source, target *image.Image) (image.Selection, image.Selection) {
+							return source.Selection(5, 0).WithSize(2, 2), target.WholeImageSelection()
+						},
+					},
+					"target partially outside the image": {
+						source: func(source, target *image.Image) (image.Selection, image.Selection) {
+							return source.WholeImageSelection(), target.Selection(-1, -2)
+						},
+					},
+					"target outside the image": {
+						source: func(source, target *image.Image) (image.Selection, image.Selection) {
+							return source.WholeImageSelection(), target.Selection(5, 0)
+						},
+					},
+				}
+				for selectionName, selection := range selections {
+					t.Run(selectionName, func(t *testing.T) {
+						sourceImage := newTestPattern(3, 3, 0)
+						targetImage := newTestPattern(4, 4, 1)
+						source, target := selection.source(sourceImage, targetImage)
+						expected := blendPixelByPixel(newBlender(), source, target)
+						// when
+						newBlender().BlendSourceToTarget(source, target)
+						// then
+						assertColors(t, targetImage, expected)
+					})
+				}
+			})
+		})
+	}
+}
+
+// newTestPattern creates image with various colors, including transparent
+// and translucent ones
+func newTestPattern(width, height, seed int) *image.Image {
+	colors := []image.Color{
+		image.RGB(255, 128, 0),
+		image.Transparent,
+		image.RGBA(20, 40, 60, 100),
+		image.RGB(128, 128, 255),
+		image.RGBA(127, 0, 0, 127),
+		image.RGB(10, 20, 30),
+	}
+	img := image.New(fake.NewAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, colors[(x+y*width+seed)%len(colors)])
+		}
+	}
+	return img
+}
+
+// blendPixelByPixel returns expected target image colors by blending each pixel
+// separately
+func blendPixelByPixel(blender blender, source, target image.Selection) [][]image.Color {
+	img := target.Image()
+	expected := make([][]image.Color, img.Height())
+	whole := img.WholeImageSelection()
+	for y := range expected {
+		expected[y] = make([]image.Color, img.Width())
+		for x := range expected[y] {
+			expected[y][x] = whole.Color(x, y)
+		}
+	}
+	for y := 0; y < source.Height(); y++ {
+		for x := 0; x < source.Width(); x++ {
+			targetX := target.ImageX() + x
+			targetY := target.ImageY() + y
+			if targetX < 0 || targetY < 0 || targetX >= img.Width() || targetY >= img.Height() {
+				continue
+			}
+			sourcePixel := newImage([][]image.Color{{source.Color(x, y)}}).WholeImageSelection()
+			targetPixel := newImage([][]image.Color{{target.Color(x, y)}}).WholeImageSelection()
+			blender.BlendSourceToTarget(sourcePixel, targetPixel)
+			expected[targetY][targetX] = targetPixel.Color(0, 0)
+		}
+	}
+	return expected
+}
@@ -0,0 +1,185 @@
+package blend
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// NewDestinationOver creates a new blending tool which paints the source
+// behind the target. Source is visible only where the target is transparent
+// or translucent.
+func NewDestinationOver() *DestinationOver {
+	return &DestinationOver{lines: newLines(destinationOver)}
+}
+
+// DestinationOver is a blending tool which paints the source behind the target.
+type DestinationOver struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (d *DestinationOver) BlendSourceToTarget(source, target image.Selection) {
+	d.lines.blendSourceToTarget(source, target)
+}
+
+func destinationOver(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, srcA := s.RGBAi()
+		dstR, dstG, dstB, dstA := target[i].RGBAi()
+		srcFactor := 255 - dstA
+		target[i] = image.RGBAi(
+			dstR+mul(srcR, srcFactor),
+			dstG+mul(srcG, srcFactor),
+			dstB+mul(srcB, srcFactor),
+			dstA+mul(srcA, srcFactor),
+		)
+	}
+}
+
+// NewSourceIn creates a new blending tool which replaces the target with
+// the source, but only where the target is opaque. Target alpha is used as
+// a mask.
+func NewSourceIn() *SourceIn {
+	return &SourceIn{lines: newLines(sourceIn)}
+}
+
+// SourceIn is a blending tool which replaces the target with the source, but
+// only where the target is opaque.
+type SourceIn struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *SourceIn) BlendSourceToTarget(source, target image.Selection) {
+	s.lines.blendSourceToTarget(source, target)
+}
+
+func sourceIn(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, srcA := s.RGBAi()
+		dstA := int(target[i].A())
+		target[i] = image.RGBAi(mul(srcR, dstA), mul(srcG, dstA), mul(srcB, dstA), mul(srcA, dstA))
+	}
+}
+
+// NewSourceOut creates a new blending tool which replaces the target with
+// the source, but only where the target is transparent. Inverted target alpha
+// is used as a mask.
+func NewSourceOut() *SourceOut {
+	return &SourceOut{lines: newLines(sourceOut)}
+}
+
+// SourceOut is a blending tool which replaces the target with the source, but
+// only where the target is transparent.
+type SourceOut struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *SourceOut) BlendSourceToTarget(source, target image.Selection) {
+	s.lines.blendSourceToTarget(source, target)
+}
+
+func sourceOut(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, srcA := s.RGBAi()
+		dstFactor := 255 - int(target[i].A())
+		target[i] = image.RGBAi(mul(srcR, dstFactor), mul(srcG, dstFactor), mul(srcB, dstFactor), mul(srcA, dstFactor))
+	}
+}
+
+// NewSourceAtop creates a new blending tool which paints the source on top of
+// the target, but only where the target is opaque. Target alpha is preserved.
+func NewSourceAtop() *SourceAtop {
+	return &SourceAtop{lines: newLines(sourceAtop)}
+}
+
+// SourceAtop is a blending tool which paints the source on top of the target,
+// but only where the target is opaque.
+type SourceAtop struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *SourceAtop) BlendSourceToTarget(source, target image.Selection) {
+	s.lines.blendSourceToTarget(source, target)
+}
+
+func sourceAtop(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, srcA := s.RGBAi()
+		dstR, dstG, dstB, dstA := target[i].RGBAi()
+		dstFactor := 255 - srcA
+		target[i] = image.RGBAi(
+			mulAdd(srcR, dstA, dstR, dstFactor),
+			mulAdd(srcG, dstA, dstG, dstFactor),
+			mulAdd(srcB, dstA, dstB, dstFactor),
+			dstA,
+		)
+	}
+}
+
+// NewXor creates a new blending tool which leaves only those parts of source
+// and target which do not overlap.
+func NewXor() *Xor {
+	return &Xor{lines: newLines(xor)}
+}
+
+// Xor is a blending tool which leaves only those parts of source and target
+// which do not overlap.
+type Xor struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (x *Xor) BlendSourceToTarget(source, target image.Selection) {
+	x.lines.blendSourceToTarget(source, target)
+}
+
+func xor(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, srcA := s.RGBAi()
+		dstR, dstG, dstB, dstA := target[i].RGBAi()
+		srcFactor := 255 - dstA
+		dstFactor := 255 - srcA
+		target[i] = image.RGBAi(
+			mulAdd(srcR, srcFactor, dstR, dstFactor),
+			mulAdd(srcG, srcFactor, dstG, dstFactor),
+			mulAdd(srcB, srcFactor, dstB, dstFactor),
+			mulAdd(srcA, srcFactor, dstA, dstFactor),
+		)
+	}
+}
+
+// NewClear creates a new blending tool which makes the target transparent.
+// Source colors are ignored, only the source size is used.
+func NewClear() *Clear {
+	return &Clear{lines: newLines(clearColors)}
+}
+
+// Clear is a blending tool which makes the target transparent.
+type Clear struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (c *Clear) BlendSourceToTarget(source, target image.Selection) {
+	c.lines.blendSourceToTarget(source, target)
+}
+
+func clearColors(_, target []image.Color) {
+	for i := range target {
+		target[i] = image.Transparent
+	}
+}
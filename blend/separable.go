@@ -0,0 +1,210 @@
+package blend
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Separable blend modes mix each color component independently and then
+// composite the result using source-over. For premultiplied components
+// (s - source, d - target, sa - source alpha, da - target alpha) the formula is:
+//
+//	result = s*(1-da) + d*(1-sa) + sa*da*B(d/da, s/sa)
+//	alpha  = sa + da - sa*da
+//
+// where B is a blend function. Functions below return sa*da*B(d/da, s/sa)
+// for components in 0-255 range. Multiply and Screen use simplified formulas
+// which can be executed by video card as well.
+
+// NewMultiply creates a new blending tool which multiplies source and target
+// colors. The result is always darker (or the same).
+func NewMultiply() *Multiply {
+	return &Multiply{lines: newLines(multiply)}
+}
+
+// Multiply is a blending tool which multiplies source and target colors.
+type Multiply struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (m *Multiply) BlendSourceToTarget(source, target image.Selection) {
+	m.lines.blendSourceToTarget(source, target)
+}
+
+// NewScreen creates a new blending tool which multiplies inverted source and
+// target colors. The result is always lighter (or the same).
+func NewScreen() *Screen {
+	return &Screen{lines: newLines(screen)}
+}
+
+// Screen is a blending tool which multiplies inverted source and target colors.
+type Screen struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *Screen) BlendSourceToTarget(source, target image.Selection) {
+	s.lines.blendSourceToTarget(source, target)
+}
+
+// NewOverlay creates a new blending tool which multiplies dark target colors
+// and screens light target colors.
+func NewOverlay() *Overlay {
+	return &Overlay{lines: newLines(separable(func(s, d, sa, da int) int {
+		if 2*d <= da {
+			return 2 * mul(s, d)
+		}
+		return mul(sa, da) - 2*mul(da-d, sa-s)
+	}))}
+}
+
+// Overlay is a blending tool which multiplies dark target colors and screens
+// light target colors.
+type Overlay struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (o *Overlay) BlendSourceToTarget(source, target image.Selection) {
+	o.lines.blendSourceToTarget(source, target)
+}
+
+// NewDarken creates a new blending tool which selects the darker of source and
+// target colors.
+func NewDarken() *Darken {
+	return &Darken{lines: newLines(separable(func(s, d, sa, da int) int {
+		return minInt(mul(s, da), mul(d, sa))
+	}))}
+}
+
+// Darken is a blending tool which selects the darker of source and target colors.
+type Darken struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (d *Darken) BlendSourceToTarget(source, target image.Selection) {
+	d.lines.blendSourceToTarget(source, target)
+}
+
+// NewLighten creates a new blending tool which selects the lighter of source
+// and target colors.
+func NewLighten() *Lighten {
+	return &Lighten{lines: newLines(separable(func(s, d, sa, da int) int {
+		return maxInt(mul(s, da), mul(d, sa))
+	}))}
+}
+
+// Lighten is a blending tool which selects the lighter of source and target colors.
+type Lighten struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (l *Lighten) BlendSourceToTarget(source, target image.Selection) {
+	l.lines.blendSourceToTarget(source, target)
+}
+
+// NewDifference creates a new blending tool which subtracts the darker of source
+// and target colors from the lighter one.
+func NewDifference() *Difference {
+	return &Difference{lines: newLines(separable(func(s, d, sa, da int) int {
+		sda := mul(s, da)
+		dsa := mul(d, sa)
+		if sda > dsa {
+			return sda - dsa
+		}
+		return dsa - sda
+	}))}
+}
+
+// Difference is a blending tool which subtracts the darker of source and target
+// colors from the lighter one.
+type Difference struct {
+	lines lines
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (d *Difference) BlendSourceToTarget(source, target image.Selection) {
+	d.lines.blendSourceToTarget(source, target)
+}
+
+// multiply is a simplified form of s*(1-da) + d*(1-sa) + s*d. It gives the same
+// results as OpenGL blending with factors DstColor and OneMinusSrcAlpha followed by
+// OneMinusDstAlpha and One.
+func multiply(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, srcA := s.RGBAi()
+		dstR, dstG, dstB, dstA := target[i].RGBAi()
+		srcFactor := 255 - dstA
+		dstFactor := 255 - srcA
+		target[i] = image.RGBAi(
+			mul(srcR, srcFactor)+mul(dstR, dstFactor+srcR),
+			mul(srcG, srcFactor)+mul(dstG, dstFactor+srcG),
+			mul(srcB, srcFactor)+mul(dstB, dstFactor+srcB),
+			srcA+mul(dstA, dstFactor),
+		)
+	}
+}
+
+// screen is a simplified form of s*(1-da) + d*(1-sa) + s*da + d*sa - s*d
+func screen(source, target []image.Color) {
+	for i, s := range source {
+		srcR, srcG, srcB, srcA := s.RGBAi()
+		dstR, dstG, dstB, dstA := target[i].RGBAi()
+		target[i] = image.RGBAi(
+			srcR+mul(dstR, 255-srcR),
+			srcG+mul(dstG, 255-srcG),
+			srcB+mul(dstB, 255-srcB),
+			srcA+mul(dstA, 255-srcA),
+		)
+	}
+}
+
+// separable returns lineBlender for a given blend function
+func separable(blend func(s, d, sa, da int) int) lineBlender {
+	return func(source, target []image.Color) {
+		for i, s := range source {
+			srcR, srcG, srcB, srcA := s.RGBAi()
+			dstR, dstG, dstB, dstA := target[i].RGBAi()
+			if srcA == 0 {
+				continue
+			}
+			srcFactor := 255 - dstA
+			dstFactor := 255 - srcA
+			outA := srcA + mul(dstA, dstFactor)
+			target[i] = image.RGBAi(
+				clamp(mul(srcR, srcFactor)+mul(dstR, dstFactor)+blend(srcR, dstR, srcA, dstA), 0, outA),
+				clamp(mul(srcG, srcFactor)+mul(dstG, dstFactor)+blend(srcG, dstG, srcA, dstA), 0, outA),
+				clamp(mul(srcB, srcFactor)+mul(dstB, dstFactor)+blend(srcB, dstB, srcA, dstA), 0, outA),
+				outA,
+			)
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
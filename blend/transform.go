@@ -0,0 +1,78 @@
+package blend
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Transform is a transformation of the source applied during blending. It can be
+// set using SetTransform method of Source and SourceOver.
+type Transform int
+
+const (
+	// NoTransform blends the source as it is. This is the default.
+	NoTransform Transform = iota
+	// FlipX mirrors the source horizontally
+	FlipX
+	// FlipY mirrors the source vertically
+	FlipY
+	// Rotate90 rotates the source by 90 degrees clockwise. Width and height
+	// of the source are swapped.
+	Rotate90
+	// Rotate180 rotates the source by 180 degrees
+	Rotate180
+	// Rotate270 rotates the source by 270 degrees clockwise (90 degrees
+	// counterclockwise). Width and height of the source are swapped.
+	Rotate270
+)
+
+// TargetSize returns the size of transformed source with given width and height.
+func (t Transform) TargetSize(width, height int) (int, int) {
+	if t == Rotate90 || t == Rotate270 {
+		return height, width
+	}
+	return width, height
+}
+
+// SourcePosition returns the position of source pixel which is drawn at local
+// position x, y of the target. Width and height are the source size.
+func (t Transform) SourcePosition(x, y, width, height int) (int, int) {
+	switch t {
+	case FlipX:
+		return width - 1 - x, y
+	case FlipY:
+		return x, height - 1 - y
+	case Rotate90:
+		return y, height - 1 - x
+	case Rotate180:
+		return width - 1 - x, height - 1 - y
+	case Rotate270:
+		return width - 1 - y, x
+	default:
+		return x, y
+	}
+}
+
+// blendTransformed blends transformed source into target pixel by pixel. Source
+// pixels outside the source image are passed as transparent colors.
+func blendTransformed(source, target image.Selection, transform Transform, blend func(source, target image.Color) image.Color) {
+	var (
+		sourceWidth  = source.Width()
+		sourceHeight = source.Height()
+	)
+	target = target.WithSize(transform.TargetSize(sourceWidth, sourceHeight))
+	if horizontallyOutsideImage(target) {
+		return
+	}
+	var (
+		targetLines   = target.Lines()
+		targetXOffset = targetLines.XOffset()
+		targetYOffset = targetLines.YOffset()
+	)
+	for y := 0; y < targetLines.Length(); y++ {
+		targetLine := targetLines.LineForWrite(y)
+		for i := range targetLine {
+			sourceX, sourceY := transform.SourcePosition(i+targetXOffset, y+targetYOffset, sourceWidth, sourceHeight)
+			targetLine[i] = blend(source.Color(sourceX, sourceY), targetLine[i])
+		}
+	}
+}
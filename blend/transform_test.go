@@ -0,0 +1,188 @@
+package blend_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/blend"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+type transformableBlender interface {
+	BlendSourceToTarget(source, target image.Selection)
+	SetTransform(transform blend.Transform)
+}
+
+func TestTransform_TargetSize(t *testing.T) {
+	tests := map[blend.Transform][2]int{
+		blend.NoTransform: {2, 3},
+		blend.FlipX:       {2, 3},
+		blend.FlipY:       {2, 3},
+		blend.Rotate90:    {3, 2},
+		blend.Rotate180:   {2, 3},
+		blend.Rotate270:   {3, 2},
+	}
+	for transform, expected := range tests {
+		width, height := transform.TargetSize(2, 3)
+		assert.Equal(t, expected, [2]int{width, height}, "transform %d", transform)
+	}
+}
+
+func TestSetTransform(t *testing.T) {
+	var (
+		color1 = image.RGBA(10, 20, 30, 40)
+		color2 = image.RGBA(50, 60, 70, 80)
+		color3 = image.RGBA(90, 100, 110, 120)
+		color4 = image.RGBA(130, 140, 150, 160)
+		color5 = image.RGBA(170, 180, 190, 200)
+		color6 = image.RGBA(210, 220, 230, 240)
+	)
+	tools := map[string]func() transformableBlender{
+		"Source":     func() transformableBlender { return blend.NewSource() },
+		"SourceOver": func() transformableBlender { return blend.NewSourceOver() },
+	}
+	transforms := map[string]struct {
+		transform blend.Transform
+		expected  [][]image.Color
+	}{
+		"NoTransform": {
+			transform: blend.NoTransform,
+			expected: [][]image.Color{
+				{color1, color2, color3},
+				{color4, color5, color6},
+			},
+		},
+		"FlipX": {
+			transform: blend.FlipX,
+			expected: [][]image.Color{
+				{color3, color2, color1},
+				{color6, color5, color4},
+			},
+		},
+		"FlipY": {
+			transform: blend.FlipY,
+			expected: [][]image.Color{
+				{color4, color5, color6},
+				{color1, color2, color3},
+			},
+		},
+		"Rotate90": {
+			transform: blend.Rotate90,
+			expected: [][]image.Color{
+				{color4, color1},
+				{color5, color2},
+				{color6, color3},
+			},
+		},
+		"Rotate180": {
+			transform: blend.Rotate180,
+			expected: [][]image.Color{
+				{color6, color5, color4},
+				{color3, color2, color1},
+			},
+		},
+		"Rotate270": {
+			transform: blend.Rotate270,
+			expected: [][]image.Color{
+				{color3, color6},
+				{color2, color5},
+				{color1, color4},
+			},
+		},
+	}
+	for toolName, newTool := range tools {
+		t.Run(toolName, func(t *testing.T) {
+			for name, test := range transforms {
+				t.Run(name, func(t *testing.T) {
+					source := newImage([][]image.Color{
+						{color1, color2, color3},
+						{color4, color5, color6},
+					}).WholeImageSelection()
+					target := newImage(transparentPixels(len(test.expected[0]), len(test.expected)))
+					tool := newTool()
+					tool.SetTransform(test.transform)
+					// when
+					tool.BlendSourceToTarget(source, target.WholeImageSelection())
+					// then
+					assertColors(t, target, test.expected)
+				})
+			}
+
+			t.Run("should clip transformed source to target image", func(t *testing.T) {
+				source := newImage([][]image.Color{
+					{color1, color2},
+					{color3, color4},
+				}).WholeImageSelection()
+				target := newImage(transparentPixels(3, 3))
+				tool := newTool()
+				tool.SetTransform(blend.Rotate90)
+				// when
+				tool.BlendSourceToTarget(source, target.Selection(-1, 2))
+				// then
+				assertColors(t, target, [][]image.Color{
+					{image.Transparent, image.Transparent, image.Transparent},
+					{image.Transparent, image.Transparent, image.Transparent},
+					{color1, image.Transparent, image.Transparent},
+				})
+			})
+
+			t.Run("should treat source pixels outside the image as transparent", func(t *testing.T) {
+				source := newImage([][]image.Color{
+					{color1, color2},
+				}).Selection(1, 0).WithSize(2, 1)
+				target := newImage(transparentPixels(2, 1))
+				tool := newTool()
+				tool.SetTransform(blend.FlipX)
+				// when
+				tool.BlendSourceToTarget(source, target.WholeImageSelection())
+				// then
+				assertColors(t, target, [][]image.Color{
+					{image.Transparent, color2},
+				})
+			})
+
+			t.Run("should skip target outside the image", func(t *testing.T) {
+				source := newImage([][]image.Color{
+					{color1},
+				}).WholeImageSelection()
+				target := newImage([][]image.Color{
+					{color6},
+				})
+				tool := newTool()
+				tool.SetTransform(blend.FlipY)
+				// when
+				tool.BlendSourceToTarget(source, target.Selection(1, 0))
+				tool.BlendSourceToTarget(source, target.Selection(0, 1))
+				tool.BlendSourceToTarget(source, target.Selection(-1, 0))
+				// then
+				assertColors(t, target, [][]image.Color{{color6}})
+			})
+		})
+	}
+
+	t.Run("SourceOver should blend transformed source", func(t *testing.T) {
+		source := newImage([][]image.Color{
+			{image.RGBA(0, 0, 0, 0), image.RGBA(100, 0, 0, 128)},
+		}).WholeImageSelection()
+		target := newImage([][]image.Color{
+			{image.RGB(0, 0, 200), image.RGB(0, 0, 200)},
+		})
+		tool := blend.NewSourceOver()
+		tool.SetTransform(blend.FlipX)
+		// when
+		tool.BlendSourceToTarget(source, target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{
+			{image.RGB(100, 0, 100), image.RGB(0, 0, 200)},
+		})
+	})
+}
+
+func transparentPixels(width, height int) [][]image.Color {
+	pixels := make([][]image.Color, height)
+	for y := range pixels {
+		pixels[y] = make([]image.Color, width)
+	}
+	return pixels
+}
@@ -4,11 +4,18 @@ package clear
 
 import (
 	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/parallel"
 )
 
 // New returns new instance of *clear.Tool
 func New() *Tool {
-	return &Tool{}
+	tool := &Tool{}
+	tool.clearLines = func(start, end int) {
+		for _, line := range tool.lines[start:end] {
+			tool.clearLine(line)
+		}
+	}
+	return tool
 }
 
 // Tool is a clearing tool. It clears the image.Selection with specific color
@@ -17,6 +24,10 @@ func New() *Tool {
 // Tool uses CPU.
 type Tool struct {
 	color image.Color
+	pool  *parallel.Pool
+	// lines and clearLines are reused by Clear to avoid allocations
+	lines      [][]image.Color
+	clearLines func(start, end int)
 }
 
 // SetColor sets color which will be used by Clear method
@@ -24,13 +35,41 @@ func (t *Tool) SetColor(color image.Color) {
 	t.color = color
 }
 
+// SetPool sets the pool of goroutines used for clearing. Lines of the selection
+// are split into continuous chunks and cleared in parallel. Lines are taken from
+// the image (which marks the image as modified in RAM) in the goroutine calling
+// Clear, before any worker starts. Clear returns when all workers are done.
+//
+// By default (nil pool) clearing is done in the current goroutine. Tool with a pool
+// set must not be used from many goroutines at the same time.
+func (t *Tool) SetPool(pool *parallel.Pool) {
+	t.pool = pool
+}
+
 // Clear clears selection with previously set color
 func (t *Tool) Clear(selection image.Selection) {
 	lines := selection.Lines()
-	for y := 0; y < lines.Length(); y++ {
-		line := lines.LineForWrite(y)
-		for x := 0; x < len(line); x++ {
-			line[x] = t.color
+	if t.pool == nil {
+		for y := 0; y < lines.Length(); y++ {
+			t.clearLine(lines.LineForWrite(y))
 		}
+		return
+	}
+	// lines are taken in the current goroutine, so the image is not accessed
+	// by workers
+	for y := 0; y < lines.Length(); y++ {
+		t.lines = append(t.lines, lines.LineForWrite(y))
+	}
+	t.pool.Run(len(t.lines), t.clearLines)
+	// do not retain image pixels
+	for i := range t.lines {
+		t.lines[i] = nil
+	}
+	t.lines = t.lines[:0]
+}
+
+func (t *Tool) clearLine(line []image.Color) {
+	for x := 0; x < len(line); x++ {
+		line[x] = t.color
 	}
 }
@@ -1,13 +1,16 @@
 package clear_test
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/jacekolszak/pixiq/clear"
 	"github.com/jacekolszak/pixiq/image"
 	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/parallel"
 )
 
+// 1920x1080 - 0.9ms
 func BenchmarkTool_Clear(b *testing.B) {
 	var (
 		width     = 1920
@@ -22,3 +25,23 @@ func BenchmarkTool_Clear(b *testing.B) {
 		tool.Clear(selection)
 	}
 }
+
+// Measured on 1 CPU, where the pool has only one worker:
+// 1920x1080 - 1ms (0.9ms without the pool)
+func BenchmarkTool_Clear_WithPool(b *testing.B) {
+	var (
+		width     = 1920
+		height    = 1080
+		img       = image.New(fake.NewAcceleratedImage(width, height))
+		selection = img.WholeImageSelection()
+		tool      = clear.New()
+		pool      = parallel.NewPool(runtime.NumCPU())
+	)
+	defer pool.Close()
+	tool.SetPool(pool)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.Clear(selection)
+	}
+}
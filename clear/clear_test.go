@@ -9,6 +9,7 @@ import (
 	"github.com/jacekolszak/pixiq/clear"
 	"github.com/jacekolszak/pixiq/image"
 	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/parallel"
 )
 
 func TestNew(t *testing.T) {
@@ -68,6 +69,45 @@ func TestClear(t *testing.T) {
 	})
 }
 
+func TestTool_SetPool(t *testing.T) {
+	pool := parallel.NewPool(3)
+	defer pool.Close()
+	color := image.RGBA(10, 20, 30, 40)
+	t.Run("should clear selection using pool", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(5, 7))
+		tool := clear.New()
+		tool.SetColor(color)
+		tool.SetPool(pool)
+		// when
+		tool.Clear(img.Selection(1, 1).WithSize(3, 5))
+		// then
+		selection := img.WholeImageSelection()
+		for y := 0; y < img.Height(); y++ {
+			for x := 0; x < img.Width(); x++ {
+				expected := image.Transparent
+				if x >= 1 && x < 4 && y >= 1 && y < 6 {
+					expected = color
+				}
+				assert.Equal(t, expected, selection.Color(x, y), "position (%d,%d)", x, y)
+			}
+		}
+	})
+	t.Run("should upload cleared pixels", func(t *testing.T) {
+		acceleratedImage := fake.NewAcceleratedImage(2, 2)
+		img := image.New(acceleratedImage)
+		tool := clear.New()
+		tool.SetColor(color)
+		tool.SetPool(pool)
+		// when
+		tool.Clear(img.WholeImageSelection())
+		img.Upload()
+		// then
+		output := make([]image.Color, 4)
+		acceleratedImage.Download(output)
+		assert.Equal(t, []image.Color{color, color, color, color}, output)
+	})
+}
+
 func assertColors(t *testing.T, img *image.Image, expectedColorLines [2][2]image.Color) {
 	selection := img.WholeImageSelection()
 	assert.Equal(t, expectedColorLines[0][0], selection.Color(0, 0), "position(0,0)")
@@ -0,0 +1,184 @@
+package decoder
+
+import (
+	"bufio"
+	stdimage "image"
+	"image/draw"
+	"image/gif"
+	"os"
+	"time"
+
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Frame is a single frame of decoded animation
+type Frame struct {
+	// Image contains all pixels of the frame
+	Image *image.Image
+	// Delay is the time the frame should be displayed before the next one
+	Delay time.Duration
+}
+
+// SpriteSheet is a decoded animation where all frames are packed into one image,
+// one next to each other from left to right.
+type SpriteSheet struct {
+	// Image contains all frames
+	Image *image.Image
+	// Frames are in the same order as in the decoded animation
+	Frames []SpriteSheetFrame
+}
+
+// SpriteSheetFrame is a single frame of the SpriteSheet
+type SpriteSheetFrame struct {
+	// Selection is a rectangle of SpriteSheet.Image containing the frame
+	Selection image.Selection
+	// Delay is the time the frame should be displayed before the next one
+	Delay time.Duration
+}
+
+// DecodeAnimation decodes compressed animation such as animated GIF and creates
+// a new *image.Image for each frame. Frames are composited according to
+// GIF disposal methods, therefore each frame contains the whole picture which
+// should be displayed on the screen.
+//
+// Images which are not animated (for example PNGs) are decoded as a single frame
+// with zero delay.
+func (d *Decoder) DecodeAnimation(reader Reader) ([]Frame, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	frames, err := decodeFrames(reader)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Frame, len(frames))
+	for i, frame := range frames {
+		size := frame.image.Bounds().Size()
+		img := d.imageFactory.NewImage(size.X, size.Y)
+		copyToSelection(frame.image, img.WholeImageSelection())
+		result[i] = Frame{
+			Image: img,
+			Delay: frame.delay,
+		}
+	}
+	return result, nil
+}
+
+// DecodeAnimationFile decodes compressed animation file such as animated GIF
+// and creates a new *image.Image for each frame. See DecodeAnimation.
+func (d *Decoder) DecodeAnimationFile(fileName string) ([]Frame, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return d.DecodeAnimation(file)
+}
+
+// DecodeSpriteSheet decodes compressed animation such as animated GIF and packs
+// all frames into one *image.Image. Frames are composited the same way as in
+// DecodeAnimation.
+func (d *Decoder) DecodeSpriteSheet(reader Reader) (SpriteSheet, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	frames, err := decodeFrames(reader)
+	if err != nil {
+		return SpriteSheet{}, err
+	}
+	size := frames[0].image.Bounds().Size()
+	img := d.imageFactory.NewImage(size.X*len(frames), size.Y)
+	sheetFrames := make([]SpriteSheetFrame, len(frames))
+	for i, frame := range frames {
+		selection := img.Selection(i*size.X, 0).WithSize(size.X, size.Y)
+		copyToSelection(frame.image, selection)
+		sheetFrames[i] = SpriteSheetFrame{
+			Selection: selection,
+			Delay:     frame.delay,
+		}
+	}
+	return SpriteSheet{
+		Image:  img,
+		Frames: sheetFrames,
+	}, nil
+}
+
+// DecodeSpriteSheetFile decodes compressed animation file such as animated GIF
+// and packs all frames into one *image.Image. See DecodeSpriteSheet.
+func (d *Decoder) DecodeSpriteSheetFile(fileName string) (SpriteSheet, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return SpriteSheet{}, err
+	}
+	defer file.Close()
+	return d.DecodeSpriteSheet(file)
+}
+
+// gifMagic is a common prefix of GIF87a and GIF89a headers
+const gifMagic = "GIF8"
+
+type decodedFrame struct {
+	image stdimage.Image
+	delay time.Duration
+}
+
+// decodeFrames returns at least one frame or error
+func decodeFrames(reader Reader) ([]decodedFrame, error) {
+	bufferedReader := bufio.NewReader(reader)
+	magic, err := bufferedReader.Peek(len(gifMagic))
+	if err == nil && string(magic) == gifMagic {
+		return decodeGIFFrames(bufferedReader)
+	}
+	img, _, err := stdimage.Decode(bufferedReader)
+	if err != nil {
+		return nil, err
+	}
+	return []decodedFrame{{image: img}}, nil
+}
+
+func decodeGIFFrames(reader Reader) ([]decodedFrame, error) {
+	g, err := gif.DecodeAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	bounds := stdimage.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := stdimage.NewRGBA(bounds)
+	previous := stdimage.NewRGBA(bounds)
+	frames := make([]decodedFrame, len(g.Image))
+	for i, paletted := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			copy(previous.Pix, canvas.Pix)
+		}
+		draw.Draw(canvas, paletted.Bounds(), paletted, paletted.Bounds().Min, draw.Over)
+		snapshot := stdimage.NewRGBA(bounds)
+		copy(snapshot.Pix, canvas.Pix)
+		frames[i] = decodedFrame{
+			image: snapshot,
+			delay: time.Duration(g.Delay[i]) * 10 * time.Millisecond,
+		}
+		switch disposal {
+		case gif.DisposalBackground:
+			// most decoders (including web browsers) clear the area to transparent
+			// instead of using the background color
+			draw.Draw(canvas, paletted.Bounds(), stdimage.Transparent, stdimage.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous.Pix)
+		}
+	}
+	return frames, nil
+}
+
+func copyToSelection(source stdimage.Image, target image.Selection) {
+	bounds := source.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			r, g, b, a := source.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			color := image.RGBA(byte(r>>8), byte(g>>8), byte(b>>8), byte(a>>8))
+			target.SetColor(x, y, color)
+		}
+	}
+}
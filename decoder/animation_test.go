@@ -0,0 +1,246 @@
+package decoder_test
+
+import (
+	"bytes"
+	stdimage "image"
+	"image/color"
+	"image/gif"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/decoder"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+var (
+	gifPalette = color.Palette{
+		color.RGBA{},
+		color.RGBA{R: 255, A: 255},
+		color.RGBA{G: 255, A: 255},
+		color.RGBA{B: 255, A: 255},
+	}
+	transparent = image.Transparent
+	red         = image.RGB(255, 0, 0)
+	green       = image.RGB(0, 255, 0)
+	blue        = image.RGB(0, 0, 255)
+)
+
+func TestDecoder_DecodeAnimation(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		assert.Panics(t, func() {
+			_, _ = imageDecoder.DecodeAnimation(nil)
+		})
+	})
+	t.Run("should return error when reader returned error", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		frames, err := imageDecoder.DecodeAnimation(&erroneousReader{})
+		assert.Nil(t, frames)
+		assert.Error(t, err)
+	})
+	t.Run("should return error when reader has invalid format", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		frames, err := imageDecoder.DecodeAnimation(&invalidFormatReader{})
+		assert.Nil(t, frames)
+		assert.Error(t, err)
+	})
+	t.Run("should decode not animated image as a single frame", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		// when
+		frames, err := imageDecoder.DecodeAnimation(bytes.NewReader(png2x1().data))
+		// then
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		assert.Equal(t, time.Duration(0), frames[0].Delay)
+		assertColors(t, png2x1().expectedColors, frames[0].Image.WholeImageSelection())
+	})
+	t.Run("should decode animated GIF", func(t *testing.T) {
+		tests := map[string]struct {
+			disposal       byte
+			expectedColors [][][]image.Color
+		}{
+			"disposal none": {
+				disposal: gif.DisposalNone,
+				expectedColors: [][][]image.Color{
+					{{red, red}},
+					{{red, green}},
+					{{blue, green}},
+				},
+			},
+			"disposal background": {
+				disposal: gif.DisposalBackground,
+				expectedColors: [][][]image.Color{
+					{{red, red}},
+					{{red, green}},
+					{{blue, transparent}},
+				},
+			},
+			"disposal previous": {
+				disposal: gif.DisposalPrevious,
+				expectedColors: [][][]image.Color{
+					{{red, red}},
+					{{red, green}},
+					{{blue, red}},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				imageDecoder := decoder.New(fakeImageFactory{})
+				data := animatedGIF(test.disposal)
+				// when
+				frames, err := imageDecoder.DecodeAnimation(bytes.NewReader(data))
+				// then
+				require.NoError(t, err)
+				require.Len(t, frames, 3)
+				assert.Equal(t, 100*time.Millisecond, frames[0].Delay)
+				assert.Equal(t, 200*time.Millisecond, frames[1].Delay)
+				assert.Equal(t, 300*time.Millisecond, frames[2].Delay)
+				for i, frame := range frames {
+					assertColors(t, test.expectedColors[i], frame.Image.WholeImageSelection())
+				}
+			})
+		}
+	})
+}
+
+func TestDecoder_DecodeAnimationFile(t *testing.T) {
+	t.Run("should return error", func(t *testing.T) {
+		filenames := []string{"", "not-existing-file"}
+		for _, filename := range filenames {
+			t.Run(filename, func(t *testing.T) {
+				imageDecoder := decoder.New(fakeImageFactory{})
+				// when
+				frames, err := imageDecoder.DecodeAnimationFile(filename)
+				assert.Error(t, err)
+				assert.Nil(t, frames)
+			})
+		}
+	})
+	t.Run("should decode file", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		fileName := tempFile(t, animatedGIF(gif.DisposalNone))
+		// when
+		frames, err := imageDecoder.DecodeAnimationFile(fileName)
+		// then
+		require.NoError(t, err)
+		assert.Len(t, frames, 3)
+	})
+}
+
+func TestDecoder_DecodeSpriteSheet(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		assert.Panics(t, func() {
+			_, _ = imageDecoder.DecodeSpriteSheet(nil)
+		})
+	})
+	t.Run("should return error when reader returned error", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		sheet, err := imageDecoder.DecodeSpriteSheet(&erroneousReader{})
+		assert.Nil(t, sheet.Image)
+		assert.Error(t, err)
+	})
+	t.Run("should return error when reader has invalid format", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		sheet, err := imageDecoder.DecodeSpriteSheet(&invalidFormatReader{})
+		assert.Nil(t, sheet.Image)
+		assert.Error(t, err)
+	})
+	t.Run("should decode animated GIF", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		data := animatedGIF(gif.DisposalNone)
+		// when
+		sheet, err := imageDecoder.DecodeSpriteSheet(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, sheet.Image)
+		assert.Equal(t, 6, sheet.Image.Width())
+		assert.Equal(t, 1, sheet.Image.Height())
+		assertColors(t, [][]image.Color{
+			{red, red, red, green, blue, green},
+		}, sheet.Image.WholeImageSelection())
+		// and
+		require.Len(t, sheet.Frames, 3)
+		for i, frame := range sheet.Frames {
+			assert.Same(t, sheet.Image, frame.Selection.Image())
+			assert.Equal(t, i*2, frame.Selection.ImageX())
+			assert.Equal(t, 0, frame.Selection.ImageY())
+			assert.Equal(t, 2, frame.Selection.Width())
+			assert.Equal(t, 1, frame.Selection.Height())
+			assert.Equal(t, time.Duration(i+1)*100*time.Millisecond, frame.Delay)
+		}
+	})
+}
+
+func TestDecoder_DecodeSpriteSheetFile(t *testing.T) {
+	t.Run("should return error", func(t *testing.T) {
+		filenames := []string{"", "not-existing-file"}
+		for _, filename := range filenames {
+			t.Run(filename, func(t *testing.T) {
+				imageDecoder := decoder.New(fakeImageFactory{})
+				// when
+				sheet, err := imageDecoder.DecodeSpriteSheetFile(filename)
+				assert.Error(t, err)
+				assert.Nil(t, sheet.Image)
+			})
+		}
+	})
+	t.Run("should decode file", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		fileName := tempFile(t, animatedGIF(gif.DisposalNone))
+		// when
+		sheet, err := imageDecoder.DecodeSpriteSheetFile(fileName)
+		// then
+		require.NoError(t, err)
+		assert.Len(t, sheet.Frames, 3)
+	})
+}
+
+// animatedGIF returns 2x1 GIF with 3 frames. First frame is red, second one
+// draws green pixel on the right, third one draws blue pixel on the left.
+// Second and third frame use given disposal method.
+func animatedGIF(disposal byte) []byte {
+	first := stdimage.NewPaletted(stdimage.Rect(0, 0, 2, 1), gifPalette)
+	first.SetColorIndex(0, 0, 1)
+	first.SetColorIndex(1, 0, 1)
+	second := stdimage.NewPaletted(stdimage.Rect(1, 0, 2, 1), gifPalette)
+	second.SetColorIndex(1, 0, 2)
+	third := stdimage.NewPaletted(stdimage.Rect(0, 0, 1, 1), gifPalette)
+	third.SetColorIndex(0, 0, 3)
+	buffer := bytes.Buffer{}
+	_ = gif.EncodeAll(&buffer, &gif.GIF{
+		Image:    []*stdimage.Paletted{first, second, third},
+		Delay:    []int{10, 20, 30},
+		Disposal: []byte{gif.DisposalNone, disposal, disposal},
+		Config: stdimage.Config{
+			ColorModel: gifPalette,
+			Width:      2,
+			Height:     1,
+		},
+	})
+	return buffer.Bytes()
+}
+
+func tempFile(t *testing.T, data []byte) string {
+	file, err := ioutil.TempFile("", "TestDecoder")
+	require.NoError(t, err)
+	defer file.Close()
+	_, err = file.Write(data)
+	require.NoError(t, err)
+	return file.Name()
+}
+
+func assertColors(t *testing.T, expectedColors [][]image.Color, selection image.Selection) {
+	require.Equal(t, len(expectedColors), selection.Height(), "height")
+	require.Equal(t, len(expectedColors[0]), selection.Width(), "width")
+	for y, line := range expectedColors {
+		for x, expectedColor := range line {
+			assertColor(t, expectedColor, selection.Color(x, y))
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Package aseprite provides functionality of decoding Aseprite files (.ase and
+// .aseprite) including layers, frames, animation tags and palette.
+//
+//	asepriteDecoder := aseprite.New(gl)
+//	file, err := asepriteDecoder.DecodeFile("sprite.aseprite")
+//	if err != nil {
+//		panic(err)
+//	}
+//	firstFrame := file.Frames[0].Image
+package aseprite
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/jacekolszak/pixiq/blend"
+	"github.com/jacekolszak/pixiq/decoder"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// New creates a Decoder instance which can be used many times for decoding
+// Aseprite files.
+func New(imageFactory decoder.ImageFactory) *Decoder {
+	if imageFactory == nil {
+		panic("nil imageFactory")
+	}
+	return &Decoder{imageFactory: imageFactory}
+}
+
+// Decoder decodes Aseprite files
+type Decoder struct {
+	imageFactory decoder.ImageFactory
+}
+
+// Reader is the equivalent of io.Reader
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+// Option is a decoding option
+type Option func(opts) opts
+
+// SeparateLayers instructs the Decoder to keep layers separate instead of
+// flattening them. Each Frame will have Layers filled and Image set to nil.
+func SeparateLayers() Option {
+	return func(o opts) opts {
+		o.separateLayers = true
+		return o
+	}
+}
+
+type opts struct {
+	separateLayers bool
+}
+
+func buildOpts(options ...Option) opts {
+	o := opts{}
+	for _, option := range options {
+		o = option(o)
+	}
+	return o
+}
+
+// File is a decoded Aseprite file
+type File struct {
+	Width, Height int
+	// Layers are ordered from the bottom-most to the top-most one
+	Layers []Layer
+	Frames []Frame
+	Tags   []Tag
+	// Palette contains all colors of the palette. For files in indexed color mode
+	// the palette is used for decoding pixels.
+	Palette []image.Color
+}
+
+// Layer contains information about the Aseprite layer
+type Layer struct {
+	Name    string
+	Visible bool
+	// Group is true for layers which do not contain pixels but only group other layers
+	Group bool
+	// ChildLevel is the nesting level of the layer. Top-level layers have zero
+	// ChildLevel, layers inside the top-level group have 1 etc.
+	ChildLevel int
+	// Opacity is 255 for files which do not support layer opacity
+	Opacity byte
+}
+
+// Frame is a single frame of the Aseprite file
+type Frame struct {
+	// Image contains all visible layers flattened into one image.
+	// It is nil when SeparateLayers option was used.
+	Image *image.Image
+	// Layers contains one image for each layer of the File. Images for group
+	// layers are nil. Layers are filled only when SeparateLayers option was used.
+	// Layer opacity is not applied, but cel opacity is.
+	Layers []*image.Image
+	// Duration is the time the frame should be displayed
+	Duration time.Duration
+}
+
+// Direction is a direction of the animation tag
+type Direction int
+
+const (
+	// Forward plays frames from From to To
+	Forward Direction = iota
+	// Reverse plays frames from To to From
+	Reverse
+	// PingPong plays frames from From to To and then back to From
+	PingPong
+	// PingPongReverse plays frames from To to From and then back to To
+	PingPongReverse
+)
+
+// Tag is an animation tag, that is a named range of frames
+type Tag struct {
+	Name string
+	// From is the index of the first frame
+	From int
+	// To is the index of the last frame (inclusive)
+	To        int
+	Direction Direction
+	// Repeat is the number of times the animation should be played. Zero means
+	// infinity.
+	Repeat int
+}
+
+// Decode decodes the Aseprite file. By default all visible layers of each frame
+// are flattened into a single image using normal blend mode. Other blend
+// modes are not supported and are treated as normal. Tilemap layers
+// are decoded as empty ones.
+func (d *Decoder) Decode(reader Reader, options ...Option) (*File, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	opts := buildOpts(options...)
+	file := &File{
+		Width:   f.width,
+		Height:  f.height,
+		Layers:  make([]Layer, len(f.layers)),
+		Frames:  make([]Frame, len(f.frames)),
+		Tags:    f.tags,
+		Palette: f.palette,
+	}
+	for i, l := range f.layers {
+		file.Layers[i] = l.Layer
+	}
+	for i, fr := range f.frames {
+		frame := Frame{Duration: fr.duration}
+		if opts.separateLayers {
+			frame.Layers = d.layerImages(f, fr)
+		} else {
+			frame.Image = d.flattenedImage(f, fr)
+		}
+		file.Frames[i] = frame
+	}
+	return file, nil
+}
+
+// DecodeFile decodes Aseprite file with a given name. See Decode.
+func (d *Decoder) DecodeFile(fileName string, options ...Option) (*File, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return d.Decode(file, options...)
+}
+
+func (d *Decoder) flattenedImage(f *file, fr frame) *image.Image {
+	pixels := make([]image.Color, f.width*f.height)
+	visible := f.visibleLayers()
+	for layerIndex, layer := range f.layers {
+		c := fr.cels[layerIndex]
+		if c == nil || !visible[layerIndex] {
+			continue
+		}
+		f.drawCel(pixels, c, layer.Opacity)
+	}
+	return d.newImage(f.width, f.height, pixels)
+}
+
+func (d *Decoder) layerImages(f *file, fr frame) []*image.Image {
+	images := make([]*image.Image, len(f.layers))
+	for layerIndex, layer := range f.layers {
+		if layer.Group {
+			continue
+		}
+		pixels := make([]image.Color, f.width*f.height)
+		if c := fr.cels[layerIndex]; c != nil {
+			f.drawCel(pixels, c, 255)
+		}
+		images[layerIndex] = d.newImage(f.width, f.height, pixels)
+	}
+	return images
+}
+
+func (d *Decoder) newImage(width, height int, pixels []image.Color) *image.Image {
+	img := d.imageFactory.NewImage(width, height)
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, pixels[x+y*width])
+		}
+	}
+	return img
+}
+
+// drawCel blends cel clipped to the canvas into pixels using source-over
+// blending. Cel colors are multiplied by cel and layer opacity.
+func (f *file) drawCel(pixels []image.Color, c *cel, layerOpacity byte) {
+	sourceOver := blend.NewSourceOver()
+	sourceOver.SetTint(image.RGBA(c.opacity, c.opacity, c.opacity, c.opacity))
+	sourceOver.SetOpacity(layerOpacity)
+	for y := 0; y < c.height; y++ {
+		canvasY := c.y + y
+		if canvasY < 0 || canvasY >= f.height {
+			continue
+		}
+		for x := 0; x < c.width; x++ {
+			canvasX := c.x + x
+			if canvasX < 0 || canvasX >= f.width {
+				continue
+			}
+			i := canvasX + canvasY*f.width
+			pixels[i] = sourceOver.BlendSourceToTargetColor(f.pixel(c, x, y), pixels[i])
+		}
+	}
+}
+
+// visibleLayers returns true for each layer which is visible and all its
+// parent groups are visible too
+func (f *file) visibleLayers() []bool {
+	visible := make([]bool, len(f.layers))
+	var groups []bool // visibility of the most recent group on each child level
+	for i, layer := range f.layers {
+		parentVisible := true
+		if layer.ChildLevel > 0 && layer.ChildLevel <= len(groups) {
+			parentVisible = groups[layer.ChildLevel-1]
+		}
+		visible[i] = parentVisible && layer.Visible
+		if layer.Group {
+			for len(groups) <= layer.ChildLevel {
+				groups = append(groups, true)
+			}
+			groups = groups[:layer.ChildLevel+1]
+			groups[layer.ChildLevel] = visible[i]
+		}
+	}
+	return visible
+}
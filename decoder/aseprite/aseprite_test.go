@@ -0,0 +1,610 @@
+package aseprite_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/decoder/aseprite"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+var (
+	transparent = image.Transparent
+	red         = image.RGB(255, 0, 0)
+	green       = image.RGB(0, 255, 0)
+	blue        = image.RGB(0, 0, 255)
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should panic for nil ImageFactory", func(t *testing.T) {
+		assert.Panics(t, func() {
+			aseprite.New(nil)
+		})
+	})
+	t.Run("should create Decoder", func(t *testing.T) {
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		assert.NotNil(t, asepriteDecoder)
+	})
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		assert.Panics(t, func() {
+			_, _ = asepriteDecoder.Decode(nil)
+		})
+	})
+	t.Run("should return error when reader returned error", func(t *testing.T) {
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		file, err := asepriteDecoder.Decode(&erroneousReader{err: errors.New("read error")})
+		assert.Nil(t, file)
+		assert.Error(t, err)
+	})
+	t.Run("should return error for invalid data", func(t *testing.T) {
+		tests := map[string][]byte{
+			"empty":         {},
+			"invalid magic": make([]byte, 128),
+			"truncated header": asepriteFile{width: 1, height: 1, depth: 32}.
+				bytes()[:64],
+			"unsupported depth": asepriteFile{width: 1, height: 1, depth: 24}.
+				bytes(),
+			"invalid frame magic": invalidFrameMagic(),
+			"truncated chunk": truncate(asepriteFile{width: 1, height: 1, depth: 32,
+				frames: []asepriteFrame{
+					{chunks: []chunk{layerChunk("layer", 1, 0, 0, 255)}},
+				}}.bytes(), 3),
+			"invalid compressed cel": asepriteFile{width: 1, height: 1, depth: 32,
+				frames: []asepriteFrame{
+					{chunks: []chunk{
+						layerChunk("layer", 1, 0, 0, 255),
+						{chunkType: 0x2005, data: celHeader(0, 0, 0, 255, 2, 1, 1, []byte{1, 2, 3})},
+					}},
+				}}.bytes(),
+			"compressed cel bigger than declared": asepriteFile{width: 1, height: 1, depth: 32,
+				frames: []asepriteFrame{
+					{chunks: []chunk{
+						layerChunk("layer", 1, 0, 0, 255),
+						compressedCelChunk(0, 0, 0, 255, 1, 1, make([]byte, 8)),
+					}},
+				}}.bytes(),
+			"frame size smaller than frame header": withFrameSize(asepriteFile{width: 1, height: 1, depth: 32,
+				frames: []asepriteFrame{{}}}.bytes(), 15),
+			"frame size bigger than data": withFrameSize(asepriteFile{width: 1, height: 1, depth: 32,
+				frames: []asepriteFrame{{}}}.bytes(), 17),
+			"linked cel to next frame": asepriteFile{width: 1, height: 1, depth: 32,
+				frames: []asepriteFrame{
+					{chunks: []chunk{
+						layerChunk("layer", 1, 0, 0, 255),
+						linkedCelChunk(0, 1),
+					}},
+					{},
+				}}.bytes(),
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				asepriteDecoder := aseprite.New(fakeImageFactory{})
+				// when
+				file, err := asepriteDecoder.Decode(bytes.NewReader(data))
+				// then
+				assert.Nil(t, file)
+				assert.Error(t, err)
+			})
+		}
+	})
+	t.Run("should decode frames", func(t *testing.T) {
+		data := asepriteFile{
+			width: 2, height: 1, depth: 32,
+			frames: []asepriteFrame{
+				{
+					duration: 100,
+					chunks: []chunk{
+						layerChunk("layer", 1, 0, 0, 255),
+						rawCelChunk(0, 0, 0, 255, 2, 1, rgbaPixels(red, green)),
+					},
+				},
+				{
+					duration: 200,
+					chunks: []chunk{
+						compressedCelChunk(0, 1, 0, 255, 1, 1, rgbaPixels(blue)),
+					},
+				},
+				{
+					duration: 300,
+					chunks: []chunk{
+						linkedCelChunk(0, 0),
+					},
+				},
+			},
+		}.bytes()
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		// when
+		file, err := asepriteDecoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, file.Width)
+		assert.Equal(t, 1, file.Height)
+		require.Len(t, file.Frames, 3)
+		assert.Equal(t, 100*time.Millisecond, file.Frames[0].Duration)
+		assert.Equal(t, 200*time.Millisecond, file.Frames[1].Duration)
+		assert.Equal(t, 300*time.Millisecond, file.Frames[2].Duration)
+		assertColors(t, []image.Color{red, green}, file.Frames[0].Image)
+		assertColors(t, []image.Color{transparent, blue}, file.Frames[1].Image)
+		assertColors(t, []image.Color{red, green}, file.Frames[2].Image)
+		assert.Nil(t, file.Frames[0].Layers)
+	})
+	t.Run("should clip cel to canvas", func(t *testing.T) {
+		data := asepriteFile{
+			width: 2, height: 1, depth: 32,
+			frames: []asepriteFrame{
+				{chunks: []chunk{
+					layerChunk("layer", 1, 0, 0, 255),
+					rawCelChunk(0, -1, 0, 255, 2, 2, rgbaPixels(red, green, blue, blue)),
+				}},
+			},
+		}.bytes()
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		// when
+		file, err := asepriteDecoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assertColors(t, []image.Color{green, transparent}, file.Frames[0].Image)
+	})
+	t.Run("should decode color depths", func(t *testing.T) {
+		tests := map[string]struct {
+			depth            uint16
+			transparentIndex byte
+			paletteChunk     chunk
+			pixels           []byte
+			expected         []image.Color
+		}{
+			"rgba": {
+				depth:    32,
+				pixels:   []byte{200, 100, 50, 51, 10, 20, 30, 255},
+				expected: []image.Color{image.NRGBA(200, 100, 50, 51), image.RGB(10, 20, 30)},
+			},
+			"grayscale": {
+				depth:    16,
+				pixels:   []byte{100, 255, 200, 51},
+				expected: []image.Color{image.RGB(100, 100, 100), image.NRGBA(200, 200, 200, 51)},
+			},
+			"indexed": {
+				depth:            8,
+				transparentIndex: 0,
+				paletteChunk:     paletteChunk(image.Transparent, red, green),
+				pixels:           []byte{0, 2},
+				expected:         []image.Color{transparent, green},
+			},
+			"indexed with transparent index different than zero": {
+				depth:            8,
+				transparentIndex: 1,
+				paletteChunk:     paletteChunk(red, green),
+				pixels:           []byte{0, 1},
+				expected:         []image.Color{red, transparent},
+			},
+			"indexed with old palette": {
+				depth:            8,
+				transparentIndex: 2,
+				paletteChunk:     oldPaletteChunk(red, green),
+				pixels:           []byte{0, 1},
+				expected:         []image.Color{red, green},
+			},
+			"indexed outside the palette": {
+				depth:            8,
+				transparentIndex: 0,
+				paletteChunk:     paletteChunk(image.Transparent),
+				pixels:           []byte{5, 5},
+				expected:         []image.Color{transparent, transparent},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				chunks := []chunk{
+					layerChunk("layer", 1, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 2, 1, test.pixels),
+				}
+				if test.paletteChunk.data != nil {
+					chunks = append(chunks, test.paletteChunk)
+				}
+				data := asepriteFile{
+					width: 2, height: 1, depth: test.depth,
+					transparentIndex: test.transparentIndex,
+					frames:           []asepriteFrame{{chunks: chunks}},
+				}.bytes()
+				asepriteDecoder := aseprite.New(fakeImageFactory{})
+				// when
+				file, err := asepriteDecoder.Decode(bytes.NewReader(data))
+				// then
+				require.NoError(t, err)
+				assertColors(t, test.expected, file.Frames[0].Image)
+			})
+		}
+	})
+	t.Run("should flatten visible layers", func(t *testing.T) {
+		tests := map[string]struct {
+			flags    uint32
+			chunks   []chunk
+			expected image.Color
+		}{
+			"top layer covers bottom one": {
+				chunks: []chunk{
+					layerChunk("bottom", 1, 0, 0, 255),
+					layerChunk("top", 1, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, rgbaPixels(red)),
+					rawCelChunk(1, 0, 0, 255, 1, 1, rgbaPixels(green)),
+				},
+				expected: green,
+			},
+			"semi-transparent top layer": {
+				chunks: []chunk{
+					layerChunk("bottom", 1, 0, 0, 255),
+					layerChunk("top", 1, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, rgbaPixels(red)),
+					rawCelChunk(1, 0, 0, 255, 1, 1, []byte{0, 255, 0, 51}),
+				},
+				expected: image.RGB(204, 51, 0),
+			},
+			"cel opacity": {
+				chunks: []chunk{
+					layerChunk("layer", 1, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 51, 1, 1, rgbaPixels(red)),
+				},
+				expected: image.RGBA(51, 0, 0, 51),
+			},
+			"layer opacity": {
+				flags: 1,
+				chunks: []chunk{
+					layerChunk("layer", 1, 0, 0, 51),
+					rawCelChunk(0, 0, 0, 255, 1, 1, rgbaPixels(red)),
+				},
+				expected: image.RGBA(51, 0, 0, 51),
+			},
+			"layer opacity ignored when flag is not set": {
+				chunks: []chunk{
+					layerChunk("layer", 1, 0, 0, 51),
+					rawCelChunk(0, 0, 0, 255, 1, 1, rgbaPixels(red)),
+				},
+				expected: red,
+			},
+			"hidden layer": {
+				chunks: []chunk{
+					layerChunk("bottom", 1, 0, 0, 255),
+					layerChunk("top", 0, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, rgbaPixels(red)),
+					rawCelChunk(1, 0, 0, 255, 1, 1, rgbaPixels(green)),
+				},
+				expected: red,
+			},
+			"layer in hidden group": {
+				chunks: []chunk{
+					layerChunk("bottom", 1, 0, 0, 255),
+					layerChunk("group", 0, 1, 0, 255),
+					layerChunk("top", 1, 0, 1, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, rgbaPixels(red)),
+					rawCelChunk(2, 0, 0, 255, 1, 1, rgbaPixels(green)),
+				},
+				expected: red,
+			},
+			"layer after hidden group": {
+				chunks: []chunk{
+					layerChunk("group", 0, 1, 0, 255),
+					layerChunk("child", 1, 0, 1, 255),
+					layerChunk("top", 1, 0, 0, 255),
+					rawCelChunk(1, 0, 0, 255, 1, 1, rgbaPixels(red)),
+					rawCelChunk(2, 0, 0, 255, 1, 1, rgbaPixels(green)),
+				},
+				expected: green,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				data := asepriteFile{
+					width: 1, height: 1, depth: 32, flags: test.flags,
+					frames: []asepriteFrame{{chunks: test.chunks}},
+				}.bytes()
+				asepriteDecoder := aseprite.New(fakeImageFactory{})
+				// when
+				file, err := asepriteDecoder.Decode(bytes.NewReader(data))
+				// then
+				require.NoError(t, err)
+				assertColors(t, []image.Color{test.expected}, file.Frames[0].Image)
+			})
+		}
+	})
+	t.Run("should keep layers separate", func(t *testing.T) {
+		data := asepriteFile{
+			width: 1, height: 1, depth: 32, flags: 1,
+			frames: []asepriteFrame{{chunks: []chunk{
+				layerChunk("group", 1, 1, 0, 255),
+				layerChunk("bottom", 1, 0, 1, 255),
+				layerChunk("top", 0, 0, 1, 51),
+				rawCelChunk(1, 0, 0, 255, 1, 1, rgbaPixels(red)),
+				rawCelChunk(2, 0, 0, 51, 1, 1, rgbaPixels(green)),
+			}}},
+		}.bytes()
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		// when
+		file, err := asepriteDecoder.Decode(bytes.NewReader(data), aseprite.SeparateLayers())
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []aseprite.Layer{
+			{Name: "group", Visible: true, Group: true, Opacity: 255},
+			{Name: "bottom", Visible: true, ChildLevel: 1, Opacity: 255},
+			{Name: "top", ChildLevel: 1, Opacity: 51},
+		}, file.Layers)
+		frame := file.Frames[0]
+		assert.Nil(t, frame.Image)
+		require.Len(t, frame.Layers, 3)
+		assert.Nil(t, frame.Layers[0])
+		assertColors(t, []image.Color{red}, frame.Layers[1])
+		assertColors(t, []image.Color{image.RGBA(0, 51, 0, 51)}, frame.Layers[2])
+	})
+	t.Run("should decode tags", func(t *testing.T) {
+		data := asepriteFile{
+			width: 1, height: 1, depth: 32,
+			frames: []asepriteFrame{
+				{chunks: []chunk{
+					tagsChunk(
+						aseprite.Tag{Name: "walk", From: 0, To: 1, Direction: aseprite.PingPong, Repeat: 3},
+						aseprite.Tag{Name: "idle", From: 2, To: 2, Direction: aseprite.Reverse},
+					),
+				}},
+				{}, {},
+			},
+		}.bytes()
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		// when
+		file, err := asepriteDecoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []aseprite.Tag{
+			{Name: "walk", From: 0, To: 1, Direction: aseprite.PingPong, Repeat: 3},
+			{Name: "idle", From: 2, To: 2, Direction: aseprite.Reverse},
+		}, file.Tags)
+	})
+	t.Run("should decode palette", func(t *testing.T) {
+		data := asepriteFile{
+			width: 1, height: 1, depth: 32,
+			frames: []asepriteFrame{
+				{chunks: []chunk{
+					paletteChunk(red, image.NRGBA(10, 20, 30, 51)),
+				}},
+			},
+		}.bytes()
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		// when
+		file, err := asepriteDecoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []image.Color{red, image.NRGBA(10, 20, 30, 51)}, file.Palette)
+	})
+	t.Run("should ignore unknown chunks", func(t *testing.T) {
+		data := asepriteFile{
+			width: 1, height: 1, depth: 32,
+			frames: []asepriteFrame{
+				{chunks: []chunk{
+					{chunkType: 0x2020, data: []byte{1, 2, 3}},
+					layerChunk("layer", 1, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, rgbaPixels(red)),
+				}},
+			},
+		}.bytes()
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		// when
+		file, err := asepriteDecoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assertColors(t, []image.Color{red}, file.Frames[0].Image)
+	})
+}
+
+func TestDecoder_DecodeFile(t *testing.T) {
+	t.Run("should return error", func(t *testing.T) {
+		filenames := []string{"", "not-existing-file"}
+		for _, filename := range filenames {
+			t.Run(filename, func(t *testing.T) {
+				asepriteDecoder := aseprite.New(fakeImageFactory{})
+				// when
+				file, err := asepriteDecoder.DecodeFile(filename)
+				assert.Error(t, err)
+				assert.Nil(t, file)
+			})
+		}
+	})
+	t.Run("should decode file", func(t *testing.T) {
+		asepriteDecoder := aseprite.New(fakeImageFactory{})
+		// when
+		file, err := asepriteDecoder.DecodeFile("../../docs/pixiq-primitives.aseprite")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 156, file.Width)
+		assert.Equal(t, 120, file.Height)
+		assert.Len(t, file.Frames, 2)
+		assert.Len(t, file.Layers, 5)
+	})
+}
+
+func assertColors(t *testing.T, expected []image.Color, img *image.Image) {
+	require.NotNil(t, img)
+	require.Equal(t, len(expected), img.Width()*img.Height())
+	selection := img.WholeImageSelection()
+	for i, expectedColor := range expected {
+		x := i % img.Width()
+		y := i / img.Width()
+		assert.Equal(t, expectedColor, selection.Color(x, y), "position (%d,%d)", x, y)
+	}
+}
+
+type fakeImageFactory struct{}
+
+func (f fakeImageFactory) NewImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
+
+type erroneousReader struct {
+	err error
+}
+
+func (r *erroneousReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// asepriteFile builds binary Aseprite file used in tests
+type asepriteFile struct {
+	width, height    uint16
+	depth            uint16
+	flags            uint32
+	transparentIndex byte
+	frames           []asepriteFrame
+}
+
+type asepriteFrame struct {
+	duration uint16
+	chunks   []chunk
+}
+
+type chunk struct {
+	chunkType uint16
+	data      []byte
+}
+
+func (f asepriteFile) bytes() []byte {
+	var frames bytes.Buffer
+	for _, frame := range f.frames {
+		var chunks bytes.Buffer
+		for _, c := range frame.chunks {
+			write(&chunks, uint32(len(c.data)+6), c.chunkType, c.data)
+		}
+		write(&frames, uint32(chunks.Len()+16), uint16(0xF1FA), uint16(len(frame.chunks)),
+			frame.duration, uint16(0), uint32(len(frame.chunks)), chunks.Bytes())
+	}
+	var header bytes.Buffer
+	write(&header, uint32(128+frames.Len()), uint16(0xA5E0), uint16(len(f.frames)),
+		f.width, f.height, f.depth, f.flags, uint16(100), uint32(0), uint32(0),
+		f.transparentIndex)
+	header.Write(make([]byte, 128-header.Len()))
+	return append(header.Bytes(), frames.Bytes()...)
+}
+
+func write(buffer *bytes.Buffer, values ...interface{}) {
+	for _, value := range values {
+		_ = binary.Write(buffer, binary.LittleEndian, value)
+	}
+}
+
+func writeString(buffer *bytes.Buffer, s string) {
+	write(buffer, uint16(len(s)), []byte(s))
+}
+
+func layerChunk(name string, flags, layerType, childLevel uint16, opacity byte) chunk {
+	var buffer bytes.Buffer
+	write(&buffer, flags, layerType, childLevel, uint16(0), uint16(0), uint16(0), opacity,
+		[3]byte{})
+	writeString(&buffer, name)
+	return chunk{chunkType: 0x2004, data: buffer.Bytes()}
+}
+
+func celHeader(layer uint16, x, y int16, opacity byte, celType uint16, width, height uint16, data []byte) []byte {
+	var buffer bytes.Buffer
+	write(&buffer, layer, x, y, opacity, celType, int16(0), [5]byte{}, width, height, data)
+	return buffer.Bytes()
+}
+
+func rawCelChunk(layer uint16, x, y int16, opacity byte, width, height uint16, pixels []byte) chunk {
+	return chunk{
+		chunkType: 0x2005,
+		data:      celHeader(layer, x, y, opacity, 0, width, height, pixels),
+	}
+}
+
+func compressedCelChunk(layer uint16, x, y int16, opacity byte, width, height uint16, pixels []byte) chunk {
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	_, _ = writer.Write(pixels)
+	_ = writer.Close()
+	return chunk{
+		chunkType: 0x2005,
+		data:      celHeader(layer, x, y, opacity, 2, width, height, compressed.Bytes()),
+	}
+}
+
+func linkedCelChunk(layer uint16, frame uint16) chunk {
+	var buffer bytes.Buffer
+	write(&buffer, layer, int16(0), int16(0), byte(255), uint16(1), int16(0), [5]byte{}, frame)
+	return chunk{chunkType: 0x2005, data: buffer.Bytes()}
+}
+
+func tagsChunk(tags ...aseprite.Tag) chunk {
+	var buffer bytes.Buffer
+	write(&buffer, uint16(len(tags)), [8]byte{})
+	for _, tag := range tags {
+		write(&buffer, uint16(tag.From), uint16(tag.To), byte(tag.Direction), uint16(tag.Repeat),
+			[6]byte{}, [3]byte{}, byte(0))
+		writeString(&buffer, tag.Name)
+	}
+	return chunk{chunkType: 0x2018, data: buffer.Bytes()}
+}
+
+// paletteChunk creates palette chunk. Colors must be fully opaque or with
+// components which can be unpremultiplied without loss.
+func paletteChunk(colors ...image.Color) chunk {
+	var buffer bytes.Buffer
+	write(&buffer, uint32(len(colors)), uint32(0), uint32(len(colors)-1), [8]byte{})
+	for i, c := range colors {
+		flags := uint16(i % 2) // every second entry has a name
+		write(&buffer, flags, straight(c))
+		if flags == 1 {
+			writeString(&buffer, "name")
+		}
+	}
+	return chunk{chunkType: 0x2019, data: buffer.Bytes()}
+}
+
+func oldPaletteChunk(colors ...image.Color) chunk {
+	var buffer bytes.Buffer
+	write(&buffer, uint16(1), byte(0), byte(len(colors)))
+	for _, c := range colors {
+		write(&buffer, c.R(), c.G(), c.B())
+	}
+	return chunk{chunkType: 0x0004, data: buffer.Bytes()}
+}
+
+func rgbaPixels(colors ...image.Color) []byte {
+	var pixels []byte
+	for _, c := range colors {
+		s := straight(c)
+		pixels = append(pixels, s[:]...)
+	}
+	return pixels
+}
+
+func straight(c image.Color) [4]byte {
+	r, g, b, a := c.RGBAi()
+	if a == 0 {
+		return [4]byte{}
+	}
+	return [4]byte{byte(r * 255 / a), byte(g * 255 / a), byte(b * 255 / a), byte(a)}
+}
+
+func invalidFrameMagic() []byte {
+	data := asepriteFile{width: 1, height: 1, depth: 32, frames: []asepriteFrame{{}}}.bytes()
+	data[128+4] = 0
+	return data
+}
+
+// withFrameSize overrides the size of the first frame
+func withFrameSize(data []byte, size uint32) []byte {
+	binary.LittleEndian.PutUint32(data[128:], size)
+	return data
+}
+
+func truncate(data []byte, n int) []byte {
+	return data[:len(data)-n]
+}
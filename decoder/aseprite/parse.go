@@ -0,0 +1,394 @@
+package aseprite
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/jacekolszak/pixiq/image"
+)
+
+const (
+	fileMagic  = 0xA5E0
+	frameMagic = 0xF1FA
+
+	headerSize      = 128
+	frameHeaderSize = 16
+
+	depthRGBA      = 32
+	depthGrayscale = 16
+	depthIndexed   = 8
+
+	flagLayerOpacityValid = 1
+
+	chunkOldPalette = 0x0004
+	chunkLayer      = 0x2004
+	chunkCel        = 0x2005
+	chunkTags       = 0x2018
+	chunkPalette    = 0x2019
+
+	layerFlagVisible = 1
+	layerTypeGroup   = 1
+
+	celTypeRaw        = 0
+	celTypeLinked     = 1
+	celTypeCompressed = 2
+
+	paletteEntryHasName = 1
+)
+
+var errUnexpectedEOF = errors.New("unexpected end of Aseprite file")
+
+// file is an intermediate representation of the Aseprite file where cels
+// are not yet decoded into images
+type file struct {
+	width, height    int
+	depth            int
+	transparentIndex byte
+	layers           []layer
+	frames           []frame
+	tags             []Tag
+	palette          []image.Color
+}
+
+type layer struct {
+	Layer
+}
+
+type frame struct {
+	duration time.Duration
+	// cels are indexed by layer index. Nil means that layer is empty in the frame.
+	cels []*cel
+}
+
+type cel struct {
+	x, y          int
+	width, height int
+	opacity       byte
+	// pixels contain raw pixel data in the color depth of the file
+	pixels []byte
+}
+
+func parse(data []byte) (*file, error) {
+	r := &binaryReader{data: data}
+	r.dword() // file size
+	if r.word() != fileMagic {
+		return nil, errors.New("not an Aseprite file")
+	}
+	framesCount := int(r.word())
+	f := &file{
+		width:  int(r.word()),
+		height: int(r.word()),
+		depth:  int(r.word()),
+	}
+	flags := r.dword()
+	r.skip(2 + 4 + 4) // speed (deprecated) and two reserved dwords
+	f.transparentIndex = r.byte()
+	r.skip(headerSize - r.offset)
+	if r.err != nil {
+		return nil, r.err
+	}
+	if f.depth != depthRGBA && f.depth != depthGrayscale && f.depth != depthIndexed {
+		return nil, errors.New("unsupported color depth")
+	}
+	layerOpacityValid := flags&flagLayerOpacityValid != 0
+	newPaletteFound := false
+	for i := 0; i < framesCount; i++ {
+		frameStart := r.offset
+		frameSize := int(r.dword())
+		if frameSize < frameHeaderSize || frameSize > len(r.data)-frameStart {
+			return nil, errors.New("invalid Aseprite frame size")
+		}
+		if r.word() != frameMagic {
+			return nil, errors.New("invalid Aseprite frame")
+		}
+		oldChunks := int(r.word())
+		fr := frame{
+			duration: time.Duration(r.word()) * time.Millisecond,
+		}
+		r.skip(2)
+		chunks := int(r.dword())
+		if chunks == 0 {
+			chunks = oldChunks
+		}
+		for j := 0; j < chunks; j++ {
+			chunkSize := int(r.dword())
+			chunkType := r.word()
+			chunk := &binaryReader{data: r.bytes(chunkSize - 6)}
+			if r.err != nil {
+				return nil, r.err
+			}
+			var err error
+			switch chunkType {
+			case chunkLayer:
+				f.layers = append(f.layers, parseLayer(chunk, layerOpacityValid))
+			case chunkCel:
+				err = parseCel(chunk, f, &fr)
+			case chunkTags:
+				f.tags = append(f.tags, parseTags(chunk)...)
+			case chunkPalette:
+				f.palette = parsePalette(chunk, f.palette)
+				newPaletteFound = true
+			case chunkOldPalette:
+				if !newPaletteFound {
+					f.palette = parseOldPalette(chunk, f.palette)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			if chunk.err != nil {
+				return nil, chunk.err
+			}
+		}
+		f.frames = append(f.frames, fr)
+		r.offset = frameStart
+		r.skip(frameSize)
+		if r.err != nil {
+			return nil, r.err
+		}
+	}
+	for i := range f.frames {
+		f.frames[i].cels = resizeCels(f.frames[i].cels, len(f.layers))
+	}
+	return f, nil
+}
+
+func parseLayer(r *binaryReader, opacityValid bool) layer {
+	flags := r.word()
+	layerType := r.word()
+	childLevel := r.word()
+	r.skip(2 + 2 + 2) // default width, default height and blend mode
+	opacity := r.byte()
+	r.skip(3)
+	l := layer{
+		Layer: Layer{
+			Name:       r.string(),
+			Visible:    flags&layerFlagVisible != 0,
+			Group:      layerType == layerTypeGroup,
+			ChildLevel: int(childLevel),
+			Opacity:    255,
+		},
+	}
+	if opacityValid {
+		l.Opacity = opacity
+	}
+	return l
+}
+
+func parseCel(r *binaryReader, f *file, fr *frame) error {
+	layerIndex := int(r.word())
+	c := &cel{
+		x: int(r.short()),
+		y: int(r.short()),
+	}
+	c.opacity = r.byte()
+	celType := r.word()
+	r.skip(2 + 5) // z-index and reserved bytes
+	switch celType {
+	case celTypeRaw:
+		c.width = int(r.word())
+		c.height = int(r.word())
+		c.pixels = r.bytes(c.width * c.height * f.bytesPerPixel())
+	case celTypeLinked:
+		linkedFrame := int(r.word())
+		if linkedFrame >= len(f.frames) {
+			return errors.New("invalid Aseprite linked cel")
+		}
+		cels := f.frames[linkedFrame].cels
+		if layerIndex >= len(cels) || cels[layerIndex] == nil {
+			return nil
+		}
+		c = cels[layerIndex]
+	case celTypeCompressed:
+		c.width = int(r.word())
+		c.height = int(r.word())
+		pixels, err := decompress(r.remaining(), c.width*c.height*f.bytesPerPixel())
+		if err != nil {
+			return err
+		}
+		c.pixels = pixels
+	default:
+		// tilemaps are not supported
+		return nil
+	}
+	if r.err != nil {
+		return r.err
+	}
+	fr.cels = resizeCels(fr.cels, layerIndex+1)
+	fr.cels[layerIndex] = c
+	return nil
+}
+
+func decompress(data []byte, size int) ([]byte, error) {
+	zlibReader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zlibReader.Close()
+	// one byte more is read to find out if data is bigger than expected
+	pixels, err := ioutil.ReadAll(io.LimitReader(zlibReader, int64(size)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(pixels) < size {
+		return nil, errUnexpectedEOF
+	}
+	if len(pixels) > size {
+		return nil, errors.New("invalid Aseprite compressed cel size")
+	}
+	return pixels, nil
+}
+
+func parseTags(r *binaryReader) []Tag {
+	count := int(r.word())
+	r.skip(8)
+	var tags []Tag
+	for i := 0; i < count && r.err == nil; i++ {
+		tag := Tag{
+			From:      int(r.word()),
+			To:        int(r.word()),
+			Direction: Direction(r.byte()),
+			Repeat:    int(r.word()),
+		}
+		r.skip(6 + 3 + 1) // reserved bytes and deprecated color
+		tag.Name = r.string()
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func parsePalette(r *binaryReader, palette []image.Color) []image.Color {
+	size := int(r.dword())
+	first := int(r.dword())
+	last := int(r.dword())
+	r.skip(8)
+	palette = resizePalette(palette, size)
+	for i := first; i <= last && i < size && r.err == nil; i++ {
+		flags := r.word()
+		palette[i] = image.NRGBA(r.byte(), r.byte(), r.byte(), r.byte())
+		if flags&paletteEntryHasName != 0 {
+			r.string()
+		}
+	}
+	return palette
+}
+
+func parseOldPalette(r *binaryReader, palette []image.Color) []image.Color {
+	packets := int(r.word())
+	index := 0
+	for i := 0; i < packets && r.err == nil; i++ {
+		index += int(r.byte())
+		count := int(r.byte())
+		if count == 0 {
+			count = 256
+		}
+		palette = resizePalette(palette, index+count)
+		for j := 0; j < count; j++ {
+			palette[index] = image.RGB(r.byte(), r.byte(), r.byte())
+			index++
+		}
+	}
+	return palette
+}
+
+func resizePalette(palette []image.Color, size int) []image.Color {
+	for len(palette) < size {
+		palette = append(palette, image.Transparent)
+	}
+	return palette
+}
+
+func resizeCels(cels []*cel, size int) []*cel {
+	for len(cels) < size {
+		cels = append(cels, nil)
+	}
+	return cels[:size]
+}
+
+func (f *file) bytesPerPixel() int {
+	return f.depth / 8
+}
+
+// pixel returns premultiplied color of the cel pixel
+func (f *file) pixel(c *cel, x, y int) image.Color {
+	i := (x + y*c.width) * f.bytesPerPixel()
+	switch f.depth {
+	case depthRGBA:
+		return image.NRGBA(c.pixels[i], c.pixels[i+1], c.pixels[i+2], c.pixels[i+3])
+	case depthGrayscale:
+		value := c.pixels[i]
+		return image.NRGBA(value, value, value, c.pixels[i+1])
+	default:
+		index := c.pixels[i]
+		if index == f.transparentIndex || int(index) >= len(f.palette) {
+			return image.Transparent
+		}
+		return f.palette[index]
+	}
+}
+
+// binaryReader reads little-endian values. After the first read beyond the data
+// all subsequent reads return zero values and err is set.
+type binaryReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *binaryReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.offset+n > len(r.data) {
+		r.err = errUnexpectedEOF
+		return nil
+	}
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return b
+}
+
+func (r *binaryReader) remaining() []byte {
+	return r.bytes(len(r.data) - r.offset)
+}
+
+func (r *binaryReader) skip(n int) {
+	r.bytes(n)
+}
+
+func (r *binaryReader) byte() byte {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *binaryReader) word() uint16 {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return uint16(b[0]) | uint16(b[1])<<8
+}
+
+func (r *binaryReader) short() int16 {
+	return int16(r.word())
+}
+
+func (r *binaryReader) dword() uint32 {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+func (r *binaryReader) string() string {
+	length := int(r.word())
+	return string(r.bytes(length))
+}
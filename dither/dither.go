@@ -0,0 +1,240 @@
+// Package dither provides CPU tools for reducing colors of images to a limited
+// palette using dithering. Dithering mixes palette colors in patterns,
+// so that gradients are still visible even when palette has a few colors:
+//
+//	tool := dither.NewFloydSteinberg(palette.Palette{black, white})
+//	tool.Dither(screen)
+package dither
+
+import (
+	"math"
+
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/palette"
+)
+
+// NewBayer creates an ordered dithering tool using Bayer threshold matrix with
+// a given size. Ordered dithering produces regular cross-hatch patterns and
+// is stable - same colors are always dithered the same way at the same position.
+//
+// Will panic if palette is empty or matrixSize is not 2, 4 or 8.
+func NewBayer(p palette.Palette, matrixSize int) *Ordered {
+	if len(p) == 0 {
+		panic("empty palette")
+	}
+	if matrixSize != 2 && matrixSize != 4 && matrixSize != 8 {
+		panic("matrixSize must be 2, 4 or 8")
+	}
+	matrix := bayerMatrix(matrixSize)
+	// spread is the maximum offset added to color components. It depends on
+	// the average distance between palette colors.
+	spread := int(255 / math.Max(1, math.Cbrt(float64(len(p)))))
+	cells := matrixSize * matrixSize
+	offsets := make([]int, cells)
+	for i, threshold := range matrix {
+		offsets[i] = spread * (2*threshold + 1 - cells) / (2 * cells)
+	}
+	return &Ordered{
+		palette:    copyPalette(p),
+		offsets:    offsets,
+		matrixSize: matrixSize,
+	}
+}
+
+// bayerMatrix returns thresholds from 0 to size*size-1 in row-major order
+func bayerMatrix(size int) []int {
+	matrix := []int{0}
+	for n := 1; n < size; n *= 2 {
+		next := make([]int, 4*n*n)
+		for y := 0; y < n; y++ {
+			for x := 0; x < n; x++ {
+				m := 4 * matrix[y*n+x]
+				next[y*2*n+x] = m
+				next[y*2*n+x+n] = m + 2
+				next[(y+n)*2*n+x] = m + 3
+				next[(y+n)*2*n+x+n] = m + 1
+			}
+		}
+		matrix = next
+	}
+	return matrix
+}
+
+// Ordered is an ordered dithering tool.
+//
+// Ordered uses CPU.
+type Ordered struct {
+	palette    palette.Palette
+	offsets    []int
+	matrixSize int
+}
+
+// Dither replaces each pixel of selection with a palette color. The pattern is
+// aligned to the selection's top-left corner.
+func (o *Ordered) Dither(selection image.Selection) {
+	var (
+		lines   = selection.Lines()
+		xOffset = lines.XOffset()
+		yOffset = lines.YOffset()
+	)
+	for y := 0; y < lines.Length(); y++ {
+		line := lines.LineForWrite(y)
+		row := ((y + yOffset) % o.matrixSize) * o.matrixSize
+		for x := 0; x < len(line); x++ {
+			offset := o.offsets[row+(x+xOffset)%o.matrixSize]
+			r, g, b, a := line[x].RGBAi()
+			color := image.RGBAi(clamp(r+offset, a), clamp(g+offset, a), clamp(b+offset, a), a)
+			line[x] = o.palette.Nearest(color)
+		}
+	}
+}
+
+// NewFloydSteinberg creates an error-diffusion dithering tool using
+// Floyd-Steinberg algorithm.
+//
+// Will panic if palette is empty.
+func NewFloydSteinberg(p palette.Palette) *ErrorDiffusion {
+	return newErrorDiffusion(p, 16, []diffusion{
+		{x: 1, y: 0, weight: 7},
+		{x: -1, y: 1, weight: 3}, {x: 0, y: 1, weight: 5}, {x: 1, y: 1, weight: 1},
+	})
+}
+
+// NewAtkinson creates an error-diffusion dithering tool using Atkinson algorithm.
+// Only 3/4 of the error is diffused, which gives images with higher contrast.
+//
+// Will panic if palette is empty.
+func NewAtkinson(p palette.Palette) *ErrorDiffusion {
+	return newErrorDiffusion(p, 8, []diffusion{
+		{x: 1, y: 0, weight: 1}, {x: 2, y: 0, weight: 1},
+		{x: -1, y: 1, weight: 1}, {x: 0, y: 1, weight: 1}, {x: 1, y: 1, weight: 1},
+		{x: 0, y: 2, weight: 1},
+	})
+}
+
+// NewSierra creates an error-diffusion dithering tool using Sierra
+// (aka Sierra-3) algorithm.
+//
+// Will panic if palette is empty.
+func NewSierra(p palette.Palette) *ErrorDiffusion {
+	return newErrorDiffusion(p, 32, []diffusion{
+		{x: 1, y: 0, weight: 5}, {x: 2, y: 0, weight: 3},
+		{x: -2, y: 1, weight: 2}, {x: -1, y: 1, weight: 4}, {x: 0, y: 1, weight: 5}, {x: 1, y: 1, weight: 4}, {x: 2, y: 1, weight: 2},
+		{x: -1, y: 2, weight: 2}, {x: 0, y: 2, weight: 3}, {x: 1, y: 2, weight: 2},
+	})
+}
+
+// diffusion describes which part of the error is moved to the neighbour pixel
+type diffusion struct {
+	x, y, weight int
+}
+
+// maxDistance is the maximum distance of pixels receiving the error
+const maxDistance = 2
+
+func newErrorDiffusion(p palette.Palette, divisor int, diffusions []diffusion) *ErrorDiffusion {
+	if len(p) == 0 {
+		panic("empty palette")
+	}
+	return &ErrorDiffusion{
+		palette:    copyPalette(p),
+		divisor:    divisor,
+		diffusions: diffusions,
+	}
+}
+
+// ErrorDiffusion is an error-diffusion dithering tool. The difference between
+// the original color and the palette color (the error) is distributed to
+// neighbour pixels which are not processed yet.
+//
+// ErrorDiffusion uses CPU.
+type ErrorDiffusion struct {
+	palette    palette.Palette
+	divisor    int
+	diffusions []diffusion
+	// errors contains accumulated RGB errors for the current and next rows.
+	// Each row has margins, so there is no need to check boundaries.
+	errors [maxDistance + 1][]int
+}
+
+// Dither replaces each pixel of selection with a palette color. Pixels outside
+// the image are skipped.
+func (d *ErrorDiffusion) Dither(selection image.Selection) {
+	lines := selection.Lines()
+	if lines.Length() == 0 {
+		return
+	}
+	width := len(lines.LineForRead(0))
+	d.resetErrors(width)
+	for y := 0; y < lines.Length(); y++ {
+		line := lines.LineForWrite(y)
+		current := d.errors[0]
+		for x := 0; x < len(line); x++ {
+			i := (x + maxDistance) * 3
+			r, g, b, a := line[x].RGBAi()
+			color := image.RGBAi(
+				clamp(r+current[i]/d.divisor, a),
+				clamp(g+current[i+1]/d.divisor, a),
+				clamp(b+current[i+2]/d.divisor, a),
+				a,
+			)
+			nearest := d.palette.Nearest(color)
+			line[x] = nearest
+			colorR, colorG, colorB, _ := color.RGBAi()
+			nearestR, nearestG, nearestB, _ := nearest.RGBAi()
+			errR := colorR - nearestR
+			errG := colorG - nearestG
+			errB := colorB - nearestB
+			for _, diffusion := range d.diffusions {
+				row := d.errors[diffusion.y]
+				j := (x + maxDistance + diffusion.x) * 3
+				row[j] += errR * diffusion.weight
+				row[j+1] += errG * diffusion.weight
+				row[j+2] += errB * diffusion.weight
+			}
+		}
+		d.nextRow()
+	}
+}
+
+func (d *ErrorDiffusion) resetErrors(width int) {
+	size := (width + 2*maxDistance) * 3
+	for i := range d.errors {
+		if cap(d.errors[i]) < size {
+			d.errors[i] = make([]int, size)
+		}
+		d.errors[i] = d.errors[i][:size]
+		clearErrors(d.errors[i])
+	}
+}
+
+// nextRow moves rows up and reuses the first one as the last one
+func (d *ErrorDiffusion) nextRow() {
+	first := d.errors[0]
+	copy(d.errors[:], d.errors[1:])
+	clearErrors(first)
+	d.errors[maxDistance] = first
+}
+
+func clearErrors(errors []int) {
+	for i := range errors {
+		errors[i] = 0
+	}
+}
+
+// clamp limits premultiplied color component to range [0, alpha]
+func clamp(component, alpha int) int {
+	if component < 0 {
+		return 0
+	}
+	if component > alpha {
+		return alpha
+	}
+	return component
+}
+
+func copyPalette(p palette.Palette) palette.Palette {
+	c := make(palette.Palette, len(p))
+	copy(c, p)
+	return c
+}
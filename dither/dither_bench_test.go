@@ -0,0 +1,33 @@
+package dither_test
+
+import (
+	"testing"
+
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/palette"
+)
+
+func BenchmarkDither(b *testing.B) {
+	p := palette.Palette{black, white, red, image.RGB(0, 255, 0), image.RGB(0, 0, 255)}
+	for name, tool := range tools(p) {
+		b.Run(name, func(b *testing.B) {
+			var (
+				img       = image.New(fake.NewAcceleratedImage(320, 180))
+				selection = img.WholeImageSelection()
+			)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				for y := 0; y < img.Height(); y++ {
+					for x := 0; x < img.Width(); x++ {
+						selection.SetColor(x, y, image.RGB(byte(x), byte(y), byte(x+y)))
+					}
+				}
+				b.StartTimer()
+				tool.Dither(selection)
+			}
+		})
+	}
+}
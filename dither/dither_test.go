@@ -0,0 +1,203 @@
+package dither_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/dither"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/palette"
+)
+
+var (
+	black      = image.RGB(0, 0, 0)
+	white      = image.RGB(255, 255, 255)
+	red        = image.RGB(255, 0, 0)
+	gray       = image.RGB(127, 127, 127)
+	blackWhite = palette.Palette{black, white}
+)
+
+type ditherer interface {
+	Dither(selection image.Selection)
+}
+
+func tools(p palette.Palette) map[string]ditherer {
+	return map[string]ditherer{
+		"Bayer 2x2":       dither.NewBayer(p, 2),
+		"Bayer 4x4":       dither.NewBayer(p, 4),
+		"Bayer 8x8":       dither.NewBayer(p, 8),
+		"Floyd-Steinberg": dither.NewFloydSteinberg(p),
+		"Atkinson":        dither.NewAtkinson(p),
+		"Sierra":          dither.NewSierra(p),
+	}
+}
+
+func TestNewBayer(t *testing.T) {
+	t.Run("should panic when palette is empty", func(t *testing.T) {
+		assert.Panics(t, func() {
+			dither.NewBayer(palette.Palette{}, 2)
+		})
+	})
+	t.Run("should panic for unsupported matrix size", func(t *testing.T) {
+		for _, size := range []int{-1, 0, 1, 3, 16} {
+			assert.Panics(t, func() {
+				dither.NewBayer(blackWhite, size)
+			}, "size %d", size)
+		}
+	})
+}
+
+func TestNewErrorDiffusion(t *testing.T) {
+	constructors := map[string]func(palette.Palette) *dither.ErrorDiffusion{
+		"Floyd-Steinberg": dither.NewFloydSteinberg,
+		"Atkinson":        dither.NewAtkinson,
+		"Sierra":          dither.NewSierra,
+	}
+	for name, newTool := range constructors {
+		t.Run(name+" should panic when palette is empty", func(t *testing.T) {
+			assert.Panics(t, func() {
+				newTool(palette.Palette{})
+			})
+		})
+	}
+}
+
+func TestDither(t *testing.T) {
+	for name, tool := range tools(blackWhite) {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should not change palette colors", func(t *testing.T) {
+				img := newImage(4, 4, white)
+				selection := img.WholeImageSelection()
+				selection.SetColor(1, 1, black)
+				selection.SetColor(3, 2, black)
+				// when
+				tool.Dither(selection)
+				// then
+				assertColorAt(t, img, 0, 0, white)
+				assertColorAt(t, img, 1, 1, black)
+				assertColorAt(t, img, 3, 2, black)
+				assertColorAt(t, img, 3, 3, white)
+			})
+			t.Run("should mix palette colors to get gray", func(t *testing.T) {
+				img := newImage(16, 16, gray)
+				// when
+				tool.Dither(img.WholeImageSelection())
+				// then
+				counts := countColors(img)
+				assert.Len(t, counts, 2)
+				assert.InDelta(t, 128, counts[white], 24)
+				assert.InDelta(t, 128, counts[black], 24)
+			})
+			t.Run("should dither only selection", func(t *testing.T) {
+				img := newImage(3, 3, gray)
+				// when
+				tool.Dither(img.Selection(1, 1).WithSize(1, 1))
+				// then
+				counts := countColors(img)
+				assert.Equal(t, 8, counts[gray])
+			})
+			t.Run("should dither selection partially outside the image", func(t *testing.T) {
+				img := newImage(2, 2, red)
+				// when
+				tool.Dither(img.Selection(-1, -1).WithSize(4, 4))
+				// then
+				counts := countColors(img)
+				assert.Equal(t, 0, counts[red])
+			})
+			t.Run("should skip selection outside the image", func(t *testing.T) {
+				img := newImage(1, 1, red)
+				// when
+				tool.Dither(img.Selection(0, 1).WithSize(1, 1))
+				tool.Dither(img.Selection(1, 0).WithSize(1, 1))
+				// then
+				assertColorAt(t, img, 0, 0, red)
+			})
+		})
+	}
+}
+
+func TestOrdered_Dither(t *testing.T) {
+	t.Run("should draw checkerboard for 50% gray", func(t *testing.T) {
+		img := newImage(4, 2, gray)
+		tool := dither.NewBayer(blackWhite, 2)
+		// when
+		tool.Dither(img.WholeImageSelection())
+		// then
+		assertPixels(t, img, []string{
+			".#.#",
+			"#.#.",
+		})
+	})
+	t.Run("should align pattern to selection", func(t *testing.T) {
+		img := newImage(3, 2, gray)
+		tool := dither.NewBayer(blackWhite, 2)
+		// when
+		tool.Dither(img.Selection(1, 0).WithSize(2, 2))
+		// then
+		selection := img.WholeImageSelection()
+		assert.Equal(t, black, selection.Color(1, 0))
+		assert.Equal(t, white, selection.Color(2, 0))
+	})
+}
+
+func TestErrorDiffusion_Dither(t *testing.T) {
+	t.Run("Floyd-Steinberg should diffuse error to the right", func(t *testing.T) {
+		img := newImage(4, 1, image.RGB(128, 128, 128))
+		tool := dither.NewFloydSteinberg(blackWhite)
+		// when
+		tool.Dither(img.WholeImageSelection())
+		// then
+		assertPixels(t, img, []string{"#.#."})
+	})
+	t.Run("should not diffuse error between calls", func(t *testing.T) {
+		tool := dither.NewFloydSteinberg(blackWhite)
+		img1 := newImage(1, 1, image.RGB(128, 128, 128))
+		img2 := newImage(1, 1, image.RGB(128, 128, 128))
+		// when
+		tool.Dither(img1.WholeImageSelection())
+		tool.Dither(img2.WholeImageSelection())
+		// then
+		assertColorAt(t, img2, 0, 0, white)
+	})
+}
+
+func newImage(width, height int, color image.Color) *image.Image {
+	img := image.New(fake.NewAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, color)
+		}
+	}
+	return img
+}
+
+func countColors(img *image.Image) map[image.Color]int {
+	counts := map[image.Color]int{}
+	selection := img.WholeImageSelection()
+	for y := 0; y < img.Height(); y++ {
+		for x := 0; x < img.Width(); x++ {
+			counts[selection.Color(x, y)]++
+		}
+	}
+	return counts
+}
+
+func assertColorAt(t *testing.T, img *image.Image, x, y int, expected image.Color) {
+	assert.Equal(t, expected, img.WholeImageSelection().Color(x, y), "position (%d,%d)", x, y)
+}
+
+// assertPixels asserts black (.) and white (#) pixels
+func assertPixels(t *testing.T, img *image.Image, expected []string) {
+	for y, line := range expected {
+		for x := 0; x < len(line); x++ {
+			expectedColor := black
+			if line[x] == '#' {
+				expectedColor = white
+			}
+			assertColorAt(t, img, x, y, expectedColor)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package draw provides CPU tools for drawing pixel-perfect primitives such as
+// lines, rectangles and ellipses:
+//
+//	tool := draw.New()
+//	tool.SetColor(colornames.White)
+//	tool.Line(screen, 0, 0, 10, 5)
+//	tool.FilledRectangle(screen, 2, 2, 4, 3)
+//
+// All methods use local coordinates of the passed image.Selection. Similar to
+// image.Selection.SetColor pixels outside the image boundaries are skipped, but
+// it is possible to draw outside the selection.
+package draw
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// New returns new instance of *draw.Tool
+func New() *Tool {
+	return &Tool{}
+}
+
+// Tool is a drawing tool. It rasterizes primitives into image.Selection using
+// previously set color. Colors are not blended - pixels are simply replaced.
+//
+// Tool uses CPU.
+type Tool struct {
+	color image.Color
+}
+
+// Point is a position in local coordinates of image.Selection
+type Point struct {
+	X, Y int
+}
+
+// SetColor sets color which will be used by all drawing methods
+func (t *Tool) SetColor(color image.Color) {
+	t.color = color
+}
+
+// Line draws a line from (x1,y1) to (x2,y2) using Bresenham's algorithm.
+// Both ends of the line are drawn.
+func (t *Tool) Line(selection image.Selection, x1, y1, x2, y2 int) {
+	if y1 == y2 {
+		if x1 > x2 {
+			x1, x2 = x2, x1
+		}
+		t.fill(selection, x1, y1, x2-x1+1, 1)
+		return
+	}
+	if x1 == x2 {
+		if y1 > y2 {
+			y1, y2 = y2, y1
+		}
+		t.fill(selection, x1, y1, 1, y2-y1+1)
+		return
+	}
+	dx := abs(x2 - x1)
+	dy := -abs(y2 - y1)
+	stepX := 1
+	if x1 > x2 {
+		stepX = -1
+	}
+	stepY := 1
+	if y1 > y2 {
+		stepY = -1
+	}
+	err := dx + dy
+	for {
+		selection.SetColor(x1, y1, t.color)
+		if x1 == x2 && y1 == y2 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x1 += stepX
+		}
+		if e2 <= dx {
+			err += dx
+			y1 += stepY
+		}
+	}
+}
+
+// Polyline draws lines connecting consecutive points. Nothing is drawn when
+// no points are given. Single point is drawn as a pixel.
+func (t *Tool) Polyline(selection image.Selection, points ...Point) {
+	if len(points) == 1 {
+		selection.SetColor(points[0].X, points[0].Y, t.color)
+		return
+	}
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		t.Line(selection, from.X, from.Y, to.X, to.Y)
+	}
+}
+
+// Rectangle draws the outline of rectangle with top-left corner at (x,y).
+// Nothing is drawn when width or height is not positive.
+func (t *Tool) Rectangle(selection image.Selection, x, y, width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	t.fill(selection, x, y, width, 1)
+	if height == 1 {
+		return
+	}
+	t.fill(selection, x, y+height-1, width, 1)
+	t.fill(selection, x, y+1, 1, height-2)
+	if width == 1 {
+		return
+	}
+	t.fill(selection, x+width-1, y+1, 1, height-2)
+}
+
+// FilledRectangle fills the rectangle with top-left corner at (x,y).
+// Nothing is drawn when width or height is not positive.
+func (t *Tool) FilledRectangle(selection image.Selection, x, y, width, height int) {
+	t.fill(selection, x, y, width, height)
+}
+
+// Ellipse draws the outline of ellipse inscribed in a rectangle with top-left
+// corner at (x,y). Nothing is drawn when width or height is not positive.
+func (t *Tool) Ellipse(selection image.Selection, x, y, width, height int) {
+	t.ellipse(x, y, width, height, func(left, right, y int) {
+		selection.SetColor(left, y, t.color)
+		selection.SetColor(right, y, t.color)
+	})
+}
+
+// FilledEllipse fills the ellipse inscribed in a rectangle with top-left corner
+// at (x,y). Nothing is drawn when width or height is not positive.
+func (t *Tool) FilledEllipse(selection image.Selection, x, y, width, height int) {
+	t.ellipse(x, y, width, height, func(left, right, y int) {
+		t.fill(selection, left, y, right-left+1, 1)
+	})
+}
+
+// ellipse rasterizes the ellipse using Alois Zingl's algorithm for ellipses
+// specified by a rectangle. For each rasterized row the span function is
+// called with leftmost and rightmost pixel of the outline.
+//
+// See http://members.chello.at/~easyfilter/bresenham.html
+func (t *Tool) ellipse(x, y, width, height int, span func(left, right, y int)) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	var (
+		a   = width - 1
+		b   = height - 1
+		b1  = b & 1
+		dx  = 4 * (1 - a) * b * b
+		dy  = 4 * (b1 + 1) * a * a
+		err = dx + dy + b1*a*a
+		x0  = x
+		x1  = x + a
+		y0  = y + (b+1)/2
+		y1  = y0 - b1
+		// rows drawn so far
+		top, bottom = y1, y0
+	)
+	a *= 8 * a
+	b1 = 8 * b * b
+	for x0 <= x1 {
+		span(x0, x1, y0)
+		span(x0, x1, y1)
+		bottom, top = y0, y1
+		e2 := 2 * err
+		if e2 <= dy {
+			y0++
+			y1--
+			dy += a
+			err += dy
+		}
+		if e2 >= dx || 2*err > dy {
+			x0++
+			x1--
+			dx += b1
+			err += dx
+		}
+	}
+	// finish tips of flat ellipses
+	for row := bottom + 1; row < y+height; row++ {
+		span(x0-1, x1+1, row)
+	}
+	for row := top - 1; row >= y; row-- {
+		span(x0-1, x1+1, row)
+	}
+}
+
+// fill sets the color of all pixels in a given rectangle using Lines
+func (t *Tool) fill(selection image.Selection, x, y, width, height int) {
+	// clamp horizontally, because Lines does not support selections
+	// placed entirely outside the image
+	imageX := selection.ImageX() + x
+	if imageX < 0 {
+		width += imageX
+		x -= imageX
+		imageX = 0
+	}
+	imageWidth := selection.Image().Width()
+	if imageX+width > imageWidth {
+		width = imageWidth - imageX
+	}
+	if width <= 0 {
+		return
+	}
+	lines := selection.Selection(x, y).WithSize(width, height).Lines()
+	for i := 0; i < lines.Length(); i++ {
+		line := lines.LineForWrite(i)
+		for j := 0; j < len(line); j++ {
+			line[j] = t.color
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,48 @@
+package draw_test
+
+import (
+	"testing"
+
+	"github.com/jacekolszak/pixiq/draw"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+func BenchmarkTool_Line(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(640, 360))
+		selection = img.WholeImageSelection()
+		tool      = draw.New()
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.Line(selection, 0, 0, 639, 359)
+	}
+}
+
+func BenchmarkTool_FilledRectangle(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(640, 360))
+		selection = img.WholeImageSelection()
+		tool      = draw.New()
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.FilledRectangle(selection, 0, 0, 640, 360)
+	}
+}
+
+func BenchmarkTool_FilledEllipse(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(640, 360))
+		selection = img.WholeImageSelection()
+		tool      = draw.New()
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.FilledEllipse(selection, 0, 0, 640, 360)
+	}
+}
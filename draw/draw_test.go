@@ -0,0 +1,426 @@
+package draw_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/draw"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+var color = image.RGBA(10, 20, 30, 40)
+
+func TestNew(t *testing.T) {
+	t.Run("should create tool", func(t *testing.T) {
+		tool := draw.New()
+		assert.NotNil(t, tool)
+	})
+}
+
+func TestTool_Line(t *testing.T) {
+	tests := map[string]struct {
+		x1, y1, x2, y2 int
+		expected       []string
+	}{
+		"single pixel": {
+			x1: 1, y1: 1, x2: 1, y2: 1,
+			expected: []string{
+				"....",
+				".X..",
+				"....",
+			},
+		},
+		"horizontal": {
+			x1: 0, y1: 1, x2: 2, y2: 1,
+			expected: []string{
+				"....",
+				"XXX.",
+				"....",
+			},
+		},
+		"horizontal reversed": {
+			x1: 3, y1: 0, x2: 1, y2: 0,
+			expected: []string{
+				".XXX",
+				"....",
+				"....",
+			},
+		},
+		"vertical": {
+			x1: 2, y1: 2, x2: 2, y2: 0,
+			expected: []string{
+				"..X.",
+				"..X.",
+				"..X.",
+			},
+		},
+		"diagonal": {
+			x1: 0, y1: 0, x2: 2, y2: 2,
+			expected: []string{
+				"X...",
+				".X..",
+				"..X.",
+			},
+		},
+		"gentle slope": {
+			x1: 0, y1: 0, x2: 3, y2: 1,
+			expected: []string{
+				"XX..",
+				"..XX",
+				"....",
+			},
+		},
+		"steep slope reversed": {
+			x1: 1, y1: 2, x2: 0, y2: 0,
+			expected: []string{
+				"X...",
+				"X...",
+				".X..",
+			},
+		},
+		"clipped by image": {
+			x1: -2, y1: -2, x2: 1, y2: 1,
+			expected: []string{
+				"X...",
+				".X..",
+				"....",
+			},
+		},
+		"outside image": {
+			x1: 5, y1: 0, x2: 7, y2: 0,
+			expected: []string{
+				"....",
+				"....",
+				"....",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := image.New(fake.NewAcceleratedImage(4, 3))
+			tool := draw.New()
+			tool.SetColor(color)
+			// when
+			tool.Line(img.WholeImageSelection(), test.x1, test.y1, test.x2, test.y2)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+	t.Run("should use local coordinates", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(4, 3))
+		tool := draw.New()
+		tool.SetColor(color)
+		// when
+		tool.Line(img.Selection(1, 1).WithSize(1, 1), 0, 0, 2, 0)
+		// then
+		assertPixels(t, img, []string{
+			"....",
+			".XXX",
+			"....",
+		})
+	})
+}
+
+func TestTool_Polyline(t *testing.T) {
+	tests := map[string]struct {
+		points   []draw.Point
+		expected []string
+	}{
+		"no points": {
+			expected: []string{
+				"....",
+				"....",
+				"....",
+			},
+		},
+		"one point": {
+			points: []draw.Point{{X: 2, Y: 1}},
+			expected: []string{
+				"....",
+				"..X.",
+				"....",
+			},
+		},
+		"three points": {
+			points: []draw.Point{{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: 2}},
+			expected: []string{
+				"XXXX",
+				"...X",
+				"...X",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := image.New(fake.NewAcceleratedImage(4, 3))
+			tool := draw.New()
+			tool.SetColor(color)
+			// when
+			tool.Polyline(img.WholeImageSelection(), test.points...)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+}
+
+func TestTool_Rectangle(t *testing.T) {
+	tests := map[string]struct {
+		x, y, width, height int
+		expected            []string
+	}{
+		"zero width": {
+			width: 0, height: 2,
+			expected: []string{
+				"....",
+				"....",
+				"....",
+			},
+		},
+		"negative height": {
+			width: 2, height: -1,
+			expected: []string{
+				"....",
+				"....",
+				"....",
+			},
+		},
+		"1x1": {
+			x: 1, y: 1, width: 1, height: 1,
+			expected: []string{
+				"....",
+				".X..",
+				"....",
+			},
+		},
+		"1x3": {
+			x: 1, width: 1, height: 3,
+			expected: []string{
+				".X..",
+				".X..",
+				".X..",
+			},
+		},
+		"4x3": {
+			width: 4, height: 3,
+			expected: []string{
+				"XXXX",
+				"X..X",
+				"XXXX",
+			},
+		},
+		"clipped": {
+			x: 2, y: -1, width: 4, height: 3,
+			expected: []string{
+				"..X.",
+				"..XX",
+				"....",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := image.New(fake.NewAcceleratedImage(4, 3))
+			tool := draw.New()
+			tool.SetColor(color)
+			// when
+			tool.Rectangle(img.WholeImageSelection(), test.x, test.y, test.width, test.height)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+}
+
+func TestTool_FilledRectangle(t *testing.T) {
+	tests := map[string]struct {
+		x, y, width, height int
+		expected            []string
+	}{
+		"zero height": {
+			width: 2,
+			expected: []string{
+				"....",
+				"....",
+				"....",
+			},
+		},
+		"2x2": {
+			x: 1, y: 1, width: 2, height: 2,
+			expected: []string{
+				"....",
+				".XX.",
+				".XX.",
+			},
+		},
+		"clipped on the left": {
+			x: -1, y: 0, width: 2, height: 2,
+			expected: []string{
+				"X...",
+				"X...",
+				"....",
+			},
+		},
+		"outside on the right": {
+			x: 4, y: 0, width: 2, height: 2,
+			expected: []string{
+				"....",
+				"....",
+				"....",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := image.New(fake.NewAcceleratedImage(4, 3))
+			tool := draw.New()
+			tool.SetColor(color)
+			// when
+			tool.FilledRectangle(img.WholeImageSelection(), test.x, test.y, test.width, test.height)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+}
+
+func TestTool_Ellipse(t *testing.T) {
+	tests := map[string]struct {
+		width, height int
+		expected      []string
+	}{
+		"zero size": {
+			expected: []string{
+				".....",
+				".....",
+				".....",
+				".....",
+				".....",
+			},
+		},
+		"1x1": {
+			width: 1, height: 1,
+			expected: []string{
+				"X....",
+				".....",
+				".....",
+				".....",
+				".....",
+			},
+		},
+		"5x1": {
+			width: 5, height: 1,
+			expected: []string{
+				"XXXXX",
+				".....",
+				".....",
+				".....",
+				".....",
+			},
+		},
+		"1x3": {
+			width: 1, height: 3,
+			expected: []string{
+				"X....",
+				"X....",
+				"X....",
+				".....",
+				".....",
+			},
+		},
+		"5x5": {
+			width: 5, height: 5,
+			expected: []string{
+				".XXX.",
+				"X...X",
+				"X...X",
+				"X...X",
+				".XXX.",
+			},
+		},
+		"4x4": {
+			width: 4, height: 4,
+			expected: []string{
+				".XX..",
+				"X..X.",
+				"X..X.",
+				".XX..",
+				".....",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := image.New(fake.NewAcceleratedImage(5, 5))
+			tool := draw.New()
+			tool.SetColor(color)
+			// when
+			tool.Ellipse(img.WholeImageSelection(), 0, 0, test.width, test.height)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+}
+
+func TestTool_FilledEllipse(t *testing.T) {
+	tests := map[string]struct {
+		x, y, width, height int
+		expected            []string
+	}{
+		"5x5": {
+			width: 5, height: 5,
+			expected: []string{
+				".XXX.",
+				"XXXXX",
+				"XXXXX",
+				"XXXXX",
+				".XXX.",
+			},
+		},
+		"5x3": {
+			width: 5, height: 3,
+			expected: []string{
+				".XXX.",
+				"XXXXX",
+				".XXX.",
+				".....",
+				".....",
+			},
+		},
+		"clipped": {
+			x: -2, y: -2, width: 5, height: 5,
+			expected: []string{
+				"XXX..",
+				"XXX..",
+				"XX...",
+				".....",
+				".....",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := image.New(fake.NewAcceleratedImage(5, 5))
+			tool := draw.New()
+			tool.SetColor(color)
+			// when
+			tool.FilledEllipse(img.WholeImageSelection(), test.x, test.y, test.width, test.height)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+}
+
+// assertPixels compares image with expected pattern, where X is a pixel
+// drawn with color and . is a transparent pixel
+func assertPixels(t *testing.T, img *image.Image, expected []string) {
+	selection := img.WholeImageSelection()
+	for y := 0; y < img.Height(); y++ {
+		for x := 0; x < img.Width(); x++ {
+			expectedColor := image.Transparent
+			if expected[y][x] == 'X' {
+				expectedColor = color
+			}
+			assert.Equal(t, expectedColor, selection.Color(x, y), "position (%d,%d)", x, y)
+		}
+	}
+}
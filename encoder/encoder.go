@@ -0,0 +1,235 @@
+// Package encoder provides functionality of encoding image.Selection into
+// compressed images such as PNG and GIF.
+package encoder
+
+import (
+	"errors"
+	stdimage "image"
+	"image/color"
+	stdpalette "image/color/palette"
+	"image/gif"
+	"image/png"
+	"os"
+
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Format is a file format of the encoded image
+type Format int
+
+const (
+	// PNG is a Portable Network Graphics format. It supports semi-transparent colors.
+	PNG Format = iota
+	// GIF is a Graphics Interchange Format. It supports at most 256 colors and
+	// only one fully transparent color. Semi-transparent colors are encoded
+	// as opaque ones.
+	GIF
+)
+
+// Writer is the equivalent of io.Writer
+type Writer interface {
+	Write(p []byte) (n int, err error)
+}
+
+// Option is an encoding option
+type Option func(opts) opts
+
+// Zoom increases the image during encoding. Zoom <= 0 is treated as zoom 1.
+func Zoom(zoom int) Option {
+	return func(o opts) opts {
+		if zoom > 0 {
+			o.zoom = zoom
+		} else {
+			o.zoom = 1
+		}
+		return o
+	}
+}
+
+// Palette sets colors used by GIF format. Each pixel is encoded using the nearest
+// color from the palette. Only first 256 colors are used. Empty palette is ignored.
+//
+// When palette is not set all distinct colors found in the selection are used.
+// If there are more than 256 such colors, standard Plan9 palette is used instead
+// and each pixel is encoded using the nearest Plan9 color (without dithering).
+// Transparent pixels are kept transparent - if there are any, the last Plan9
+// color is replaced with the transparent one.
+func Palette(palette []image.Color) Option {
+	return func(o opts) opts {
+		o.palette = palette
+		return o
+	}
+}
+
+type opts struct {
+	zoom    int
+	palette []image.Color
+}
+
+func buildOpts(options ...Option) opts {
+	o := opts{
+		zoom: 1,
+	}
+	for _, option := range options {
+		o = option(o)
+	}
+	return o
+}
+
+// Encode encodes the selection using given format and writes it to writer.
+// Pixels outside the image are encoded as transparent ones.
+func Encode(writer Writer, selection image.Selection, format Format, options ...Option) error {
+	if writer == nil {
+		panic("nil writer")
+	}
+	opts := buildOpts(options...)
+	switch format {
+	case PNG:
+		return png.Encode(writer, toNRGBA(selection, opts.zoom))
+	case GIF:
+		return encodeGIF(writer, selection, opts)
+	default:
+		return errors.New("unsupported format")
+	}
+}
+
+// EncodeFile encodes the selection using given format and saves it to a file
+// with a given name. If the file already exists it is truncated.
+func EncodeFile(fileName string, selection image.Selection, format Format, options ...Option) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	err = Encode(file, selection, format, options...)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+func toNRGBA(selection image.Selection, zoom int) *stdimage.NRGBA {
+	target := stdimage.NewNRGBA(stdimage.Rect(0, 0, selection.Width()*zoom, selection.Height()*zoom))
+	for y := 0; y < selection.Height(); y++ {
+		for x := 0; x < selection.Width(); x++ {
+			c := straightColor(selection.Color(x, y))
+			for zy := 0; zy < zoom; zy++ {
+				for zx := 0; zx < zoom; zx++ {
+					target.SetNRGBA(x*zoom+zx, y*zoom+zy, c)
+				}
+			}
+		}
+	}
+	return target
+}
+
+// straightColor converts premultiplied image.Color into color.NRGBA
+// (color not premultiplied by alpha)
+func straightColor(c image.Color) color.NRGBA {
+	r, g, b, a := c.RGBAi()
+	if a == 0 {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{
+		R: unpremultiply(r, a),
+		G: unpremultiply(g, a),
+		B: unpremultiply(b, a),
+		A: byte(a),
+	}
+}
+
+func unpremultiply(component, alpha int) byte {
+	v := (component*255 + alpha/2) / alpha
+	if v > 255 {
+		v = 255
+	}
+	return byte(v)
+}
+
+// gifColor converts image.Color into color supported by GIF, that is either
+// fully opaque or fully transparent
+func gifColor(c image.Color) color.NRGBA {
+	straight := straightColor(c)
+	if straight.A > 0 {
+		straight.A = 255
+	}
+	return straight
+}
+
+func encodeGIF(writer Writer, selection image.Selection, opts opts) error {
+	palette := gifPalette(selection, opts.palette)
+	if palette == nil {
+		palette = plan9Palette(selection)
+	}
+	bounds := stdimage.Rect(0, 0, selection.Width()*opts.zoom, selection.Height()*opts.zoom)
+	target := stdimage.NewPaletted(bounds, palette)
+	indexes := map[color.NRGBA]uint8{}
+	for y := 0; y < selection.Height(); y++ {
+		for x := 0; x < selection.Width(); x++ {
+			c := gifColor(selection.Color(x, y))
+			index, ok := indexes[c]
+			if !ok {
+				index = uint8(palette.Index(c))
+				indexes[c] = index
+			}
+			for zy := 0; zy < opts.zoom; zy++ {
+				for zx := 0; zx < opts.zoom; zx++ {
+					target.SetColorIndex(x*opts.zoom+zx, y*opts.zoom+zy, index)
+				}
+			}
+		}
+	}
+	return gif.Encode(writer, target, nil)
+}
+
+// gifPalette returns palette used for encoding GIF or nil if palette could not
+// be created.
+func gifPalette(selection image.Selection, colors []image.Color) color.Palette {
+	if len(colors) > 0 {
+		if len(colors) > 256 {
+			colors = colors[:256]
+		}
+		palette := make(color.Palette, len(colors))
+		for i, c := range colors {
+			palette[i] = gifColor(c)
+		}
+		return palette
+	}
+	var (
+		palette color.Palette
+		unique  = map[color.NRGBA]struct{}{}
+	)
+	for y := 0; y < selection.Height(); y++ {
+		for x := 0; x < selection.Width(); x++ {
+			c := gifColor(selection.Color(x, y))
+			if _, ok := unique[c]; ok {
+				continue
+			}
+			if len(palette) == 256 {
+				return nil
+			}
+			unique[c] = struct{}{}
+			palette = append(palette, c)
+		}
+	}
+	if len(palette) == 0 {
+		palette = color.Palette{color.NRGBA{}}
+	}
+	return palette
+}
+
+// plan9Palette returns a copy of standard Plan9 palette. The last color is
+// replaced with transparent one when the selection has transparent pixels.
+func plan9Palette(selection image.Selection) color.Palette {
+	palette := make(color.Palette, len(stdpalette.Plan9))
+	copy(palette, stdpalette.Plan9)
+	for y := 0; y < selection.Height(); y++ {
+		for x := 0; x < selection.Width(); x++ {
+			if gifColor(selection.Color(x, y)).A == 0 {
+				palette[len(palette)-1] = color.NRGBA{}
+				return palette
+			}
+		}
+	}
+	return palette
+}
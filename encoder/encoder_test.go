@@ -0,0 +1,286 @@
+package encoder_test
+
+import (
+	"bytes"
+	"errors"
+	stdimage "image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/encoder"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+func TestEncode(t *testing.T) {
+	t.Run("should panic for nil writer", func(t *testing.T) {
+		selection := newImage(1, 1).WholeImageSelection()
+		assert.Panics(t, func() {
+			_ = encoder.Encode(nil, selection, encoder.PNG)
+		})
+	})
+	t.Run("should return error for unsupported format", func(t *testing.T) {
+		selection := newImage(1, 1).WholeImageSelection()
+		// when
+		err := encoder.Encode(&bytes.Buffer{}, selection, encoder.Format(-1))
+		// then
+		assert.Error(t, err)
+	})
+	t.Run("should return error when writer returned error", func(t *testing.T) {
+		formats := map[string]encoder.Format{
+			"png": encoder.PNG,
+			"gif": encoder.GIF,
+		}
+		for name, format := range formats {
+			t.Run(name, func(t *testing.T) {
+				selection := newImage(1, 1).WholeImageSelection()
+				writer := &erroneousWriter{err: errors.New("writer error")}
+				// when
+				err := encoder.Encode(writer, selection, format)
+				// then
+				assert.Error(t, err)
+			})
+		}
+	})
+	t.Run("should encode PNG", func(t *testing.T) {
+		tests := map[string]struct {
+			pixels   [][]image.Color
+			expected [][]color.NRGBA
+		}{
+			"1x2": {
+				pixels: [][]image.Color{
+					{image.RGB(10, 20, 30)},
+					{image.RGB(40, 50, 60)},
+				},
+				expected: [][]color.NRGBA{
+					{{R: 10, G: 20, B: 30, A: 255}},
+					{{R: 40, G: 50, B: 60, A: 255}},
+				},
+			},
+			"2x1": {
+				pixels: [][]image.Color{
+					{image.RGB(10, 20, 30), image.RGB(40, 50, 60)},
+				},
+				expected: [][]color.NRGBA{
+					{{R: 10, G: 20, B: 30, A: 255}, {R: 40, G: 50, B: 60, A: 255}},
+				},
+			},
+			"transparent": {
+				pixels: [][]image.Color{
+					{image.RGBA(1, 2, 3, 0)},
+				},
+				expected: [][]color.NRGBA{
+					{{}},
+				},
+			},
+			"semi-transparent": {
+				pixels: [][]image.Color{
+					{image.NRGBA(200, 100, 50, 51)},
+				},
+				expected: [][]color.NRGBA{
+					{{R: 200, G: 100, B: 50, A: 51}},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				selection := newImageWithPixels(test.pixels).WholeImageSelection()
+				buffer := &bytes.Buffer{}
+				// when
+				err := encoder.Encode(buffer, selection, encoder.PNG)
+				// then
+				require.NoError(t, err)
+				decoded, err := png.Decode(buffer)
+				require.NoError(t, err)
+				assertImage(t, test.expected, decoded)
+			})
+		}
+	})
+	t.Run("should encode selection", func(t *testing.T) {
+		img := newImageWithPixels([][]image.Color{
+			{image.RGB(1, 1, 1), image.RGB(2, 2, 2)},
+			{image.RGB(3, 3, 3), image.RGB(4, 4, 4)},
+		})
+		selection := img.Selection(1, 1).WithSize(2, 1)
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.Encode(buffer, selection, encoder.PNG)
+		// then
+		require.NoError(t, err)
+		decoded, err := png.Decode(buffer)
+		require.NoError(t, err)
+		assertImage(t, [][]color.NRGBA{
+			{{R: 4, G: 4, B: 4, A: 255}, {}},
+		}, decoded)
+	})
+	t.Run("should encode zoomed PNG", func(t *testing.T) {
+		selection := newImageWithPixels([][]image.Color{
+			{image.RGB(10, 20, 30), image.RGB(40, 50, 60)},
+		}).WholeImageSelection()
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.Encode(buffer, selection, encoder.PNG, encoder.Zoom(2))
+		// then
+		require.NoError(t, err)
+		decoded, err := png.Decode(buffer)
+		require.NoError(t, err)
+		c1 := color.NRGBA{R: 10, G: 20, B: 30, A: 255}
+		c2 := color.NRGBA{R: 40, G: 50, B: 60, A: 255}
+		assertImage(t, [][]color.NRGBA{
+			{c1, c1, c2, c2},
+			{c1, c1, c2, c2},
+		}, decoded)
+	})
+	t.Run("should encode GIF using colors from selection", func(t *testing.T) {
+		selection := newImageWithPixels([][]image.Color{
+			{image.RGB(10, 20, 30), image.Transparent},
+			{image.RGB(40, 50, 60), image.RGB(10, 20, 30)},
+		}).WholeImageSelection()
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.Encode(buffer, selection, encoder.GIF)
+		// then
+		require.NoError(t, err)
+		decoded, err := gif.Decode(buffer)
+		require.NoError(t, err)
+		assertImage(t, [][]color.NRGBA{
+			{{R: 10, G: 20, B: 30, A: 255}, {}},
+			{{R: 40, G: 50, B: 60, A: 255}, {R: 10, G: 20, B: 30, A: 255}},
+		}, decoded)
+	})
+	t.Run("should encode GIF using given palette", func(t *testing.T) {
+		selection := newImageWithPixels([][]image.Color{
+			{image.RGB(10, 20, 30), image.RGB(200, 210, 220)},
+		}).WholeImageSelection()
+		palette := []image.Color{image.RGB(0, 0, 0), image.RGB(255, 255, 255)}
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.Encode(buffer, selection, encoder.GIF, encoder.Palette(palette), encoder.Zoom(2))
+		// then
+		require.NoError(t, err)
+		decoded, err := gif.Decode(buffer)
+		require.NoError(t, err)
+		black := color.NRGBA{A: 255}
+		white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+		assertImage(t, [][]color.NRGBA{
+			{black, black, white, white},
+			{black, black, white, white},
+		}, decoded)
+	})
+	t.Run("should encode GIF with more than 256 colors", func(t *testing.T) {
+		img := newImage(32, 9)
+		selection := img.WholeImageSelection()
+		for y := 0; y < 9; y++ {
+			for x := 0; x < 32; x++ {
+				selection.SetColor(x, y, image.RGB(byte(x*8), byte(y*28), 0))
+			}
+		}
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.Encode(buffer, selection, encoder.GIF)
+		// then
+		require.NoError(t, err)
+		decoded, err := gif.Decode(buffer)
+		require.NoError(t, err)
+		assert.Equal(t, stdimage.Rect(0, 0, 32, 9), decoded.Bounds())
+		for y := 0; y < 9; y++ {
+			for x := 0; x < 32; x++ {
+				nearest := color.Palette(palette.Plan9).Convert(color.NRGBA{R: byte(x * 8), G: byte(y * 28), A: 255})
+				assert.Equal(t, color.NRGBAModel.Convert(nearest), color.NRGBAModel.Convert(decoded.At(x, y)), "position (%d,%d)", x, y)
+			}
+		}
+	})
+	t.Run("should keep transparent pixels when encoding GIF with more than 256 colors", func(t *testing.T) {
+		img := newImage(32, 9)
+		selection := img.WholeImageSelection()
+		for y := 0; y < 9; y++ {
+			for x := 0; x < 32; x++ {
+				selection.SetColor(x, y, image.RGB(byte(x*8), byte(y*28), 0))
+			}
+		}
+		selection.SetColor(0, 0, image.Transparent)
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.Encode(buffer, selection, encoder.GIF)
+		// then
+		require.NoError(t, err)
+		decoded, err := gif.Decode(buffer)
+		require.NoError(t, err)
+		_, _, _, a := decoded.At(0, 0).RGBA()
+		assert.Equal(t, uint32(0), a)
+	})
+}
+
+func TestEncodeFile(t *testing.T) {
+	t.Run("should return error when file cannot be created", func(t *testing.T) {
+		selection := newImage(1, 1).WholeImageSelection()
+		// when
+		err := encoder.EncodeFile("", selection, encoder.PNG)
+		// then
+		assert.Error(t, err)
+	})
+	t.Run("should encode file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "TestEncodeFile")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		fileName := filepath.Join(dir, "image.png")
+		selection := newImageWithPixels([][]image.Color{
+			{image.RGB(10, 20, 30)},
+		}).WholeImageSelection()
+		// when
+		err = encoder.EncodeFile(fileName, selection, encoder.PNG)
+		// then
+		require.NoError(t, err)
+		file, err := os.Open(fileName)
+		require.NoError(t, err)
+		defer file.Close()
+		decoded, err := png.Decode(file)
+		require.NoError(t, err)
+		assertImage(t, [][]color.NRGBA{
+			{{R: 10, G: 20, B: 30, A: 255}},
+		}, decoded)
+	})
+}
+
+func assertImage(t *testing.T, expected [][]color.NRGBA, actual stdimage.Image) {
+	require.Equal(t, len(expected), actual.Bounds().Dy(), "height")
+	require.Equal(t, len(expected[0]), actual.Bounds().Dx(), "width")
+	for y, line := range expected {
+		for x, expectedColor := range line {
+			actualColor := color.NRGBAModel.Convert(actual.At(x, y))
+			assert.Equal(t, expectedColor, actualColor, "position (%d,%d)", x, y)
+		}
+	}
+}
+
+func newImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
+
+func newImageWithPixels(pixels [][]image.Color) *image.Image {
+	img := newImage(len(pixels[0]), len(pixels))
+	selection := img.WholeImageSelection()
+	for y, line := range pixels {
+		for x, c := range line {
+			selection.SetColor(x, y, c)
+		}
+	}
+	return img
+}
+
+type erroneousWriter struct {
+	err error
+}
+
+func (w *erroneousWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/jacekolszak/pixiq/colornames"
+	"github.com/jacekolszak/pixiq/draw"
+	"github.com/jacekolszak/pixiq/glfw"
+)
+
+func main() {
+	glfw.RunOrDie(func(openGL *glfw.OpenGL) {
+		window, err := openGL.OpenWindow(40, 30, glfw.Zoom(10), glfw.Title("Draw window"))
+		if err != nil {
+			panic(err)
+		}
+		tool := draw.New()
+		for {
+			screen := window.Screen()
+
+			tool.SetColor(colornames.Darkslategray)
+			tool.FilledRectangle(screen, 0, 0, 40, 30)
+
+			tool.SetColor(colornames.Yellow)
+			tool.FilledEllipse(screen, 2, 2, 9, 9)
+
+			tool.SetColor(colornames.Lightblue)
+			tool.Rectangle(screen, 14, 2, 12, 9)
+			tool.Ellipse(screen, 28, 2, 10, 7)
+
+			tool.SetColor(colornames.White)
+			tool.Line(screen, 2, 14, 37, 27)
+			tool.Polyline(screen,
+				draw.Point{X: 2, Y: 27},
+				draw.Point{X: 10, Y: 16},
+				draw.Point{X: 18, Y: 27},
+				draw.Point{X: 2, Y: 27},
+			)
+
+			window.Draw()
+			if window.ShouldClose() {
+				break
+			}
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/jacekolszak/pixiq/clear"
+	"github.com/jacekolszak/pixiq/colornames"
+	"github.com/jacekolszak/pixiq/glfw"
+	"github.com/jacekolszak/pixiq/loop"
+)
+
+func main() {
+	glfw.RunOrDie(func(openGL *glfw.OpenGL) {
+		// SwapInterval(1) enables VSync, so Draw waits for the screen refresh
+		window, err := openGL.OpenWindow(80, 20, glfw.Title("Fixed timestep loop"), glfw.Zoom(7), glfw.SwapInterval(1))
+		if err != nil {
+			log.Panicf("OpenWindow failed: %v", err)
+		}
+		// Game state is updated 30 times per second, no matter how many
+		// frames are rendered. Rendering is limited to 60 FPS.
+		gameLoop := loop.New(window, loop.UpdateRate(30), loop.TargetFPS(60))
+		var (
+			x, previousX float64
+			velocity     = 20.0 // pixels per second
+			clearTool    = clear.New()
+			lastReport   = time.Now()
+		)
+		gameLoop.Run(func(step time.Duration) {
+			previousX = x
+			x += velocity * step.Seconds()
+			if x >= 80 {
+				x, previousX = 0, 0
+			}
+			if window.ShouldClose() {
+				gameLoop.Stop()
+			}
+		}, func(alpha float64) {
+			screen := window.Screen()
+			clearTool.Clear(screen)
+			// interpolate between two last updates to get a smooth movement
+			interpolatedX := previousX + (x-previousX)*alpha
+			screen.SetColor(int(interpolatedX), 10, colornames.White)
+			if time.Since(lastReport) > time.Second {
+				stats := gameLoop.Stats()
+				log.Printf("FPS: %.1f, frame time min/avg/max: %v/%v/%v",
+					stats.FPS(), stats.Min, stats.Avg(), stats.Max)
+				gameLoop.ResetStats()
+				lastReport = time.Now()
+			}
+		})
+	})
+}
@@ -0,0 +1,238 @@
+// Package fill provides CPU flood fill tool (aka bucket tool) for selections
+//
+//	tool := fill.New()
+//	tool.SetColor(colornames.Red)
+//	tool.Fill(screen, 10, 20)
+package fill
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// New returns new instance of *fill.Tool. By default the tool uses
+// 4-connectivity and zero tolerance.
+func New() *Tool {
+	return &Tool{
+		connectivity: FourConnected,
+	}
+}
+
+// Connectivity defines which pixels are considered neighbours during flood fill
+type Connectivity int
+
+const (
+	// FourConnected means that pixels are neighbours when they touch each other
+	// with edges (left, right, top and bottom)
+	FourConnected Connectivity = iota
+	// EightConnected means that pixels are neighbours when they touch each other
+	// with edges or corners
+	EightConnected
+)
+
+// Tool is a flood fill tool. It fills the area of similar colors with previously
+// set color. The area is bounded by the selection and the image.
+//
+// Tool uses CPU.
+type Tool struct {
+	color        image.Color
+	connectivity Connectivity
+	tolerance    int
+	// following slices are reused between executions to avoid allocations
+	visited []bool
+	stack   []point
+	lines   [][]image.Color
+}
+
+type point struct {
+	x, y int
+}
+
+// SetColor sets color which will be used by Fill and Replace methods
+func (t *Tool) SetColor(color image.Color) {
+	t.color = color
+}
+
+// SetConnectivity sets which pixels are considered neighbours by Fill method
+func (t *Tool) SetConnectivity(connectivity Connectivity) {
+	t.connectivity = connectivity
+}
+
+// SetTolerance sets the maximum difference of each color component (including
+// alpha) for which colors are considered similar. Zero tolerance means
+// that only exactly the same colors are filled.
+func (t *Tool) SetTolerance(tolerance byte) {
+	t.tolerance = int(tolerance)
+}
+
+// Fill fills the area of similar colors starting at a given position.
+// Passed coordinates are local, which means that the top-left corner of selection
+// is equivalent to localX=0, localY=0. Nothing happens when the starting pixel is
+// outside the selection or the image.
+func (t *Tool) Fill(selection image.Selection, localX, localY int) {
+	area, ok := t.newArea(selection, localX, localY)
+	if !ok {
+		return
+	}
+	t.resetVisited(area.width * area.height)
+	target := area.color(area.startX, area.startY)
+	matches := func(x, y int) bool {
+		return !t.visited[x+y*area.width] && t.similar(target, area.color(x, y))
+	}
+	t.stack = append(t.stack[:0], point{x: area.startX, y: area.startY})
+	for len(t.stack) > 0 {
+		p := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		if !matches(p.x, p.y) {
+			continue
+		}
+		left := p.x
+		for left > 0 && matches(left-1, p.y) {
+			left--
+		}
+		right := p.x
+		for right < area.width-1 && matches(right+1, p.y) {
+			right++
+		}
+		line := area.lineForWrite(p.y)
+		for x := left; x <= right; x++ {
+			line[x] = t.color
+			t.visited[x+p.y*area.width] = true
+		}
+		if t.connectivity == EightConnected {
+			if left > 0 {
+				left--
+			}
+			if right < area.width-1 {
+				right++
+			}
+		}
+		if p.y > 0 {
+			t.pushSpanSeeds(left, right, p.y-1, matches)
+		}
+		if p.y < area.height-1 {
+			t.pushSpanSeeds(left, right, p.y+1, matches)
+		}
+	}
+}
+
+// pushSpanSeeds pushes the starting point of each matching run in a given row
+func (t *Tool) pushSpanSeeds(left, right, y int, matches func(x, y int) bool) {
+	previousMatched := false
+	for x := left; x <= right; x++ {
+		matched := matches(x, y)
+		if matched && !previousMatched {
+			t.stack = append(t.stack, point{x: x, y: y})
+		}
+		previousMatched = matched
+	}
+}
+
+// Replace replaces all pixels in the selection similar to the pixel at a given
+// position. Passed coordinates are local, which means that the top-left corner
+// of selection is equivalent to localX=0, localY=0. Nothing happens when the pixel
+// is outside the selection or the image.
+func (t *Tool) Replace(selection image.Selection, localX, localY int) {
+	area, ok := t.newArea(selection, localX, localY)
+	if !ok {
+		return
+	}
+	target := area.color(area.startX, area.startY)
+	for y, line := range area.lines {
+		written := false
+		for x := 0; x < len(line); x++ {
+			if t.similar(target, line[x]) {
+				if !written {
+					line = area.lineForWrite(y)
+					written = true
+				}
+				line[x] = t.color
+			}
+		}
+	}
+}
+
+func (t *Tool) similar(c1, c2 image.Color) bool {
+	if t.tolerance == 0 {
+		return c1 == c2
+	}
+	r1, g1, b1, a1 := c1.RGBAi()
+	r2, g2, b2, a2 := c2.RGBAi()
+	return abs(r1-r2) <= t.tolerance &&
+		abs(g1-g2) <= t.tolerance &&
+		abs(b1-b2) <= t.tolerance &&
+		abs(a1-a2) <= t.tolerance
+}
+
+func (t *Tool) resetVisited(size int) {
+	if cap(t.visited) < size {
+		t.visited = make([]bool, size)
+		return
+	}
+	t.visited = t.visited[:size]
+	for i := range t.visited {
+		t.visited[i] = false
+	}
+}
+
+// area is a part of selection visible in the image. Coordinates are relative
+// to the top-left corner of the area. Lines are taken for read, lineForWrite
+// must be called before updating the line, so only changed lines are marked
+// as modified.
+type area struct {
+	selectionLines image.Lines
+	lines          [][]image.Color
+	width, height  int
+	startX, startY int
+}
+
+func (t *Tool) newArea(selection image.Selection, localX, localY int) (area, bool) {
+	if localX < 0 || localY < 0 || localX >= selection.Width() || localY >= selection.Height() {
+		return area{}, false
+	}
+	left := selection.ImageX()
+	if left < 0 {
+		left = 0
+	}
+	right := selection.ImageX() + selection.Width()
+	if right > selection.Image().Width() {
+		right = selection.Image().Width()
+	}
+	width := right - left
+	lines := selection.Lines()
+	height := lines.Length()
+	if width <= 0 || height == 0 {
+		return area{}, false
+	}
+	startX := localX - lines.XOffset()
+	startY := localY - lines.YOffset()
+	if startX < 0 || startY < 0 || startX >= width || startY >= height {
+		return area{}, false
+	}
+	t.lines = t.lines[:0]
+	for y := 0; y < height; y++ {
+		t.lines = append(t.lines, lines.LineForRead(y))
+	}
+	return area{
+		selectionLines: lines,
+		lines:          t.lines,
+		width:          width,
+		height:         height,
+		startX:         startX,
+		startY:         startY,
+	}, true
+}
+
+func (a area) lineForWrite(y int) []image.Color {
+	return a.selectionLines.LineForWrite(y)
+}
+
+func (a area) color(x, y int) image.Color {
+	return a.lines[y][x]
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,39 @@
+package fill_test
+
+import (
+	"testing"
+
+	"github.com/jacekolszak/pixiq/fill"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+func BenchmarkTool_Fill(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(640, 360))
+		selection = img.WholeImageSelection()
+		tool      = fill.New()
+		colors    = []image.Color{image.RGB(255, 0, 0), image.RGB(0, 255, 0)}
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.SetColor(colors[i%2])
+		tool.Fill(selection, 320, 180)
+	}
+}
+
+func BenchmarkTool_Replace(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(640, 360))
+		selection = img.WholeImageSelection()
+		tool      = fill.New()
+		colors    = []image.Color{image.RGB(255, 0, 0), image.RGB(0, 255, 0)}
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.SetColor(colors[i%2])
+		tool.Replace(selection, 320, 180)
+	}
+}
@@ -0,0 +1,348 @@
+package fill_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/fill"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+// palette maps characters used in test patterns to colors
+var palette = map[byte]image.Color{
+	'.': image.Transparent,
+	'#': image.RGB(10, 20, 30),
+	'o': image.RGB(12, 21, 29),
+	'F': image.RGBA(50, 60, 70, 80),
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should create tool", func(t *testing.T) {
+		tool := fill.New()
+		assert.NotNil(t, tool)
+	})
+}
+
+func TestTool_Fill(t *testing.T) {
+	tests := map[string]struct {
+		pixels       []string
+		selection    func(img *image.Image) image.Selection
+		x, y         int
+		connectivity fill.Connectivity
+		tolerance    byte
+		expected     []string
+	}{
+		"whole image": {
+			pixels: []string{
+				"...",
+				"...",
+			},
+			expected: []string{
+				"FFF",
+				"FFF",
+			},
+		},
+		"area bounded by other color": {
+			pixels: []string{
+				"..#.",
+				"##..",
+				"..#.",
+			},
+			x: 3, y: 0,
+			expected: []string{
+				"..#F",
+				"##FF",
+				"..#F",
+			},
+		},
+		"concave area": {
+			pixels: []string{
+				".#..",
+				".#.#",
+				"...#",
+			},
+			expected: []string{
+				"F#FF",
+				"F#F#",
+				"FFF#",
+			},
+		},
+		"4-connectivity does not fill through corners": {
+			pixels: []string{
+				".#.",
+				"#..",
+			},
+			expected: []string{
+				"F#.",
+				"#..",
+			},
+		},
+		"8-connectivity fills through corners": {
+			pixels: []string{
+				".#.",
+				"#..",
+			},
+			connectivity: fill.EightConnected,
+			expected: []string{
+				"F#F",
+				"#FF",
+			},
+		},
+		"zero tolerance": {
+			pixels: []string{
+				"#o#",
+			},
+			expected: []string{
+				"Fo#",
+			},
+		},
+		"tolerance": {
+			pixels: []string{
+				"#o#.",
+			},
+			tolerance: 2,
+			expected: []string{
+				"FFF.",
+			},
+		},
+		"tolerance too low": {
+			pixels: []string{
+				"#o#.",
+			},
+			tolerance: 1,
+			expected: []string{
+				"Fo#.",
+			},
+		},
+		"bounded by selection": {
+			pixels: []string{
+				"...",
+				"...",
+				"...",
+			},
+			selection: func(img *image.Image) image.Selection {
+				return img.Selection(1, 1).WithSize(2, 1)
+			},
+			x: 1, y: 0,
+			expected: []string{
+				"...",
+				".FF",
+				"...",
+			},
+		},
+		"selection partially outside the image": {
+			pixels: []string{
+				"...",
+				"...",
+			},
+			selection: func(img *image.Image) image.Selection {
+				return img.Selection(-1, -1).WithSize(3, 3)
+			},
+			x: 1, y: 1,
+			expected: []string{
+				"FF.",
+				"FF.",
+			},
+		},
+		"starting point outside the selection": {
+			pixels: []string{
+				"...",
+			},
+			selection: func(img *image.Image) image.Selection {
+				return img.Selection(0, 0).WithSize(2, 1)
+			},
+			x: 2, y: 0,
+			expected: []string{
+				"...",
+			},
+		},
+		"starting point outside the image": {
+			pixels: []string{
+				"...",
+			},
+			selection: func(img *image.Image) image.Selection {
+				return img.Selection(-1, 0).WithSize(2, 1)
+			},
+			x: 0, y: 0,
+			expected: []string{
+				"...",
+			},
+		},
+		"selection outside the image": {
+			pixels: []string{
+				"...",
+			},
+			selection: func(img *image.Image) image.Selection {
+				return img.Selection(3, 0).WithSize(2, 1)
+			},
+			expected: []string{
+				"...",
+			},
+		},
+		"fill color same as target": {
+			pixels: []string{
+				"FF",
+				"F.",
+			},
+			expected: []string{
+				"FF",
+				"F.",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := newImage(test.pixels)
+			selection := img.WholeImageSelection()
+			if test.selection != nil {
+				selection = test.selection(img)
+			}
+			tool := fill.New()
+			tool.SetColor(palette['F'])
+			tool.SetConnectivity(test.connectivity)
+			tool.SetTolerance(test.tolerance)
+			// when
+			tool.Fill(selection, test.x, test.y)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+	t.Run("should reuse tool for different selections", func(t *testing.T) {
+		tool := fill.New()
+		tool.SetColor(palette['F'])
+		small := newImage([]string{"."})
+		big := newImage([]string{"..", ".."})
+		// when
+		tool.Fill(big.WholeImageSelection(), 0, 0)
+		tool.Fill(small.WholeImageSelection(), 0, 0)
+		// then
+		assertPixels(t, big, []string{"FF", "FF"})
+		assertPixels(t, small, []string{"F"})
+	})
+}
+
+func TestTool_Replace(t *testing.T) {
+	tests := map[string]struct {
+		pixels    []string
+		selection func(img *image.Image) image.Selection
+		x, y      int
+		tolerance byte
+		expected  []string
+	}{
+		"replace not connected pixels": {
+			pixels: []string{
+				".#.",
+				"#.#",
+			},
+			expected: []string{
+				"F#F",
+				"#F#",
+			},
+		},
+		"tolerance": {
+			pixels: []string{
+				"#o.",
+			},
+			tolerance: 2,
+			expected: []string{
+				"FF.",
+			},
+		},
+		"bounded by selection": {
+			pixels: []string{
+				"...",
+				"...",
+			},
+			selection: func(img *image.Image) image.Selection {
+				return img.Selection(1, 0).WithSize(2, 1)
+			},
+			expected: []string{
+				".FF",
+				"...",
+			},
+		},
+		"position outside the selection": {
+			pixels: []string{
+				"...",
+			},
+			x: -1,
+			expected: []string{
+				"...",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := newImage(test.pixels)
+			selection := img.WholeImageSelection()
+			if test.selection != nil {
+				selection = test.selection(img)
+			}
+			tool := fill.New()
+			tool.SetColor(palette['F'])
+			tool.SetTolerance(test.tolerance)
+			// when
+			tool.Replace(selection, test.x, test.y)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+}
+
+func TestTool_ModifiedLines(t *testing.T) {
+	tests := map[string]func(tool *fill.Tool, selection image.Selection){
+		"Fill": func(tool *fill.Tool, selection image.Selection) {
+			tool.Fill(selection, 0, 1)
+		},
+		"Replace": func(tool *fill.Tool, selection image.Selection) {
+			tool.Replace(selection, 0, 1)
+		},
+	}
+	for name, run := range tests {
+		t.Run(name, func(t *testing.T) {
+			acceleratedImage := fake.NewAcceleratedImage(2, 3)
+			img := image.New(acceleratedImage)
+			selection := img.WholeImageSelection()
+			selection.SetColor(0, 1, palette['#'])
+			img.Upload()
+			// pixels not uploaded by the next Upload stay as they are
+			o := palette['o']
+			acceleratedImage.Upload([]image.Color{o, o, o, o, o, o})
+			tool := fill.New()
+			tool.SetColor(palette['F'])
+			// when
+			run(tool, selection)
+			// then
+			img.Upload()
+			expected := [][]image.Color{
+				{o, o},
+				{palette['F'], image.Transparent},
+				{o, o},
+			}
+			assert.Equal(t, expected, acceleratedImage.PixelsTable())
+		})
+	}
+}
+
+func newImage(pixels []string) *image.Image {
+	img := image.New(fake.NewAcceleratedImage(len(pixels[0]), len(pixels)))
+	selection := img.WholeImageSelection()
+	for y, line := range pixels {
+		for x := 0; x < len(line); x++ {
+			selection.SetColor(x, y, palette[line[x]])
+		}
+	}
+	return img
+}
+
+func assertPixels(t *testing.T, img *image.Image, expected []string) {
+	selection := img.WholeImageSelection()
+	for y := 0; y < img.Height(); y++ {
+		for x := 0; x < img.Width(); x++ {
+			expectedColor := palette[expected[y][x]]
+			assert.Equal(t, expectedColor, selection.Color(x, y), "position (%d,%d)", x, y)
+		}
+	}
+}
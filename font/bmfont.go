@@ -0,0 +1,147 @@
+package font
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Reader is the equivalent of io.Reader
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+// DecodeBMFont decodes font descriptor in AngelCode BMFont text format (.fnt).
+// Page images referenced by the descriptor are not loaded - they should be
+// decoded separately (for example using decoder package) and passed as pages
+// in the same order as page ids.
+func DecodeBMFont(reader Reader, pages ...image.Selection) (*Font, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	font := NewFont(0)
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		tag, attributes := parseBMFontLine(scanner.Text())
+		var err error
+		switch tag {
+		case "common":
+			font.lineHeight, err = attributes.int("lineHeight")
+		case "char":
+			err = decodeBMFontChar(font, attributes, pages)
+		case "kerning":
+			err = decodeBMFontKerning(font, attributes)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid BMFont line %d: %s", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return font, nil
+}
+
+func decodeBMFontChar(font *Font, attributes bmFontAttributes, pages []image.Selection) error {
+	values, err := attributes.ints("id", "x", "y", "width", "height", "xoffset", "yoffset", "xadvance")
+	if err != nil {
+		return err
+	}
+	page := 0
+	if _, ok := attributes["page"]; ok {
+		if page, err = attributes.int("page"); err != nil {
+			return err
+		}
+	}
+	if page < 0 || page >= len(pages) {
+		return errors.New("missing page " + strconv.Itoa(page))
+	}
+	x, y, width, height := values[1], values[2], values[3], values[4]
+	font.SetGlyph(rune(values[0]), Glyph{
+		Selection: pages[page].Selection(x, y).WithSize(width, height),
+		XOffset:   values[5],
+		YOffset:   values[6],
+		XAdvance:  values[7],
+	})
+	return nil
+}
+
+func decodeBMFontKerning(font *Font, attributes bmFontAttributes) error {
+	values, err := attributes.ints("first", "second", "amount")
+	if err != nil {
+		return err
+	}
+	font.SetKerning(rune(values[0]), rune(values[1]), values[2])
+	return nil
+}
+
+type bmFontAttributes map[string]string
+
+func (a bmFontAttributes) int(key string) (int, error) {
+	value, ok := a[key]
+	if !ok {
+		return 0, errors.New("missing attribute " + key)
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New("invalid attribute " + key)
+	}
+	return i, nil
+}
+
+func (a bmFontAttributes) ints(keys ...string) ([]int, error) {
+	values := make([]int, len(keys))
+	for i, key := range keys {
+		value, err := a.int(key)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// parseBMFontLine parses line such as `page id=0 file="font.png"`
+func parseBMFontLine(line string) (tag string, attributes bmFontAttributes) {
+	line = strings.TrimSpace(line)
+	end := strings.IndexAny(line, " \t")
+	if end < 0 {
+		return line, bmFontAttributes{}
+	}
+	tag = line[:end]
+	attributes = bmFontAttributes{}
+	rest := line[end:]
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		equals := strings.IndexByte(rest, '=')
+		if equals < 0 {
+			return tag, attributes
+		}
+		key := rest[:equals]
+		rest = rest[equals+1:]
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			closing := strings.IndexByte(rest[1:], '"')
+			if closing < 0 {
+				closing = len(rest) - 1
+			}
+			value = rest[1 : closing+1]
+			rest = rest[closing+1:]
+			rest = strings.TrimPrefix(rest, `"`)
+		} else {
+			valueEnd := strings.IndexAny(rest, " \t")
+			if valueEnd < 0 {
+				valueEnd = len(rest)
+			}
+			value = rest[:valueEnd]
+			rest = rest[valueEnd:]
+		}
+		attributes[key] = value
+	}
+}
@@ -0,0 +1,101 @@
+package font_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/font"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+const bmFont = `info face="Pixel Font" size=8 bold=0 italic=0 charset="" unicode=1 padding=0,0,0,0 spacing=1,1
+common lineHeight=9 base=7 scaleW=16 scaleH=16 pages=2 packed=0
+page id=0 file="font_0.png"
+page id=1 file="font_1.png"
+chars count=2
+char id=65   x=1    y=2    width=3    height=4    xoffset=-1   yoffset=2    xadvance=5    page=0  chnl=15
+char id=66   x=5    y=6    width=7    height=8    xoffset=0    yoffset=1    xadvance=8    page=1  chnl=15
+kernings count=1
+kerning first=65  second=66  amount=-2
+`
+
+func TestDecodeBMFont(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = font.DecodeBMFont(nil)
+		})
+	})
+	t.Run("should return error when reader returned error", func(t *testing.T) {
+		f, err := font.DecodeBMFont(&erroneousReader{err: errors.New("read error")})
+		assert.Nil(t, f)
+		assert.Error(t, err)
+	})
+	t.Run("should return error for invalid descriptor", func(t *testing.T) {
+		tests := map[string]string{
+			"invalid line height":  "common lineHeight=x",
+			"missing char id":      "char x=0 y=0 width=1 height=1 xoffset=0 yoffset=0 xadvance=1",
+			"invalid char x":       "char id=65 x=a y=0 width=1 height=1 xoffset=0 yoffset=0 xadvance=1",
+			"missing page":         "char id=65 x=0 y=0 width=1 height=1 xoffset=0 yoffset=0 xadvance=1 page=2",
+			"negative page":        "char id=65 x=0 y=0 width=1 height=1 xoffset=0 yoffset=0 xadvance=1 page=-1",
+			"missing kerning pair": "kerning first=65 amount=1",
+		}
+		for name, descriptor := range tests {
+			t.Run(name, func(t *testing.T) {
+				page := newImage([]string{"."}).WholeImageSelection()
+				// when
+				f, err := font.DecodeBMFont(strings.NewReader(descriptor), page)
+				// then
+				assert.Nil(t, f)
+				assert.Error(t, err)
+			})
+		}
+	})
+	t.Run("should decode descriptor", func(t *testing.T) {
+		page0 := newImage([]string{"."}).WholeImageSelection()
+		page1 := newImage([]string{"."}).WholeImageSelection()
+		// when
+		f, err := font.DecodeBMFont(strings.NewReader(bmFont), page0, page1)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 9, f.LineHeight())
+		assertGlyph(t, f, 'A', page0, 1, 2, 3, 4, font.Glyph{XOffset: -1, YOffset: 2, XAdvance: 5})
+		assertGlyph(t, f, 'B', page1, 5, 6, 7, 8, font.Glyph{XOffset: 0, YOffset: 1, XAdvance: 8})
+		assert.Equal(t, -2, f.Kerning('A', 'B'))
+	})
+	t.Run("should use first page when page attribute is missing", func(t *testing.T) {
+		page := newImage([]string{"."}).WholeImageSelection()
+		descriptor := "char id=65 x=0 y=0 width=1 height=1 xoffset=0 yoffset=0 xadvance=1"
+		// when
+		f, err := font.DecodeBMFont(strings.NewReader(descriptor), page)
+		// then
+		require.NoError(t, err)
+		glyph, ok := f.Glyph('A')
+		require.True(t, ok)
+		assert.Same(t, page.Image(), glyph.Selection.Image())
+	})
+}
+
+func assertGlyph(t *testing.T, f *font.Font, char rune, page image.Selection, x, y, width, height int, metrics font.Glyph) {
+	glyph, ok := f.Glyph(char)
+	require.True(t, ok)
+	assert.Same(t, page.Image(), glyph.Selection.Image())
+	assert.Equal(t, x, glyph.Selection.ImageX())
+	assert.Equal(t, y, glyph.Selection.ImageY())
+	assert.Equal(t, width, glyph.Selection.Width())
+	assert.Equal(t, height, glyph.Selection.Height())
+	assert.Equal(t, metrics.XOffset, glyph.XOffset)
+	assert.Equal(t, metrics.YOffset, glyph.YOffset)
+	assert.Equal(t, metrics.XAdvance, glyph.XAdvance)
+}
+
+type erroneousReader struct {
+	err error
+}
+
+func (r *erroneousReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,284 @@
+// Package font provides bitmap fonts and a CPU tool for rendering text:
+//
+//	f := font.NewMonospace(sheet, 6, 8, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+//	tool := font.New(f)
+//	tool.SetColor(colornames.Yellow)
+//	tool.DrawText(screen, 2, 2, "SCORE 100")
+//
+// Glyphs are stored in image.Selection (usually a part of the sprite sheet).
+// Glyph colors are multiplied by the tool color (tinted) and blended into
+// the target using source-over blending. Similar to image.Selection.SetColor
+// pixels outside the image boundaries are skipped, but it is possible to draw
+// outside the selection.
+package font
+
+import (
+	"strings"
+
+	"github.com/jacekolszak/pixiq/blend"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Glyph is a single character of the Font
+type Glyph struct {
+	// Selection contains pixels of the glyph
+	Selection image.Selection
+	// XOffset is a horizontal distance from the pen position to the left edge
+	// of the Selection
+	XOffset int
+	// YOffset is a vertical distance from the top of the line to the top edge
+	// of the Selection
+	YOffset int
+	// XAdvance is a horizontal distance the pen is moved after drawing the glyph
+	XAdvance int
+}
+
+// Font is a collection of glyphs with metrics
+type Font struct {
+	lineHeight int
+	glyphs     map[rune]Glyph
+	kerning    map[kerningPair]int
+}
+
+type kerningPair struct {
+	first, second rune
+}
+
+// NewFont creates an empty Font with a given line height. Glyphs should be added
+// using SetGlyph method.
+func NewFont(lineHeight int) *Font {
+	return &Font{
+		lineHeight: lineHeight,
+		glyphs:     map[rune]Glyph{},
+		kerning:    map[kerningPair]int{},
+	}
+}
+
+// NewMonospace creates a Font from the sprite sheet where all glyphs have
+// the same size. Glyphs are taken row by row, from left to right, and assigned
+// to consecutive characters of chars. Characters for which there is no space
+// in the sheet are skipped.
+func NewMonospace(sheet image.Selection, glyphWidth, glyphHeight int, chars string) *Font {
+	if glyphWidth <= 0 || glyphHeight <= 0 {
+		panic("non-positive glyph size")
+	}
+	font := NewFont(glyphHeight)
+	columns := sheet.Width() / glyphWidth
+	rows := sheet.Height() / glyphHeight
+	i := 0
+	for _, char := range chars {
+		if i >= columns*rows {
+			break
+		}
+		x := (i % columns) * glyphWidth
+		y := (i / columns) * glyphHeight
+		font.SetGlyph(char, Glyph{
+			Selection: sheet.Selection(x, y).WithSize(glyphWidth, glyphHeight),
+			XAdvance:  glyphWidth,
+		})
+		i++
+	}
+	return font
+}
+
+// LineHeight returns the distance between the tops of two consecutive lines
+func (f *Font) LineHeight() int {
+	return f.lineHeight
+}
+
+// SetGlyph adds or replaces glyph for a given character
+func (f *Font) SetGlyph(char rune, glyph Glyph) {
+	f.glyphs[char] = glyph
+}
+
+// Glyph returns glyph for a given character and true, or false if the font
+// does not have such glyph
+func (f *Font) Glyph(char rune) (Glyph, bool) {
+	glyph, ok := f.glyphs[char]
+	return glyph, ok
+}
+
+// SetKerning sets the additional horizontal distance between two consecutive
+// characters. Usually the amount is negative.
+func (f *Font) SetKerning(first, second rune, amount int) {
+	f.kerning[kerningPair{first: first, second: second}] = amount
+}
+
+// Kerning returns the additional horizontal distance between two consecutive
+// characters
+func (f *Font) Kerning(first, second rune) int {
+	return f.kerning[kerningPair{first: first, second: second}]
+}
+
+// Alignment is a horizontal alignment of text lines
+type Alignment int
+
+const (
+	// Left aligns lines to the left edge
+	Left Alignment = iota
+	// Center centers lines
+	Center
+	// Right aligns lines to the right edge
+	Right
+)
+
+// New returns new instance of *font.Tool rendering text with a given font.
+// By default the text is white (glyphs are not tinted), aligned to the left
+// and not wrapped.
+func New(font *Font) *Tool {
+	if font == nil {
+		panic("nil font")
+	}
+	return &Tool{
+		font:    font,
+		blender: blend.NewSourceOver(),
+	}
+}
+
+// Tool is a text rendering tool.
+//
+// Tool uses CPU.
+type Tool struct {
+	font *Font
+	// blender tints glyphs with the tool color
+	blender   *blend.SourceOver
+	alignment Alignment
+	wrapWidth int
+}
+
+// SetColor sets the color which is multiplied by each glyph pixel. White color
+// does not change glyph colors at all.
+func (t *Tool) SetColor(color image.Color) {
+	t.blender.SetTint(color)
+}
+
+// SetAlignment sets the horizontal alignment of lines. Lines are aligned inside
+// the box starting at x position with a width set by SetWrapWidth. When wrap
+// width is zero, the box has zero width, therefore centered lines are centered
+// at x and right-aligned lines end at x.
+func (t *Tool) SetAlignment(alignment Alignment) {
+	t.alignment = alignment
+}
+
+// SetWrapWidth sets the maximum width of the line. Longer lines are broken
+// at spaces. Words longer than wrap width are broken at any character.
+// Zero disables wrapping.
+func (t *Tool) SetWrapWidth(width int) {
+	if width < 0 {
+		width = 0
+	}
+	t.wrapWidth = width
+}
+
+// DrawText draws the text at a given position, which is the top-left corner
+// of the first line (for left-aligned text). Passed coordinates are local,
+// which means that the top-left corner of selection is equivalent
+// to localX=0, localY=0. New line characters start new lines. Characters
+// without glyphs in the font are skipped.
+func (t *Tool) DrawText(selection image.Selection, localX, localY int, text string) {
+	for i, line := range t.lines(text) {
+		x := localX + t.alignmentOffset(t.lineWidth(line))
+		y := localY + i*t.font.lineHeight
+		var previous rune
+		for j, char := range line {
+			glyph, ok := t.font.glyphs[char]
+			if !ok {
+				continue
+			}
+			if j > 0 {
+				x += t.font.Kerning(previous, char)
+			}
+			t.drawGlyph(selection, x+glyph.XOffset, y+glyph.YOffset, glyph.Selection)
+			x += glyph.XAdvance
+			previous = char
+		}
+	}
+}
+
+// MeasureText returns the size of the box containing all lines of the text
+// after wrapping
+func (t *Tool) MeasureText(text string) (width, height int) {
+	lines := t.lines(text)
+	for _, line := range lines {
+		if w := t.lineWidth(line); w > width {
+			width = w
+		}
+	}
+	return width, len(lines) * t.font.lineHeight
+}
+
+func (t *Tool) alignmentOffset(lineWidth int) int {
+	switch t.alignment {
+	case Center:
+		return (t.wrapWidth - lineWidth) / 2
+	case Right:
+		return t.wrapWidth - lineWidth
+	default:
+		return 0
+	}
+}
+
+func (t *Tool) drawGlyph(target image.Selection, x, y int, glyph image.Selection) {
+	t.blender.BlendSourceToTarget(glyph, target.Selection(x, y))
+}
+
+// lineWidth returns the sum of advances and kerning of all characters
+func (t *Tool) lineWidth(line []rune) int {
+	width := 0
+	var previous rune
+	for i, char := range line {
+		glyph, ok := t.font.glyphs[char]
+		if !ok {
+			continue
+		}
+		if i > 0 {
+			width += t.font.Kerning(previous, char)
+		}
+		width += glyph.XAdvance
+		previous = char
+	}
+	return width
+}
+
+// lines splits text into lines using new line characters and wrap width
+func (t *Tool) lines(text string) [][]rune {
+	var lines [][]rune
+	for _, paragraph := range strings.Split(text, "\n") {
+		lines = append(lines, t.wrap([]rune(paragraph))...)
+	}
+	return lines
+}
+
+func (t *Tool) wrap(paragraph []rune) [][]rune {
+	if t.wrapWidth == 0 || t.lineWidth(paragraph) <= t.wrapWidth {
+		return [][]rune{paragraph}
+	}
+	var (
+		lines [][]rune
+		line  []rune
+	)
+	for _, word := range strings.Split(string(paragraph), " ") {
+		wordRunes := []rune(word)
+		candidate := wordRunes
+		if len(line) > 0 {
+			candidate = append(append(append([]rune{}, line...), ' '), wordRunes...)
+		}
+		if t.lineWidth(candidate) <= t.wrapWidth {
+			line = candidate
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		line = nil
+		for _, char := range wordRunes {
+			candidate = append(append([]rune{}, line...), char)
+			if len(line) > 0 && t.lineWidth(candidate) > t.wrapWidth {
+				lines = append(lines, line)
+				candidate = []rune{char}
+			}
+			line = candidate
+		}
+	}
+	return append(lines, line)
+}
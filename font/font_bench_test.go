@@ -0,0 +1,23 @@
+package font_test
+
+import (
+	"testing"
+
+	"github.com/jacekolszak/pixiq/font"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+func BenchmarkTool_DrawText(b *testing.B) {
+	var (
+		img    = image.New(fake.NewAcceleratedImage(640, 360))
+		screen = img.WholeImageSelection()
+		tool   = font.New(newFont())
+	)
+	tool.SetWrapWidth(100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.DrawText(screen, 10, 10, "ABC ABC ABC ABC ABC ABC ABC ABC ABC ABC")
+	}
+}
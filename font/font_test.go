@@ -0,0 +1,334 @@
+package font_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/font"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+// palette maps characters used in test patterns to colors
+var palette = map[byte]image.Color{
+	'.': image.Transparent,
+	'#': image.RGB(255, 255, 255),
+	'o': image.RGB(10, 20, 30),
+	'R': image.RGB(255, 0, 0),
+	'r': image.RGB(132, 10, 15),
+}
+
+// glyphs is a sprite sheet with 2x3 glyphs of A, B and C characters. Glyphs
+// are distinguishable by pixel position.
+var glyphs = []string{
+	"#.#..#",
+	"..#...",
+	"......",
+}
+
+func TestNewFont(t *testing.T) {
+	t.Run("should create empty font", func(t *testing.T) {
+		f := font.NewFont(8)
+		require.NotNil(t, f)
+		assert.Equal(t, 8, f.LineHeight())
+		_, ok := f.Glyph('A')
+		assert.False(t, ok)
+	})
+}
+
+func TestFont_SetGlyph(t *testing.T) {
+	t.Run("should set glyph", func(t *testing.T) {
+		f := font.NewFont(8)
+		selection := newImage(glyphs).WholeImageSelection()
+		glyph := font.Glyph{Selection: selection, XOffset: 1, YOffset: 2, XAdvance: 3}
+		// when
+		f.SetGlyph('A', glyph)
+		// then
+		actual, ok := f.Glyph('A')
+		assert.True(t, ok)
+		assert.Equal(t, glyph, actual)
+	})
+}
+
+func TestFont_SetKerning(t *testing.T) {
+	t.Run("should set kerning", func(t *testing.T) {
+		f := font.NewFont(8)
+		// when
+		f.SetKerning('A', 'V', -1)
+		// then
+		assert.Equal(t, -1, f.Kerning('A', 'V'))
+		assert.Equal(t, 0, f.Kerning('V', 'A'))
+	})
+}
+
+func TestNewMonospace(t *testing.T) {
+	t.Run("should panic when glyph size is not positive", func(t *testing.T) {
+		sheet := newImage(glyphs).WholeImageSelection()
+		assert.Panics(t, func() {
+			font.NewMonospace(sheet, 0, 1, "A")
+		})
+		assert.Panics(t, func() {
+			font.NewMonospace(sheet, 1, 0, "A")
+		})
+	})
+	t.Run("should create glyphs row by row", func(t *testing.T) {
+		sheet := newImage([]string{
+			"....",
+			"....",
+		}).WholeImageSelection()
+		// when
+		f := font.NewMonospace(sheet, 2, 1, "ABCDE")
+		// then
+		assert.Equal(t, 1, f.LineHeight())
+		expectedPositions := map[rune][2]int{'A': {0, 0}, 'B': {2, 0}, 'C': {0, 1}, 'D': {2, 1}}
+		for char, position := range expectedPositions {
+			glyph, ok := f.Glyph(char)
+			require.True(t, ok)
+			assert.Equal(t, position[0], glyph.Selection.ImageX())
+			assert.Equal(t, position[1], glyph.Selection.ImageY())
+			assert.Equal(t, 2, glyph.Selection.Width())
+			assert.Equal(t, 1, glyph.Selection.Height())
+			assert.Equal(t, 2, glyph.XAdvance)
+		}
+		_, ok := f.Glyph('E')
+		assert.False(t, ok)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should panic for nil font", func(t *testing.T) {
+		assert.Panics(t, func() {
+			font.New(nil)
+		})
+	})
+	t.Run("should create tool", func(t *testing.T) {
+		tool := font.New(font.NewFont(1))
+		assert.NotNil(t, tool)
+	})
+}
+
+func TestTool_DrawText(t *testing.T) {
+	tests := map[string]struct {
+		text       string
+		x, y       int
+		color      image.Color
+		alignment  font.Alignment
+		wrapWidth  int
+		kerning    int
+		background []string
+		expected   []string
+	}{
+		"empty text": {
+			background: []string{"....", "...."},
+			expected:   []string{"....", "...."},
+		},
+		"single char": {
+			text:       "A",
+			background: []string{"....", "....", "...."},
+			expected:   []string{"#...", "....", "...."},
+		},
+		"many chars": {
+			text:       "ABC",
+			background: []string{"......", "......", "......"},
+			expected: []string{
+				"#.#..#",
+				"..#...",
+				"......",
+			},
+		},
+		"position": {
+			text:       "B",
+			x:          1,
+			y:          1,
+			background: []string{"....", "....", "....", "...."},
+			expected: []string{
+				"....",
+				".#..",
+				".#..",
+				"....",
+			},
+		},
+		"position partially outside the image": {
+			text:       "C",
+			x:          -1,
+			background: []string{"..", ".."},
+			expected: []string{
+				"#.",
+				"..",
+			},
+		},
+		"unknown chars are skipped": {
+			text:       "?A",
+			background: []string{"..", "..", ".."},
+			expected:   []string{"#.", "..", ".."},
+		},
+		"new line": {
+			text:       "A\nA",
+			background: []string{"..", "..", "..", "..", "..", ".."},
+			expected:   []string{"#.", "..", "..", "#.", "..", ".."},
+		},
+		"tint": {
+			text:       "A",
+			color:      image.RGB(255, 0, 0),
+			background: []string{"..", "..", ".."},
+			expected:   []string{"R.", "..", ".."},
+		},
+		"blend with background": {
+			text:       "A",
+			color:      image.RGBA(127, 0, 0, 127),
+			background: []string{"oo", "oo", "oo"},
+			expected:   []string{"ro", "oo", "oo"},
+		},
+		"kerning": {
+			text:       "AB",
+			kerning:    -1,
+			background: []string{"...", "...", "..."},
+			expected:   []string{"##.", ".#.", "..."},
+		},
+		"right alignment": {
+			text:       "A\nAB",
+			x:          4,
+			alignment:  font.Right,
+			background: []string{"....", "....", "....", "....", "....", "...."},
+			expected:   []string{"..#.", "....", "....", "#.#.", "..#.", "...."},
+		},
+		"center alignment": {
+			text:       "A\nAB",
+			alignment:  font.Center,
+			wrapWidth:  6,
+			background: []string{"......", "......", "......", "......", "......", "......"},
+			expected: []string{
+				"..#...",
+				"......",
+				"......",
+				".#.#..",
+				"...#..",
+				"......",
+			},
+		},
+		"wrap at space": {
+			text:       "A B",
+			wrapWidth:  4,
+			background: []string{"....", "....", "....", "....", "....", "...."},
+			expected:   []string{"#...", "....", "....", "#...", "#...", "...."},
+		},
+		"wrap long word": {
+			text:       "AB",
+			wrapWidth:  3,
+			background: []string{"..", "..", "..", "..", "..", ".."},
+			expected:   []string{"#.", "..", "..", "#.", "#.", ".."},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := newImage(test.background)
+			f := newFont()
+			f.SetKerning('A', 'B', test.kerning)
+			tool := font.New(f)
+			if test.color != (image.Color{}) {
+				tool.SetColor(test.color)
+			}
+			tool.SetAlignment(test.alignment)
+			tool.SetWrapWidth(test.wrapWidth)
+			// when
+			tool.DrawText(img.WholeImageSelection(), test.x, test.y, test.text)
+			// then
+			assertPixels(t, img, test.expected)
+		})
+	}
+	t.Run("should use glyph offsets", func(t *testing.T) {
+		img := newImage([]string{"...", "...", "..."})
+		f := font.NewFont(3)
+		sheet := newImage([]string{"#"}).WholeImageSelection()
+		f.SetGlyph('.', font.Glyph{Selection: sheet, XOffset: 1, YOffset: 2, XAdvance: 1})
+		tool := font.New(f)
+		// when
+		tool.DrawText(img.WholeImageSelection(), 0, 0, "..")
+		// then
+		assertPixels(t, img, []string{"...", "...", ".##"})
+	})
+	t.Run("should use selection coordinates", func(t *testing.T) {
+		img := newImage([]string{"...", "...", "...", "..."})
+		tool := font.New(newFont())
+		// when
+		tool.DrawText(img.Selection(1, 1), 0, 0, "A")
+		// then
+		assertPixels(t, img, []string{"...", ".#.", "...", "..."})
+	})
+}
+
+func TestTool_MeasureText(t *testing.T) {
+	tests := map[string]struct {
+		text           string
+		wrapWidth      int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		"empty": {
+			expectedHeight: 3,
+		},
+		"single line": {
+			text:           "AB",
+			expectedWidth:  4,
+			expectedHeight: 3,
+		},
+		"kerning": {
+			text:           "AC",
+			expectedWidth:  3,
+			expectedHeight: 3,
+		},
+		"many lines": {
+			text:           "A\nABC",
+			expectedWidth:  6,
+			expectedHeight: 6,
+		},
+		"wrapped": {
+			text:           "AB AB",
+			wrapWidth:      4,
+			expectedWidth:  4,
+			expectedHeight: 6,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newFont()
+			f.SetKerning('A', 'C', -1)
+			tool := font.New(f)
+			tool.SetWrapWidth(test.wrapWidth)
+			// when
+			width, height := tool.MeasureText(test.text)
+			// then
+			assert.Equal(t, test.expectedWidth, width)
+			assert.Equal(t, test.expectedHeight, height)
+		})
+	}
+}
+
+// newFont returns monospace font with 2x3 glyphs: A, B, C and a space
+func newFont() *font.Font {
+	sheet := newImage(append(glyphs, "......", "......", "......")).WholeImageSelection()
+	return font.NewMonospace(sheet, 2, 3, "ABC ")
+}
+
+func newImage(pixels []string) *image.Image {
+	img := image.New(fake.NewAcceleratedImage(len(pixels[0]), len(pixels)))
+	selection := img.WholeImageSelection()
+	for y, line := range pixels {
+		for x := 0; x < len(line); x++ {
+			selection.SetColor(x, y, palette[line[x]])
+		}
+	}
+	return img
+}
+
+func assertPixels(t *testing.T, img *image.Image, expected []string) {
+	selection := img.WholeImageSelection()
+	for y := 0; y < img.Height(); y++ {
+		for x := 0; x < img.Width(); x++ {
+			expectedColor := palette[expected[y][x]]
+			assert.Equal(t, expectedColor, selection.Color(x, y), "position (%d,%d)", x, y)
+		}
+	}
+}
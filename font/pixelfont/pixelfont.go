@@ -0,0 +1,133 @@
+// Package pixelfont provides a tiny built-in 5x7 pixel font, which does not need
+// any asset files. It is useful for debug overlays, examples and tests:
+//
+//	pixelfont.DrawText(screen, 1, 1, "FPS 60", colornames.White)
+//
+// Font contains all printable ASCII characters (from ' ' to '~').
+package pixelfont
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/jacekolszak/pixiq/font"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+const (
+	// GlyphWidth is the width of each glyph in pixels
+	GlyphWidth = 5
+	// GlyphHeight is the height of each glyph in pixels
+	GlyphHeight = 7
+	// Advance is the horizontal distance between two consecutive characters
+	Advance = GlyphWidth + 1
+	// LineHeight is the distance between the tops of two consecutive lines
+	LineHeight = GlyphHeight + 1
+)
+
+const (
+	firstChar = ' '
+	lastChar  = '~'
+)
+
+// DrawText draws the text at a given position, which is the top-left corner
+// of the first line. Passed coordinates are local, which means that the top-left
+// corner of selection is equivalent to localX=0, localY=0. New line characters
+// start new lines. Characters not supported by the font are skipped but the pen is
+// moved anyway.
+//
+// Text is blended into the selection using the source-over blending. Similar to
+// image.Selection.SetColor pixels outside the image boundaries are skipped, but
+// it is possible to draw outside the selection.
+//
+// DrawText uses CPU. It reuses the same font.Tool between calls, therefore it
+// is not safe for concurrent use.
+func DrawText(selection image.Selection, localX, localY int, text string, color image.Color) {
+	tool := builtinTool()
+	tool.SetColor(color)
+	tool.DrawText(selection, localX, localY, strings.Map(supportedOrSpace, text))
+}
+
+// supportedOrSpace replaces unsupported characters with space, so the pen is
+// moved for them too
+func supportedOrSpace(char rune) rune {
+	if char == '\n' || (char >= firstChar && char <= lastChar) {
+		return char
+	}
+	return ' '
+}
+
+var (
+	builtinOnce sync.Once
+	builtin     *font.Tool
+)
+
+// builtinTool returns the tool used by DrawText. Glyphs are stored in RAM only.
+func builtinTool() *font.Tool {
+	builtinOnce.Do(func() {
+		builtin = font.New(NewFont(ramImageFactory{}))
+	})
+	return builtin
+}
+
+// MeasureText returns the size of the box containing all lines of the text
+// drawn by DrawText. The width does not include the spacing after the last
+// character.
+func MeasureText(text string) (width, height int) {
+	lines := strings.Split(text, "\n")
+	for _, line := range lines {
+		chars := len([]rune(line))
+		if chars == 0 {
+			continue
+		}
+		if w := chars*Advance - 1; w > width {
+			width = w
+		}
+	}
+	return width, len(lines)*LineHeight - 1
+}
+
+func drawGlyph(selection image.Selection, glyph [GlyphWidth]byte, color image.Color) {
+	for column, bits := range glyph {
+		for row := 0; row < GlyphHeight; row++ {
+			if bits&(1<<row) != 0 {
+				selection.SetColor(column, row, color)
+			}
+		}
+	}
+}
+
+// ImageFactory creates a new image with given dimensions.
+//
+// *glfw.OpenGL instance can be used as an ImageFactory implementation.
+type ImageFactory interface {
+	NewImage(width, height int) *image.Image
+}
+
+// NewFont creates a font.Font which can be used by font.Tool, for example to
+// align or wrap the text. Glyphs are white and are stored in a new image
+// created by imageFactory.
+func NewFont(imageFactory ImageFactory) *font.Font {
+	if imageFactory == nil {
+		panic("nil imageFactory")
+	}
+	sheet := imageFactory.NewImage(len(glyphs)*GlyphWidth, GlyphHeight).WholeImageSelection()
+	white := image.RGB(255, 255, 255)
+	f := font.NewFont(LineHeight)
+	for i, glyph := range glyphs {
+		selection := sheet.Selection(i*GlyphWidth, 0).WithSize(GlyphWidth, GlyphHeight)
+		drawGlyph(selection, glyph, white)
+		f.SetGlyph(rune(firstChar+i), font.Glyph{
+			Selection: selection,
+			XAdvance:  Advance,
+		})
+	}
+	return f
+}
+
+type ramImageFactory struct{}
+
+func (ramImageFactory) NewImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
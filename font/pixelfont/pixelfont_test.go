@@ -0,0 +1,193 @@
+package pixelfont_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/font"
+	"github.com/jacekolszak/pixiq/font/pixelfont"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+var (
+	white = image.RGB(255, 255, 255)
+	red   = image.RGB(255, 0, 0)
+)
+
+var letterA = []string{
+	".###.",
+	"#...#",
+	"#...#",
+	"#...#",
+	"#####",
+	"#...#",
+	"#...#",
+}
+
+func TestDrawText(t *testing.T) {
+	t.Run("should draw letter", func(t *testing.T) {
+		img := newImage(7, 9)
+		// when
+		pixelfont.DrawText(img.WholeImageSelection(), 1, 1, "A", white)
+		// then
+		assertPattern(t, img.Selection(1, 1), letterA, white)
+		assertTransparentBorder(t, img)
+	})
+	t.Run("should draw characters next to each other", func(t *testing.T) {
+		img := newImage(11, 7)
+		// when
+		pixelfont.DrawText(img.WholeImageSelection(), 0, 0, "AA", red)
+		// then
+		assertPattern(t, img.Selection(0, 0), letterA, red)
+		assertPattern(t, img.Selection(6, 0), letterA, red)
+	})
+	t.Run("should move pen for unsupported characters", func(t *testing.T) {
+		img := newImage(11, 7)
+		// when
+		pixelfont.DrawText(img.WholeImageSelection(), 0, 0, "ąA", red)
+		// then
+		assertPattern(t, img.Selection(6, 0), letterA, red)
+		assert.Equal(t, image.Transparent, img.WholeImageSelection().Color(1, 0))
+	})
+	t.Run("should draw many lines", func(t *testing.T) {
+		img := newImage(5, 15)
+		// when
+		pixelfont.DrawText(img.WholeImageSelection(), 0, 0, "A\nA", white)
+		// then
+		assertPattern(t, img.Selection(0, 0), letterA, white)
+		assertPattern(t, img.Selection(0, 8), letterA, white)
+	})
+	t.Run("should use selection coordinates", func(t *testing.T) {
+		img := newImage(7, 9)
+		// when
+		pixelfont.DrawText(img.Selection(1, 1), 0, 0, "A", white)
+		// then
+		assertPattern(t, img.Selection(1, 1), letterA, white)
+	})
+	t.Run("should skip pixels outside the image", func(t *testing.T) {
+		img := newImage(2, 2)
+		// when
+		pixelfont.DrawText(img.WholeImageSelection(), -3, -4, "A", white)
+		// then
+		assertPattern(t, img.WholeImageSelection(), []string{
+			"##",
+			".#",
+		}, white)
+	})
+	t.Run("should blend color with background", func(t *testing.T) {
+		img := newImage(1, 1)
+		selection := img.WholeImageSelection()
+		selection.SetColor(0, 0, image.RGB(10, 20, 30))
+		// when
+		pixelfont.DrawText(selection, 0, -4, "A", image.RGBA(127, 0, 0, 127))
+		// then
+		assert.Equal(t, image.RGB(132, 10, 15), selection.Color(0, 0))
+	})
+	t.Run("all printable characters but space should have pixels", func(t *testing.T) {
+		for char := '!'; char <= '~'; char++ {
+			img := newImage(5, 7)
+			// when
+			pixelfont.DrawText(img.WholeImageSelection(), 0, 0, string(char), white)
+			// then
+			assert.True(t, hasPixels(img), "character %q", char)
+		}
+	})
+}
+
+func TestMeasureText(t *testing.T) {
+	tests := map[string]struct {
+		text           string
+		expectedWidth  int
+		expectedHeight int
+	}{
+		"empty":      {text: "", expectedWidth: 0, expectedHeight: 7},
+		"one char":   {text: "A", expectedWidth: 5, expectedHeight: 7},
+		"many chars": {text: "FPS 60", expectedWidth: 35, expectedHeight: 7},
+		"many lines": {text: "A\nAB", expectedWidth: 11, expectedHeight: 15},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			width, height := pixelfont.MeasureText(test.text)
+			assert.Equal(t, test.expectedWidth, width)
+			assert.Equal(t, test.expectedHeight, height)
+		})
+	}
+}
+
+func TestNewFont(t *testing.T) {
+	t.Run("should panic for nil ImageFactory", func(t *testing.T) {
+		assert.Panics(t, func() {
+			pixelfont.NewFont(nil)
+		})
+	})
+	t.Run("should create font usable by font.Tool", func(t *testing.T) {
+		f := pixelfont.NewFont(fakeImageFactory{})
+		require.NotNil(t, f)
+		assert.Equal(t, pixelfont.LineHeight, f.LineHeight())
+		img := newImage(7, 9)
+		tool := font.New(f)
+		tool.SetColor(red)
+		// when
+		tool.DrawText(img.WholeImageSelection(), 1, 1, "A")
+		// then
+		assertPattern(t, img.Selection(1, 1), letterA, red)
+		assertTransparentBorder(t, img)
+	})
+	t.Run("should have glyphs of all printable characters", func(t *testing.T) {
+		f := pixelfont.NewFont(fakeImageFactory{})
+		for char := ' '; char <= '~'; char++ {
+			glyph, ok := f.Glyph(char)
+			require.True(t, ok, "character %q", char)
+			assert.Equal(t, pixelfont.Advance, glyph.XAdvance)
+		}
+	})
+}
+
+func newImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
+
+func assertPattern(t *testing.T, selection image.Selection, pattern []string, color image.Color) {
+	for y, line := range pattern {
+		for x := 0; x < len(line); x++ {
+			expected := image.Transparent
+			if line[x] == '#' {
+				expected = color
+			}
+			assert.Equal(t, expected, selection.Color(x, y), "position (%d,%d)", x, y)
+		}
+	}
+}
+
+func assertTransparentBorder(t *testing.T, img *image.Image) {
+	selection := img.WholeImageSelection()
+	for x := 0; x < img.Width(); x++ {
+		assert.Equal(t, image.Transparent, selection.Color(x, 0))
+		assert.Equal(t, image.Transparent, selection.Color(x, img.Height()-1))
+	}
+	for y := 0; y < img.Height(); y++ {
+		assert.Equal(t, image.Transparent, selection.Color(0, y))
+		assert.Equal(t, image.Transparent, selection.Color(img.Width()-1, y))
+	}
+}
+
+func hasPixels(img *image.Image) bool {
+	selection := img.WholeImageSelection()
+	for y := 0; y < img.Height(); y++ {
+		for x := 0; x < img.Width(); x++ {
+			if selection.Color(x, y) != image.Transparent {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type fakeImageFactory struct{}
+
+func (f fakeImageFactory) NewImage(width, height int) *image.Image {
+	return newImage(width, height)
+}
@@ -114,6 +114,8 @@ type API interface {
 	DeleteFramebuffers(n int32, framebuffers *uint32)
 	// FramebufferTexture2D attaches a level of a texture object as a logical buffer to the currently bound framebuffer object
 	FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32)
+	// PixelStorei sets pixel storage modes
+	PixelStorei(pname uint32, param int32)
 	// TexSubImage2D specifies a two-dimensional texture subimage
 	TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer)
 	// GetTexImage returns a texture image
@@ -124,6 +126,12 @@ type API interface {
 	ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer)
 	// BlendFunc specifies pixel arithmetic
 	BlendFunc(sfactor uint32, dfactor uint32)
+	// BlendFuncSeparate specifies pixel arithmetic for RGB and alpha components separately
+	BlendFuncSeparate(srcRGB uint32, dstRGB uint32, srcAlpha uint32, dstAlpha uint32)
+	// BlendEquationSeparate sets the RGB blend equation and the alpha blend equation separately
+	BlendEquationSeparate(modeRGB uint32, modeAlpha uint32)
+	// BlendColor sets the blend color
+	BlendColor(red float32, green float32, blue float32, alpha float32)
 	// Finish blocks until all GL execution is complete
 	Finish()
 	// Ptr takes a slice or pointer (to a singular scalar value or the first
@@ -21,12 +21,14 @@ type Command interface {
 // component, df is a destination factor.
 // By default sf is 1 and df is 0 (gl.SourceBlendFactors), which means that formula is
 //   R = S
-// BlendFactors can be changed by calling SetBlendFactors method.
+// BlendFactors (including the blend equation) can be changed by calling
+// SetBlendFactors method.
 type Renderer struct {
 	program      *Program
 	api          API
 	allImages    allImages
 	blendFactors BlendFactors
+	blendColor   image.Color
 }
 
 // BindTexture assigns image.AcceleratedImage to a given textureUnit and uniform attribute.
@@ -163,6 +165,11 @@ const (
 	Zero = BlendFactor(0)
 	// One is GL_ONE. Multiplies all components by 1.
 	One = BlendFactor(1)
+	// SrcColor is GL_SRC_COLOR. Multiplies all components by the source color.
+	SrcColor = BlendFactor(0x0300)
+	// OneMinusSrcColor is GL_ONE_MINUS_SRC_COLOR. Multiplies all components by 1 minus
+	// the source color.
+	OneMinusSrcColor = BlendFactor(0x0301)
 	// OneMinusSrcAlpha is GL_ONE_MINUS_SRC_ALPHA. Multiplies all components by 1 minus
 	// the source alpha value.
 	OneMinusSrcAlpha = BlendFactor(0x0303)
@@ -173,12 +180,73 @@ const (
 	// OneMinusDstAlpha is GL_ONE_MINUS_DST_ALPHA. Multiplies all components by 1 minus
 	// the destination alpha value.
 	OneMinusDstAlpha = BlendFactor(0x0305)
+	// DstColor is GL_DST_COLOR. Multiplies all components by the destination color.
+	DstColor = BlendFactor(0x0306)
+	// OneMinusDstColor is GL_ONE_MINUS_DST_COLOR. Multiplies all components by 1 minus
+	// the destination color.
+	OneMinusDstColor = BlendFactor(0x0307)
+	// SrcAlphaSaturate is GL_SRC_ALPHA_SATURATE. Multiplies RGB components by
+	// min(source alpha, 1 - destination alpha) and alpha component by 1.
+	SrcAlphaSaturate = BlendFactor(0x0308)
+	// ConstantColor is GL_CONSTANT_COLOR. Multiplies all components by the color
+	// set using Renderer.SetBlendColor.
+	ConstantColor = BlendFactor(0x8001)
+	// OneMinusConstantColor is GL_ONE_MINUS_CONSTANT_COLOR. Multiplies all components
+	// by 1 minus the color set using Renderer.SetBlendColor.
+	OneMinusConstantColor = BlendFactor(0x8002)
+	// ConstantAlpha is GL_CONSTANT_ALPHA. Multiplies all components by the alpha
+	// of the color set using Renderer.SetBlendColor.
+	ConstantAlpha = BlendFactor(0x8003)
+	// OneMinusConstantAlpha is GL_ONE_MINUS_CONSTANT_ALPHA. Multiplies all components
+	// by 1 minus the alpha of the color set using Renderer.SetBlendColor.
+	OneMinusConstantAlpha = BlendFactor(0x8004)
 )
 
+// BlendEquation specifies how source and destination components are combined.
+// Zero value is treated as FuncAdd.
+type BlendEquation uint32
+
+const (
+	// FuncAdd is GL_FUNC_ADD. R = S*sf + D*df
+	FuncAdd = BlendEquation(0x8006)
+	// FuncSubtract is GL_FUNC_SUBTRACT. R = S*sf - D*df
+	FuncSubtract = BlendEquation(0x800A)
+	// FuncReverseSubtract is GL_FUNC_REVERSE_SUBTRACT. R = D*df - S*sf
+	FuncReverseSubtract = BlendEquation(0x800B)
+	// Min is GL_MIN. R = min(S, D). Factors are ignored.
+	Min = BlendEquation(0x8007)
+	// Max is GL_MAX. R = max(S, D). Factors are ignored.
+	Max = BlendEquation(0x8008)
+)
+
+func (e BlendEquation) glEquation() uint32 {
+	if e == 0 {
+		return uint32(FuncAdd)
+	}
+	return uint32(e)
+}
+
 // BlendFactors contains source and destination factors used by blending formula
 // R = S*sf + D*df
+//
+// Formula can be changed by setting the Equation. By default the same factors
+// and equation are used for all components. Alpha component can have its own
+// factors and equation when SeparateAlpha is true.
 type BlendFactors struct {
 	SrcFactor, DstFactor BlendFactor
+	Equation             BlendEquation
+	// SeparateAlpha enables SrcAlphaFactor, DstAlphaFactor and AlphaEquation for
+	// alpha component.
+	SeparateAlpha                  bool
+	SrcAlphaFactor, DstAlphaFactor BlendFactor
+	AlphaEquation                  BlendEquation
+}
+
+func (f BlendFactors) alpha() (src, dst BlendFactor, equation BlendEquation) {
+	if f.SeparateAlpha {
+		return f.SrcAlphaFactor, f.DstAlphaFactor, f.AlphaEquation
+	}
+	return f.SrcFactor, f.DstFactor, f.Equation
 }
 
 // SourceBlendFactors is default BlendFactors used by AcceleratedCommand.
@@ -190,16 +258,31 @@ func (r *Renderer) SetBlendFactors(factors BlendFactors) {
 	r.blendFactors = factors
 }
 
+// SetBlendColor sets the color used by ConstantColor, OneMinusConstantColor,
+// ConstantAlpha and OneMinusConstantAlpha blend factors. By default it is
+// image.Transparent.
+func (r *Renderer) SetBlendColor(color image.Color) {
+	r.blendColor = color
+}
+
 // DrawArrays draws primitives (such as triangles) using vertices defined in VertexArray.
 //
 // Before primitive is drawn this method validates if
 func (r *Renderer) DrawArrays(array *VertexArray, mode Mode, first, count int) {
 	r.validateAttributeTypes(array)
 	r.api.BindVertexArray(array.id)
-	r.api.BlendFunc(uint32(r.blendFactors.SrcFactor), uint32(r.blendFactors.DstFactor))
+	r.setBlending()
 	r.api.DrawArrays(mode.glMode, int32(first), int32(count))
 }
 
+func (r *Renderer) setBlending() {
+	factors := r.blendFactors
+	srcAlpha, dstAlpha, alphaEquation := factors.alpha()
+	r.api.BlendFuncSeparate(uint32(factors.SrcFactor), uint32(factors.DstFactor), uint32(srcAlpha), uint32(dstAlpha))
+	r.api.BlendEquationSeparate(factors.Equation.glEquation(), alphaEquation.glEquation())
+	r.api.BlendColor(r.blendColor.RGBAf())
+}
+
 func (r *Renderer) validateAttributeTypes(array *VertexArray) {
 	if len(array.layout) > len(r.program.attributes) {
 		msg := fmt.Sprintf("vertex array has more enabled attributes (%d) than program (%d)", len(array.layout), len(r.program.attributes))
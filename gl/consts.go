@@ -45,4 +45,6 @@ const (
 	noError                  = 0
 	outOfMemory              = 0x0505
 	blend                    = 0x0BE2
+	unpackRowLength          = 0x0CF2
+	packRowLength            = 0x0D02
 )
@@ -0,0 +1,436 @@
+package fake
+
+import "unsafe"
+
+// GenBuffers generates buffer object names
+func (a *API) GenBuffers(n int32, buffers *uint32) {
+	a.api.GenBuffers(n, buffers)
+	names := uint32sAt(buffers, n)
+	a.record("GenBuffers", []interface{}{n, names}, nil)
+	a.created(Buffer, names...)
+}
+
+// DeleteBuffers deletes named buffer objects
+func (a *API) DeleteBuffers(n int32, buffers *uint32) {
+	names := uint32sAt(buffers, n)
+	a.recordDelete("DeleteBuffers", Buffer, []interface{}{n, names}, names...)
+	a.api.DeleteBuffers(n, buffers)
+}
+
+// GenVertexArrays generates vertex array object names
+func (a *API) GenVertexArrays(n int32, arrays *uint32) {
+	a.api.GenVertexArrays(n, arrays)
+	names := uint32sAt(arrays, n)
+	a.record("GenVertexArrays", []interface{}{n, names}, nil)
+	a.created(VertexArray, names...)
+}
+
+// DeleteVertexArrays deletes vertex array objects
+func (a *API) DeleteVertexArrays(n int32, arrays *uint32) {
+	names := uint32sAt(arrays, n)
+	a.recordDelete("DeleteVertexArrays", VertexArray, []interface{}{n, names}, names...)
+	a.api.DeleteVertexArrays(n, arrays)
+}
+
+// CreateShader creates a shader object
+func (a *API) CreateShader(xtype uint32) uint32 {
+	shader := a.api.CreateShader(xtype)
+	a.record("CreateShader", []interface{}{xtype}, shader)
+	a.created(Shader, shader)
+	return shader
+}
+
+// DeleteShader deletes a shader object
+func (a *API) DeleteShader(shader uint32) {
+	a.recordDelete("DeleteShader", Shader, []interface{}{shader}, shader)
+	a.api.DeleteShader(shader)
+}
+
+// CreateProgram creates a program object
+func (a *API) CreateProgram() uint32 {
+	program := a.api.CreateProgram()
+	a.record("CreateProgram", []interface{}{}, program)
+	a.created(Program, program)
+	return program
+}
+
+// DeleteProgram deletes a program object
+func (a *API) DeleteProgram(program uint32) {
+	a.recordDelete("DeleteProgram", Program, []interface{}{program}, program)
+	a.api.DeleteProgram(program)
+}
+
+// GetAttribLocation returns the location of an attribute variable
+func (a *API) GetAttribLocation(program uint32, name *uint8) int32 {
+	location := a.api.GetAttribLocation(program, name)
+	a.record("GetAttribLocation", []interface{}{program, a.api.GoStr(name)}, location, Object{Kind: Program, Name: program})
+	return location
+}
+
+// UniformMatrix3fv specifies the value of a uniform variable for the current program object
+func (a *API) UniformMatrix3fv(location int32, count int32, transpose bool, value *float32) {
+	a.record("UniformMatrix3fv", []interface{}{location, count, transpose, float32sAt(value, count*9)}, nil)
+	a.api.UniformMatrix3fv(location, count, transpose, value)
+}
+
+// UniformMatrix4fv specifies the value of a uniform variable for the current program object
+func (a *API) UniformMatrix4fv(location int32, count int32, transpose bool, value *float32) {
+	a.record("UniformMatrix4fv", []interface{}{location, count, transpose, float32sAt(value, count*16)}, nil)
+	a.api.UniformMatrix4fv(location, count, transpose, value)
+}
+
+// GenTextures generates texture names
+func (a *API) GenTextures(n int32, textures *uint32) {
+	a.api.GenTextures(n, textures)
+	names := uint32sAt(textures, n)
+	a.record("GenTextures", []interface{}{n, names}, nil)
+	a.created(Texture, names...)
+}
+
+// DeleteTextures deletes named textures
+func (a *API) DeleteTextures(n int32, textures *uint32) {
+	names := uint32sAt(textures, n)
+	a.recordDelete("DeleteTextures", Texture, []interface{}{n, names}, names...)
+	a.api.DeleteTextures(n, textures)
+}
+
+// GenFramebuffers generates framebuffer object names
+func (a *API) GenFramebuffers(n int32, framebuffers *uint32) {
+	a.api.GenFramebuffers(n, framebuffers)
+	names := uint32sAt(framebuffers, n)
+	a.record("GenFramebuffers", []interface{}{n, names}, nil)
+	a.created(Framebuffer, names...)
+}
+
+// DeleteFramebuffers deletes named framebuffer objects
+func (a *API) DeleteFramebuffers(n int32, framebuffers *uint32) {
+	names := uint32sAt(framebuffers, n)
+	a.recordDelete("DeleteFramebuffers", Framebuffer, []interface{}{n, names}, names...)
+	a.api.DeleteFramebuffers(n, framebuffers)
+}
+
+// Ptr takes a slice or pointer (to a singular scalar value or the first
+// element of an array or slice) and returns its GL-compatible address.
+// It is not recorded.
+func (a *API) Ptr(data interface{}) unsafe.Pointer {
+	return a.api.Ptr(data)
+}
+
+// PtrOffset takes a pointer offset and returns a GL-compatible pointer.
+// It is not recorded.
+func (a *API) PtrOffset(offset int) unsafe.Pointer {
+	return a.api.PtrOffset(offset)
+}
+
+// GoStr takes a null-terminated string returned by OpenGL and constructs a
+// corresponding Go string. It is not recorded.
+func (a *API) GoStr(cstr *uint8) string {
+	return a.api.GoStr(cstr)
+}
+
+// Strs takes a list of Go strings (with or without null-termination) and
+// returns their C counterpart. It is not recorded.
+func (a *API) Strs(strs ...string) (cstrs **uint8, free func()) {
+	return a.api.Strs(strs...)
+}
+
+// BindBuffer binds a named buffer object
+func (a *API) BindBuffer(target uint32, buffer uint32) {
+	a.record("BindBuffer", []interface{}{target, buffer}, nil, Object{Kind: Buffer, Name: buffer})
+	a.api.BindBuffer(target, buffer)
+}
+
+// BufferData creates and initializes a buffer object's data store
+func (a *API) BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+	a.record("BufferData", []interface{}{target, size, data, usage}, nil)
+	a.api.BufferData(target, size, data, usage)
+}
+
+// BufferSubData updates a subset of a buffer object's data store
+func (a *API) BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	a.record("BufferSubData", []interface{}{target, offset, size, data}, nil)
+	a.api.BufferSubData(target, offset, size, data)
+}
+
+// GetBufferSubData returns a subset of a buffer object's data store
+func (a *API) GetBufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	a.record("GetBufferSubData", []interface{}{target, offset, size, data}, nil)
+	a.api.GetBufferSubData(target, offset, size, data)
+}
+
+// BindVertexArray binds a vertex array object
+func (a *API) BindVertexArray(array uint32) {
+	a.record("BindVertexArray", []interface{}{array}, nil, Object{Kind: VertexArray, Name: array})
+	a.api.BindVertexArray(array)
+}
+
+// VertexAttribPointer defines an array of generic vertex attribute data
+func (a *API) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer) {
+	a.record("VertexAttribPointer", []interface{}{index, size, xtype, normalized, stride, pointer}, nil)
+	a.api.VertexAttribPointer(index, size, xtype, normalized, stride, pointer)
+}
+
+// EnableVertexAttribArray enables a generic vertex attribute array
+func (a *API) EnableVertexAttribArray(index uint32) {
+	a.record("EnableVertexAttribArray", []interface{}{index}, nil)
+	a.api.EnableVertexAttribArray(index)
+}
+
+// ShaderSource replaces the source code in a shader object
+func (a *API) ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
+	a.record("ShaderSource", []interface{}{shader, count, xstring, length}, nil, Object{Kind: Shader, Name: shader})
+	a.api.ShaderSource(shader, count, xstring, length)
+}
+
+// CompileShader compiles a shader object
+func (a *API) CompileShader(shader uint32) {
+	a.record("CompileShader", []interface{}{shader}, nil, Object{Kind: Shader, Name: shader})
+	a.api.CompileShader(shader)
+}
+
+// GetShaderiv returns a parameter from a shader object
+func (a *API) GetShaderiv(shader uint32, pname uint32, params *int32) {
+	a.record("GetShaderiv", []interface{}{shader, pname, params}, nil, Object{Kind: Shader, Name: shader})
+	a.api.GetShaderiv(shader, pname, params)
+}
+
+// GetShaderInfoLog returns the information log for a shader object
+func (a *API) GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
+	a.record("GetShaderInfoLog", []interface{}{shader, bufSize, length, infoLog}, nil, Object{Kind: Shader, Name: shader})
+	a.api.GetShaderInfoLog(shader, bufSize, length, infoLog)
+}
+
+// AttachShader attaches a shader object to a program object
+func (a *API) AttachShader(program uint32, shader uint32) {
+	a.record("AttachShader", []interface{}{program, shader}, nil, Object{Kind: Program, Name: program}, Object{Kind: Shader, Name: shader})
+	a.api.AttachShader(program, shader)
+}
+
+// LinkProgram links a program object
+func (a *API) LinkProgram(program uint32) {
+	a.record("LinkProgram", []interface{}{program}, nil, Object{Kind: Program, Name: program})
+	a.api.LinkProgram(program)
+}
+
+// GetProgramiv returns a parameter from a program object
+func (a *API) GetProgramiv(program uint32, pname uint32, params *int32) {
+	a.record("GetProgramiv", []interface{}{program, pname, params}, nil, Object{Kind: Program, Name: program})
+	a.api.GetProgramiv(program, pname, params)
+}
+
+// GetProgramInfoLog returns the information log for a program object
+func (a *API) GetProgramInfoLog(program uint32, bufSize int32, length *int32, infoLog *uint8) {
+	a.record("GetProgramInfoLog", []interface{}{program, bufSize, length, infoLog}, nil, Object{Kind: Program, Name: program})
+	a.api.GetProgramInfoLog(program, bufSize, length, infoLog)
+}
+
+// UseProgram installs a program object as part of current rendering state
+func (a *API) UseProgram(program uint32) {
+	a.record("UseProgram", []interface{}{program}, nil, Object{Kind: Program, Name: program})
+	a.api.UseProgram(program)
+}
+
+// GetActiveUniform returns information about an active uniform variable for the specified program object
+func (a *API) GetActiveUniform(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	a.record("GetActiveUniform", []interface{}{program, index, bufSize, length, size, xtype, name}, nil, Object{Kind: Program, Name: program})
+	a.api.GetActiveUniform(program, index, bufSize, length, size, xtype, name)
+}
+
+// GetActiveAttrib returns information about an active attribute variable for the specified program object
+func (a *API) GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	a.record("GetActiveAttrib", []interface{}{program, index, bufSize, length, size, xtype, name}, nil, Object{Kind: Program, Name: program})
+	a.api.GetActiveAttrib(program, index, bufSize, length, size, xtype, name)
+}
+
+// Enable enables server-side GL capabilities
+func (a *API) Enable(cap uint32) {
+	a.record("Enable", []interface{}{cap}, nil)
+	a.api.Enable(cap)
+}
+
+// Disable disables server-side GL capabilities
+func (a *API) Disable(cap uint32) {
+	a.record("Disable", []interface{}{cap}, nil)
+	a.api.Disable(cap)
+}
+
+// BindFramebuffer binds a framebuffer to a framebuffer target
+func (a *API) BindFramebuffer(target uint32, framebuffer uint32) {
+	a.record("BindFramebuffer", []interface{}{target, framebuffer}, nil, Object{Kind: Framebuffer, Name: framebuffer})
+	a.api.BindFramebuffer(target, framebuffer)
+}
+
+// Scissor defines the scissor box
+func (a *API) Scissor(x int32, y int32, width int32, height int32) {
+	a.record("Scissor", []interface{}{x, y, width, height}, nil)
+	a.api.Scissor(x, y, width, height)
+}
+
+// Viewport sets the viewport
+func (a *API) Viewport(x int32, y int32, width int32, height int32) {
+	a.record("Viewport", []interface{}{x, y, width, height}, nil)
+	a.api.Viewport(x, y, width, height)
+}
+
+// ClearColor specifies clear values for the color buffers
+func (a *API) ClearColor(red float32, green float32, blue float32, alpha float32) {
+	a.record("ClearColor", []interface{}{red, green, blue, alpha}, nil)
+	a.api.ClearColor(red, green, blue, alpha)
+}
+
+// Clear clears buffers to preset values
+func (a *API) Clear(mask uint32) {
+	a.record("Clear", []interface{}{mask}, nil)
+	a.api.Clear(mask)
+}
+
+// DrawArrays render primitives from array data
+func (a *API) DrawArrays(mode uint32, first int32, count int32) {
+	a.record("DrawArrays", []interface{}{mode, first, count}, nil)
+	a.api.DrawArrays(mode, first, count)
+}
+
+// Uniform1f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform1f(location int32, v0 float32) {
+	a.record("Uniform1f", []interface{}{location, v0}, nil)
+	a.api.Uniform1f(location, v0)
+}
+
+// Uniform2f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform2f(location int32, v0 float32, v1 float32) {
+	a.record("Uniform2f", []interface{}{location, v0, v1}, nil)
+	a.api.Uniform2f(location, v0, v1)
+}
+
+// Uniform3f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform3f(location int32, v0 float32, v1 float32, v2 float32) {
+	a.record("Uniform3f", []interface{}{location, v0, v1, v2}, nil)
+	a.api.Uniform3f(location, v0, v1, v2)
+}
+
+// Uniform4f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform4f(location int32, v0 float32, v1 float32, v2 float32, v3 float32) {
+	a.record("Uniform4f", []interface{}{location, v0, v1, v2, v3}, nil)
+	a.api.Uniform4f(location, v0, v1, v2, v3)
+}
+
+// Uniform1i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform1i(location int32, v0 int32) {
+	a.record("Uniform1i", []interface{}{location, v0}, nil)
+	a.api.Uniform1i(location, v0)
+}
+
+// Uniform2i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform2i(location int32, v0 int32, v1 int32) {
+	a.record("Uniform2i", []interface{}{location, v0, v1}, nil)
+	a.api.Uniform2i(location, v0, v1)
+}
+
+// Uniform3i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform3i(location int32, v0 int32, v1 int32, v2 int32) {
+	a.record("Uniform3i", []interface{}{location, v0, v1, v2}, nil)
+	a.api.Uniform3i(location, v0, v1, v2)
+}
+
+// Uniform4i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform4i(location int32, v0 int32, v1 int32, v2 int32, v3 int32) {
+	a.record("Uniform4i", []interface{}{location, v0, v1, v2, v3}, nil)
+	a.api.Uniform4i(location, v0, v1, v2, v3)
+}
+
+// ActiveTexture selects active texture unit
+func (a *API) ActiveTexture(texture uint32) {
+	a.record("ActiveTexture", []interface{}{texture}, nil)
+	a.api.ActiveTexture(texture)
+}
+
+// BindTexture binds a named texture to a texturing target
+func (a *API) BindTexture(target uint32, texture uint32) {
+	a.record("BindTexture", []interface{}{target, texture}, nil, Object{Kind: Texture, Name: texture})
+	a.api.BindTexture(target, texture)
+}
+
+// GetIntegerv returns the value or values of the specified parameter
+func (a *API) GetIntegerv(pname uint32, data *int32) {
+	a.record("GetIntegerv", []interface{}{pname, data}, nil)
+	a.api.GetIntegerv(pname, data)
+}
+
+// TexImage2D specifies a two-dimensional texture image
+func (a *API) TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	a.record("TexImage2D", []interface{}{target, level, internalformat, width, height, border, format, xtype, pixels}, nil)
+	a.api.TexImage2D(target, level, internalformat, width, height, border, format, xtype, pixels)
+}
+
+// TexParameteri sets texture parameter
+func (a *API) TexParameteri(target uint32, pname uint32, param int32) {
+	a.record("TexParameteri", []interface{}{target, pname, param}, nil)
+	a.api.TexParameteri(target, pname, param)
+}
+
+// FramebufferTexture2D attaches a level of a texture object as a logical buffer to the currently bound framebuffer object
+func (a *API) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+	a.record("FramebufferTexture2D", []interface{}{target, attachment, textarget, texture, level}, nil, Object{Kind: Texture, Name: texture})
+	a.api.FramebufferTexture2D(target, attachment, textarget, texture, level)
+}
+
+// PixelStorei sets pixel storage modes
+func (a *API) PixelStorei(pname uint32, param int32) {
+	a.record("PixelStorei", []interface{}{pname, param}, nil)
+	a.api.PixelStorei(pname, param)
+}
+
+// TexSubImage2D specifies a two-dimensional texture subimage
+func (a *API) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	a.record("TexSubImage2D", []interface{}{target, level, xoffset, yoffset, width, height, format, xtype, pixels}, nil)
+	a.api.TexSubImage2D(target, level, xoffset, yoffset, width, height, format, xtype, pixels)
+}
+
+// GetTexImage returns a texture image
+func (a *API) GetTexImage(target uint32, level int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	a.record("GetTexImage", []interface{}{target, level, format, xtype, pixels}, nil)
+	a.api.GetTexImage(target, level, format, xtype, pixels)
+}
+
+// GetError returns error information
+func (a *API) GetError() uint32 {
+	result := a.api.GetError()
+	a.record("GetError", []interface{}{}, result)
+	return result
+}
+
+// ReadPixels reads a block of pixels from the frame buffer
+func (a *API) ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	a.record("ReadPixels", []interface{}{x, y, width, height, format, xtype, pixels}, nil)
+	a.api.ReadPixels(x, y, width, height, format, xtype, pixels)
+}
+
+// BlendFunc specifies pixel arithmetic
+func (a *API) BlendFunc(sfactor uint32, dfactor uint32) {
+	a.record("BlendFunc", []interface{}{sfactor, dfactor}, nil)
+	a.api.BlendFunc(sfactor, dfactor)
+}
+
+// BlendFuncSeparate specifies pixel arithmetic for RGB and alpha components separately
+func (a *API) BlendFuncSeparate(srcRGB uint32, dstRGB uint32, srcAlpha uint32, dstAlpha uint32) {
+	a.record("BlendFuncSeparate", []interface{}{srcRGB, dstRGB, srcAlpha, dstAlpha}, nil)
+	a.api.BlendFuncSeparate(srcRGB, dstRGB, srcAlpha, dstAlpha)
+}
+
+// BlendEquationSeparate sets the RGB blend equation and the alpha blend equation separately
+func (a *API) BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
+	a.record("BlendEquationSeparate", []interface{}{modeRGB, modeAlpha}, nil)
+	a.api.BlendEquationSeparate(modeRGB, modeAlpha)
+}
+
+// BlendColor sets the blend color
+func (a *API) BlendColor(red float32, green float32, blue float32, alpha float32) {
+	a.record("BlendColor", []interface{}{red, green, blue, alpha}, nil)
+	a.api.BlendColor(red, green, blue, alpha)
+}
+
+// Finish blocks until all GL execution is complete
+func (a *API) Finish() {
+	a.record("Finish", []interface{}{}, nil)
+	a.api.Finish()
+}
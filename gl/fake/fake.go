@@ -0,0 +1,321 @@
+// Package fake provides a recording gl.API implementation which can be used
+// in unit testing.
+//
+// API records every call together with its arguments, tracks the lifetime of
+// OpenGL objects (buffers, vertex arrays, shaders, programs, textures and
+// framebuffers) and reports objects which were used after being deleted or
+// never deleted at all. API created with NewAPI does not render anything,
+// therefore gl.Context and all the tools built on top of it work without
+// a video card. Wrap can be used to delegate calls to another gl.API.
+package fake
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/jacekolszak/pixiq/gl"
+)
+
+// NewAPI returns a new recording API which does not render anything. It
+// generates object names, reports successful compilation and linking of
+// shaders and never reports errors.
+func NewAPI() *API {
+	return Wrap(&stub{})
+}
+
+// Wrap returns a new recording API delegating all calls to a given api.
+func Wrap(api gl.API) *API {
+	if api == nil {
+		panic("nil api")
+	}
+	return &API{
+		api:     api,
+		objects: map[Object]bool{},
+	}
+}
+
+// API is a gl.API recording all calls. Helper methods which are not OpenGL
+// functions (Ptr, PtrOffset, GoStr and Strs) are delegated but not recorded.
+type API struct {
+	api   gl.API
+	calls []Call
+	// objects contains all created objects. Value is true when the object was
+	// deleted.
+	objects        map[Object]bool
+	useAfterDelete []Call
+}
+
+// Call is a recorded call of gl.API method.
+type Call struct {
+	Method string
+	// Args are arguments passed to the method. Names generated by Gen*
+	// methods and names passed to Delete* methods are recorded as []uint32
+	// slices. Matrices passed to UniformMatrix*fv are recorded as []float32
+	// slices and attribute name passed to GetAttribLocation as string.
+	Args []interface{}
+	// Result is a value returned by the method or nil
+	Result interface{}
+}
+
+// String returns the call in a form of Method(arg1, arg2, ...)
+func (c Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = fmt.Sprintf("%v", arg)
+	}
+	s := c.Method + "(" + strings.Join(args, ", ") + ")"
+	if c.Result != nil {
+		s += fmt.Sprintf(" = %v", c.Result)
+	}
+	return s
+}
+
+// ObjectKind is a kind of OpenGL object
+type ObjectKind int
+
+const (
+	// Buffer is a buffer object created with GenBuffers
+	Buffer ObjectKind = iota
+	// VertexArray is a vertex array object created with GenVertexArrays
+	VertexArray
+	// Shader is a shader object created with CreateShader
+	Shader
+	// Program is a program object created with CreateProgram
+	Program
+	// Texture is a texture object created with GenTextures
+	Texture
+	// Framebuffer is a framebuffer object created with GenFramebuffers
+	Framebuffer
+)
+
+var objectKindNames = []string{"Buffer", "VertexArray", "Shader", "Program", "Texture", "Framebuffer"}
+
+func (k ObjectKind) String() string {
+	if k < 0 || int(k) >= len(objectKindNames) {
+		return fmt.Sprintf("ObjectKind(%d)", int(k))
+	}
+	return objectKindNames[k]
+}
+
+// Object is an OpenGL object identified by its kind and name.
+type Object struct {
+	Kind ObjectKind
+	Name uint32
+}
+
+func (o Object) String() string {
+	return fmt.Sprintf("%s %d", o.Kind, o.Name)
+}
+
+// Calls returns all recorded calls in order.
+func (a *API) Calls() []Call {
+	calls := make([]Call, len(a.calls))
+	copy(calls, a.calls)
+	return calls
+}
+
+// CallsTo returns recorded calls of a given method in order.
+func (a *API) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range a.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets recorded calls. Object lifetimes are still tracked.
+func (a *API) Reset() {
+	a.calls = nil
+	a.useAfterDelete = nil
+}
+
+// Leaks returns objects which were created but not deleted, sorted by kind
+// and name.
+func (a *API) Leaks() []Object {
+	var leaks []Object
+	for object, deleted := range a.objects {
+		if !deleted {
+			leaks = append(leaks, object)
+		}
+	}
+	sort.Slice(leaks, func(i, j int) bool {
+		if leaks[i].Kind != leaks[j].Kind {
+			return leaks[i].Kind < leaks[j].Kind
+		}
+		return leaks[i].Name < leaks[j].Name
+	})
+	return leaks
+}
+
+// UseAfterDelete returns calls which used already deleted objects, including
+// calls deleting the same object twice.
+func (a *API) UseAfterDelete() []Call {
+	calls := make([]Call, len(a.useAfterDelete))
+	copy(calls, a.useAfterDelete)
+	return calls
+}
+
+// Matcher matches the argument of recorded call. Matchers can be passed to
+// Expect instead of argument values.
+type Matcher func(arg interface{}) bool
+
+// Any matches any argument
+var Any Matcher = func(interface{}) bool {
+	return true
+}
+
+// Expectation describes the call which is expected to be recorded. Can be
+// created using Expect function.
+type Expectation struct {
+	Method string
+	Args   []interface{}
+}
+
+// Expect returns an expectation of a call to a given method with given
+// arguments. Each argument can be a value or a Matcher. Numeric values are
+// compared by value, not by type, so untyped constants can be used.
+// When no arguments are given the expectation matches the call with any
+// arguments.
+func Expect(method string, args ...interface{}) Expectation {
+	return Expectation{Method: method, Args: args}
+}
+
+// Matches returns true if the call fulfills the expectation.
+func (e Expectation) Matches(call Call) bool {
+	if call.Method != e.Method {
+		return false
+	}
+	if len(e.Args) == 0 {
+		return true
+	}
+	if len(e.Args) != len(call.Args) {
+		return false
+	}
+	for i, expected := range e.Args {
+		if !argMatches(expected, call.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func argMatches(expected, actual interface{}) bool {
+	if matcher, ok := expected.(Matcher); ok {
+		return matcher(actual)
+	}
+	e, eNumeric := number(expected)
+	a, aNumeric := number(actual)
+	if eNumeric && aNumeric {
+		return e == a
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+func number(v interface{}) (float64, bool) {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+// Called returns true if the expected call was recorded.
+func (a *API) Called(expectation Expectation) bool {
+	return a.Count(expectation) > 0
+}
+
+// Count returns the number of recorded calls fulfilling the expectation.
+func (a *API) Count(expectation Expectation) int {
+	count := 0
+	for _, call := range a.calls {
+		if expectation.Matches(call) {
+			count++
+		}
+	}
+	return count
+}
+
+// CalledInOrder returns true if expected calls were recorded in a given order.
+// Other calls may be recorded in between.
+func (a *API) CalledInOrder(expectations ...Expectation) bool {
+	next := 0
+	for _, call := range a.calls {
+		if next == len(expectations) {
+			break
+		}
+		if expectations[next].Matches(call) {
+			next++
+		}
+	}
+	return next == len(expectations)
+}
+
+func (a *API) record(method string, args []interface{}, result interface{}, uses ...Object) {
+	call := Call{Method: method, Args: args, Result: result}
+	a.calls = append(a.calls, call)
+	for _, object := range uses {
+		if object.Name != 0 && a.objects[object] {
+			a.useAfterDelete = append(a.useAfterDelete, call)
+			return
+		}
+	}
+}
+
+func (a *API) created(kind ObjectKind, names ...uint32) {
+	for _, name := range names {
+		if name != 0 {
+			a.objects[Object{Kind: kind, Name: name}] = false
+		}
+	}
+}
+
+func (a *API) recordDelete(method string, kind ObjectKind, args []interface{}, names ...uint32) {
+	uses := make([]Object, len(names))
+	for i, name := range names {
+		uses[i] = Object{Kind: kind, Name: name}
+	}
+	a.record(method, args, nil, uses...)
+	for _, object := range uses {
+		if _, ok := a.objects[object]; ok {
+			a.objects[object] = true
+		}
+	}
+}
+
+func uint32sAt(pointer *uint32, length int32) []uint32 {
+	if pointer == nil || length <= 0 {
+		return nil
+	}
+	var uints []uint32
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&uints))
+	header.Data = uintptr(unsafe.Pointer(pointer))
+	header.Len = int(length)
+	header.Cap = int(length)
+	result := make([]uint32, length)
+	copy(result, uints)
+	return result
+}
+
+func float32sAt(pointer *float32, length int32) []float32 {
+	if pointer == nil || length <= 0 {
+		return nil
+	}
+	var floats []float32
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&floats))
+	header.Data = uintptr(unsafe.Pointer(pointer))
+	header.Len = int(length)
+	header.Cap = int(length)
+	result := make([]float32, length)
+	copy(result, floats)
+	return result
+}
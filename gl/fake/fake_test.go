@@ -0,0 +1,286 @@
+package fake_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/gl"
+	"github.com/jacekolszak/pixiq/gl/fake"
+)
+
+func TestNewAPI(t *testing.T) {
+	t.Run("should implement gl.API", func(t *testing.T) {
+		var api gl.API = fake.NewAPI()
+		// when
+		context := gl.NewContext(api)
+		// then
+		assert.NoError(t, context.Error())
+	})
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("should panic when api is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			fake.Wrap(nil)
+		})
+	})
+	t.Run("should delegate calls", func(t *testing.T) {
+		wrapped := fake.NewAPI()
+		api := fake.Wrap(wrapped)
+		// when
+		program := api.CreateProgram()
+		api.UseProgram(program)
+		// then
+		calls := wrapped.CallsTo("UseProgram")
+		require.Len(t, calls, 1)
+		assert.Equal(t, []interface{}{program}, calls[0].Args)
+	})
+}
+
+func TestAPI_Calls(t *testing.T) {
+	t.Run("should record calls with arguments", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		api.Viewport(1, 2, 3, 4)
+		api.ClearColor(0.1, 0.2, 0.3, 0.4)
+		// then
+		assert.Equal(t, []fake.Call{
+			{Method: "Viewport", Args: []interface{}{int32(1), int32(2), int32(3), int32(4)}},
+			{Method: "ClearColor", Args: []interface{}{float32(0.1), float32(0.2), float32(0.3), float32(0.4)}},
+		}, api.Calls())
+	})
+	t.Run("should record result", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		program := api.CreateProgram()
+		// then
+		calls := api.CallsTo("CreateProgram")
+		require.Len(t, calls, 1)
+		assert.Equal(t, program, calls[0].Result)
+	})
+	t.Run("should record generated names", func(t *testing.T) {
+		api := fake.NewAPI()
+		var names [2]uint32
+		// when
+		api.GenTextures(2, &names[0])
+		// then
+		calls := api.CallsTo("GenTextures")
+		require.Len(t, calls, 1)
+		assert.Equal(t, []interface{}{int32(2), names[:]}, calls[0].Args)
+	})
+	t.Run("should record matrix values", func(t *testing.T) {
+		api := fake.NewAPI()
+		matrix := [9]float32{1, 2, 3, 4, 5, 6, 7, 8, 9}
+		// when
+		api.UniformMatrix3fv(0, 1, false, &matrix[0])
+		// then
+		calls := api.CallsTo("UniformMatrix3fv")
+		require.Len(t, calls, 1)
+		assert.Equal(t, matrix[:], calls[0].Args[3])
+	})
+	t.Run("should not record helper methods", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		api.Ptr([]float32{1})
+		api.PtrOffset(0)
+		// then
+		assert.Empty(t, api.Calls())
+	})
+	t.Run("Reset should forget recorded calls", func(t *testing.T) {
+		api := fake.NewAPI()
+		api.Finish()
+		// when
+		api.Reset()
+		// then
+		assert.Empty(t, api.Calls())
+	})
+}
+
+func TestCall_String(t *testing.T) {
+	tests := map[string]struct {
+		call     fake.Call
+		expected string
+	}{
+		"no args": {
+			call:     fake.Call{Method: "Finish"},
+			expected: "Finish()",
+		},
+		"args": {
+			call:     fake.Call{Method: "Viewport", Args: []interface{}{int32(1), int32(2), int32(3), int32(4)}},
+			expected: "Viewport(1, 2, 3, 4)",
+		},
+		"result": {
+			call:     fake.Call{Method: "CreateShader", Args: []interface{}{uint32(35633)}, Result: uint32(1)},
+			expected: "CreateShader(35633) = 1",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.call.String())
+		})
+	}
+}
+
+func TestExpectation_Matches(t *testing.T) {
+	call := fake.Call{Method: "Viewport", Args: []interface{}{int32(1), int32(2), int32(3), int32(4)}}
+	tests := map[string]struct {
+		expectation fake.Expectation
+		expected    bool
+	}{
+		"untyped constants": {
+			expectation: fake.Expect("Viewport", 1, 2, 3, 4),
+			expected:    true,
+		},
+		"no args": {
+			expectation: fake.Expect("Viewport"),
+			expected:    true,
+		},
+		"Any matcher": {
+			expectation: fake.Expect("Viewport", fake.Any, fake.Any, 3, 4),
+			expected:    true,
+		},
+		"custom matcher": {
+			expectation: fake.Expect("Viewport", fake.Matcher(func(arg interface{}) bool {
+				return arg.(int32) > 0
+			}), 2, 3, 4),
+			expected: true,
+		},
+		"different method": {
+			expectation: fake.Expect("Scissor", 1, 2, 3, 4),
+			expected:    false,
+		},
+		"different arg": {
+			expectation: fake.Expect("Viewport", 1, 2, 3, 5),
+			expected:    false,
+		},
+		"different number of args": {
+			expectation: fake.Expect("Viewport", 1, 2, 3),
+			expected:    false,
+		},
+		"different arg type": {
+			expectation: fake.Expect("Viewport", "1", 2, 3, 4),
+			expected:    false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.expectation.Matches(call))
+		})
+	}
+}
+
+func TestAPI_Called(t *testing.T) {
+	api := fake.NewAPI()
+	api.Enable(0x0BE2)
+	api.Viewport(0, 0, 1, 1)
+	api.Viewport(0, 0, 2, 2)
+	api.Disable(0x0BE2)
+
+	t.Run("Called", func(t *testing.T) {
+		assert.True(t, api.Called(fake.Expect("Viewport", 0, 0, 2, 2)))
+		assert.False(t, api.Called(fake.Expect("Viewport", 0, 0, 3, 3)))
+	})
+	t.Run("Count", func(t *testing.T) {
+		assert.Equal(t, 2, api.Count(fake.Expect("Viewport")))
+		assert.Equal(t, 0, api.Count(fake.Expect("Scissor")))
+	})
+	t.Run("CalledInOrder", func(t *testing.T) {
+		assert.True(t, api.CalledInOrder(
+			fake.Expect("Enable", 0x0BE2),
+			fake.Expect("Viewport", 0, 0, 2, 2),
+			fake.Expect("Disable", 0x0BE2),
+		))
+		assert.False(t, api.CalledInOrder(
+			fake.Expect("Disable", 0x0BE2),
+			fake.Expect("Enable", 0x0BE2),
+		))
+		assert.True(t, api.CalledInOrder())
+	})
+}
+
+func TestAPI_Leaks(t *testing.T) {
+	t.Run("should return objects not deleted", func(t *testing.T) {
+		api := fake.NewAPI()
+		var textures [2]uint32
+		api.GenTextures(2, &textures[0])
+		program := api.CreateProgram()
+		// when
+		api.DeleteTextures(1, &textures[0])
+		// then
+		assert.Equal(t, []fake.Object{
+			{Kind: fake.Program, Name: program},
+			{Kind: fake.Texture, Name: textures[1]},
+		}, api.Leaks())
+	})
+	t.Run("should not report leaks when all objects used by Context were deleted", func(t *testing.T) {
+		api := fake.NewAPI()
+		context := gl.NewContext(api)
+		img := context.NewAcceleratedImage(1, 1)
+		buffer := context.NewFloatVertexBuffer(1, gl.StaticDraw)
+		// when
+		img.Delete()
+		buffer.Delete()
+		// then
+		for _, leak := range api.Leaks() {
+			assert.NotEqual(t, fake.Texture, leak.Kind)
+			assert.NotEqual(t, fake.Buffer, leak.Kind)
+		}
+	})
+}
+
+func TestAPI_UseAfterDelete(t *testing.T) {
+	t.Run("should return call using deleted object", func(t *testing.T) {
+		api := fake.NewAPI()
+		var texture uint32
+		api.GenTextures(1, &texture)
+		api.DeleteTextures(1, &texture)
+		// when
+		api.BindTexture(0x0DE1, texture)
+		// then
+		assert.Equal(t, []fake.Call{
+			{Method: "BindTexture", Args: []interface{}{uint32(0x0DE1), texture}},
+		}, api.UseAfterDelete())
+	})
+	t.Run("should return call deleting object twice", func(t *testing.T) {
+		api := fake.NewAPI()
+		program := api.CreateProgram()
+		api.DeleteProgram(program)
+		// when
+		api.DeleteProgram(program)
+		// then
+		assert.Equal(t, []fake.Call{
+			{Method: "DeleteProgram", Args: []interface{}{program}},
+		}, api.UseAfterDelete())
+	})
+	t.Run("should ignore name 0", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		api.BindFramebuffer(0x8D40, 0)
+		// then
+		assert.Empty(t, api.UseAfterDelete())
+	})
+	t.Run("should not return call using not deleted object", func(t *testing.T) {
+		api := fake.NewAPI()
+		var buffer uint32
+		api.GenBuffers(1, &buffer)
+		// when
+		api.BindBuffer(0x8892, buffer)
+		// then
+		assert.Empty(t, api.UseAfterDelete())
+	})
+	t.Run("Context should not use deleted image", func(t *testing.T) {
+		api := fake.NewAPI()
+		context := gl.NewContext(api)
+		img := context.NewAcceleratedImage(1, 1)
+		// when
+		img.Delete()
+		// then
+		assert.Empty(t, api.UseAfterDelete())
+		assert.True(t, api.CalledInOrder(
+			fake.Expect("GenTextures"),
+			fake.Expect("DeleteTextures"),
+		))
+	})
+}
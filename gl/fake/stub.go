@@ -0,0 +1,256 @@
+package fake
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+const (
+	maxTextureSizeValue = 4096
+
+	maxTextureSize = 0x0D33
+	compileStatus  = 0x8B81
+	linkStatus     = 0x8B82
+)
+
+// stub is a gl.API which does not render anything. It generates object names,
+// reports successful compilation and linking and no errors.
+type stub struct {
+	lastName uint32
+}
+
+func (s *stub) genNames(n int32, names *uint32) {
+	if names == nil || n <= 0 {
+		return
+	}
+	var slice []uint32
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&slice))
+	header.Data = uintptr(unsafe.Pointer(names))
+	header.Len = int(n)
+	header.Cap = int(n)
+	for i := range slice {
+		slice[i] = s.genName()
+	}
+}
+
+func (s *stub) genName() uint32 {
+	s.lastName++
+	return s.lastName
+}
+
+func (s *stub) GenBuffers(n int32, buffers *uint32) {
+	s.genNames(n, buffers)
+}
+
+func (s *stub) BindBuffer(uint32, uint32) {}
+
+func (s *stub) BufferData(uint32, int, unsafe.Pointer, uint32) {}
+
+func (s *stub) BufferSubData(uint32, int, int, unsafe.Pointer) {}
+
+func (s *stub) GetBufferSubData(uint32, int, int, unsafe.Pointer) {}
+
+func (s *stub) DeleteBuffers(int32, *uint32) {}
+
+func (s *stub) GenVertexArrays(n int32, arrays *uint32) {
+	s.genNames(n, arrays)
+}
+
+func (s *stub) DeleteVertexArrays(int32, *uint32) {}
+
+func (s *stub) BindVertexArray(uint32) {}
+
+func (s *stub) VertexAttribPointer(uint32, int32, uint32, bool, int32, unsafe.Pointer) {}
+
+func (s *stub) EnableVertexAttribArray(uint32) {}
+
+func (s *stub) CreateShader(uint32) uint32 {
+	return s.genName()
+}
+
+func (s *stub) ShaderSource(uint32, int32, **uint8, *int32) {}
+
+func (s *stub) CompileShader(uint32) {}
+
+func (s *stub) GetShaderiv(_ uint32, pname uint32, params *int32) {
+	*params = boolToInt(pname == compileStatus)
+}
+
+func (s *stub) GetShaderInfoLog(_ uint32, _ int32, length *int32, _ *uint8) {
+	if length != nil {
+		*length = 0
+	}
+}
+
+func (s *stub) DeleteShader(uint32) {}
+
+func (s *stub) AttachShader(uint32, uint32) {}
+
+func (s *stub) LinkProgram(uint32) {}
+
+func (s *stub) GetProgramiv(_ uint32, pname uint32, params *int32) {
+	*params = boolToInt(pname == linkStatus)
+}
+
+func (s *stub) GetProgramInfoLog(_ uint32, _ int32, length *int32, _ *uint8) {
+	if length != nil {
+		*length = 0
+	}
+}
+
+func (s *stub) UseProgram(uint32) {}
+
+func (s *stub) CreateProgram() uint32 {
+	return s.genName()
+}
+
+func (s *stub) DeleteProgram(uint32) {}
+
+func (s *stub) GetActiveUniform(uint32, uint32, int32, *int32, *int32, *uint32, *uint8) {}
+
+func (s *stub) GetActiveAttrib(uint32, uint32, int32, *int32, *int32, *uint32, *uint8) {}
+
+func (s *stub) GetAttribLocation(uint32, *uint8) int32 {
+	return -1
+}
+
+func (s *stub) Enable(uint32) {}
+
+func (s *stub) Disable(uint32) {}
+
+func (s *stub) BindFramebuffer(uint32, uint32) {}
+
+func (s *stub) Scissor(int32, int32, int32, int32) {}
+
+func (s *stub) Viewport(int32, int32, int32, int32) {}
+
+func (s *stub) ClearColor(float32, float32, float32, float32) {}
+
+func (s *stub) Clear(uint32) {}
+
+func (s *stub) DrawArrays(uint32, int32, int32) {}
+
+func (s *stub) Uniform1f(int32, float32) {}
+
+func (s *stub) Uniform2f(int32, float32, float32) {}
+
+func (s *stub) Uniform3f(int32, float32, float32, float32) {}
+
+func (s *stub) Uniform4f(int32, float32, float32, float32, float32) {}
+
+func (s *stub) Uniform1i(int32, int32) {}
+
+func (s *stub) Uniform2i(int32, int32, int32) {}
+
+func (s *stub) Uniform3i(int32, int32, int32, int32) {}
+
+func (s *stub) Uniform4i(int32, int32, int32, int32, int32) {}
+
+func (s *stub) UniformMatrix3fv(int32, int32, bool, *float32) {}
+
+func (s *stub) UniformMatrix4fv(int32, int32, bool, *float32) {}
+
+func (s *stub) ActiveTexture(uint32) {}
+
+func (s *stub) BindTexture(uint32, uint32) {}
+
+func (s *stub) GetIntegerv(pname uint32, data *int32) {
+	if pname == maxTextureSize {
+		*data = maxTextureSizeValue
+	}
+}
+
+func (s *stub) GenTextures(n int32, textures *uint32) {
+	s.genNames(n, textures)
+}
+
+func (s *stub) DeleteTextures(int32, *uint32) {}
+
+func (s *stub) TexImage2D(uint32, int32, int32, int32, int32, int32, uint32, uint32, unsafe.Pointer) {
+}
+
+func (s *stub) TexParameteri(uint32, uint32, int32) {}
+
+func (s *stub) GenFramebuffers(n int32, framebuffers *uint32) {
+	s.genNames(n, framebuffers)
+}
+
+func (s *stub) DeleteFramebuffers(int32, *uint32) {}
+
+func (s *stub) FramebufferTexture2D(uint32, uint32, uint32, uint32, int32) {}
+
+func (s *stub) PixelStorei(uint32, int32) {}
+
+func (s *stub) TexSubImage2D(uint32, int32, int32, int32, int32, int32, uint32, uint32, unsafe.Pointer) {
+}
+
+func (s *stub) GetTexImage(uint32, int32, uint32, uint32, unsafe.Pointer) {}
+
+func (s *stub) GetError() uint32 {
+	return 0
+}
+
+func (s *stub) ReadPixels(int32, int32, int32, int32, uint32, uint32, unsafe.Pointer) {}
+
+func (s *stub) BlendFunc(uint32, uint32) {}
+
+func (s *stub) BlendFuncSeparate(uint32, uint32, uint32, uint32) {}
+
+func (s *stub) BlendEquationSeparate(uint32, uint32) {}
+
+func (s *stub) BlendColor(float32, float32, float32, float32) {}
+
+func (s *stub) Finish() {}
+
+func (s *stub) Ptr(data interface{}) unsafe.Pointer {
+	if data == nil {
+		return nil
+	}
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return unsafe.Pointer(v.Pointer())
+	default:
+		panic(fmt.Sprintf("unsupported type %s; must be a slice or pointer", v.Type()))
+	}
+}
+
+// PtrOffset returns a pointer which is never dereferenced by stub
+func (s *stub) PtrOffset(offset int) unsafe.Pointer {
+	return unsafe.Pointer(&offset)
+}
+
+func (s *stub) GoStr(cstr *uint8) string {
+	if cstr == nil {
+		return ""
+	}
+	var bytes []byte
+	for p := unsafe.Pointer(cstr); *(*uint8)(p) != 0; p = unsafe.Pointer(uintptr(p) + 1) {
+		bytes = append(bytes, *(*uint8)(p))
+	}
+	return string(bytes)
+}
+
+func (s *stub) Strs(strs ...string) (cstrs **uint8, free func()) {
+	if len(strs) == 0 {
+		panic("Strs: expected at least 1 string")
+	}
+	pointers := make([]*uint8, len(strs))
+	for i, str := range strs {
+		if !strings.HasSuffix(str, "\x00") {
+			str += "\x00"
+		}
+		bytes := []byte(str)
+		pointers[i] = &bytes[0]
+	}
+	return &pointers[0], func() {}
+}
+
+func boolToInt(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
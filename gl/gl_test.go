@@ -544,6 +544,7 @@ func (a apiStub) GenFramebuffers(n int32, framebuffers *uint32)          {}
 func (a apiStub) DeleteFramebuffers(n int32, framebuffers *uint32)       {}
 func (a apiStub) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
 }
+func (a apiStub) PixelStorei(pname uint32, param int32) {}
 func (a apiStub) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 }
 func (a apiStub) GetTexImage(target uint32, level int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
@@ -552,6 +553,10 @@ func (a apiStub) GetError() uint32 { return 0 }
 func (a apiStub) ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 }
 func (a apiStub) BlendFunc(sfactor uint32, dfactor uint32) {}
-func (a apiStub) Finish()                                  {}
-func (a apiStub) Ptr(data interface{}) unsafe.Pointer      { return nil }
-func (a apiStub) PtrOffset(offset int) unsafe.Pointer      { return nil }
+func (a apiStub) BlendFuncSeparate(srcRGB uint32, dstRGB uint32, srcAlpha uint32, dstAlpha uint32) {
+}
+func (a apiStub) BlendEquationSeparate(modeRGB uint32, modeAlpha uint32)             {}
+func (a apiStub) BlendColor(red float32, green float32, blue float32, alpha float32) {}
+func (a apiStub) Finish()                                                            {}
+func (a apiStub) Ptr(data interface{}) unsafe.Pointer                                { return nil }
+func (a apiStub) PtrOffset(offset int) unsafe.Pointer                                { return nil }
@@ -114,6 +114,30 @@ func (i *AcceleratedImage) Upload(pixels []image.Color) {
 	)
 }
 
+// UploadRegion sends pixels in a given region to video card. Region uses image
+// coordinates with the origin at the top-left corner.
+func (i *AcceleratedImage) UploadRegion(pixels []image.Color, region image.AcceleratedImageLocation) {
+	if len(pixels) == 0 || region.Width <= 0 || region.Height <= 0 {
+		return
+	}
+	// pixels are stored starting from the bottom line, same as in OpenGL
+	y := i.height - region.Y - region.Height
+	i.api.BindTexture(texture2D, i.textureID)
+	i.api.PixelStorei(unpackRowLength, int32(i.width))
+	i.api.TexSubImage2D(
+		texture2D,
+		0,
+		int32(region.X),
+		int32(y),
+		int32(region.Width),
+		int32(region.Height),
+		rgba,
+		unsignedByte,
+		i.api.Ptr(pixels[y*i.width+region.X:]),
+	)
+	i.api.PixelStorei(unpackRowLength, 0)
+}
+
 // Download gets pixels pixels from video card
 func (i *AcceleratedImage) Download(output []image.Color) {
 	if len(output) == 0 {
@@ -129,6 +153,29 @@ func (i *AcceleratedImage) Download(output []image.Color) {
 	)
 }
 
+// DownloadRegion gets pixels in a given region from video card. Region uses
+// image coordinates with the origin at the top-left corner. Pixels are read
+// from the image framebuffer, afterwards the default framebuffer is bound.
+func (i *AcceleratedImage) DownloadRegion(output []image.Color, region image.AcceleratedImageLocation) {
+	if len(output) == 0 || region.Width <= 0 || region.Height <= 0 {
+		return
+	}
+	y := i.height - region.Y - region.Height
+	i.api.BindFramebuffer(framebuffer, i.frameBufferID)
+	i.api.PixelStorei(packRowLength, int32(i.width))
+	i.api.ReadPixels(
+		int32(region.X),
+		int32(y),
+		int32(region.Width),
+		int32(region.Height),
+		rgba,
+		unsignedByte,
+		i.api.Ptr(output[y*i.width+region.X:]),
+	)
+	i.api.PixelStorei(packRowLength, 0)
+	i.api.BindFramebuffer(framebuffer, 0)
+}
+
 // Width returns the number of pixels in a row.
 func (i *AcceleratedImage) Width() int {
 	return i.width
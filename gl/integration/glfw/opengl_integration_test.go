@@ -467,6 +467,111 @@ func TestAcceleratedImage_Upload(t *testing.T) {
 	})
 }
 
+func TestAcceleratedImage_UploadRegion(t *testing.T) {
+	var (
+		color1 = image.RGBA(10, 20, 30, 40)
+		color2 = image.RGBA(50, 60, 70, 80)
+		color3 = image.RGBA(90, 100, 110, 120)
+	)
+	tests := map[string]struct {
+		region         image.AcceleratedImageLocation
+		expectedColors []image.Color
+	}{
+		"empty region": {
+			region:         image.AcceleratedImageLocation{X: 1, Y: 0},
+			expectedColors: []image.Color{color1, color1, color1, color1, color1, color1},
+		},
+		"top line": {
+			region:         image.AcceleratedImageLocation{X: 1, Y: 0, Width: 2, Height: 1},
+			expectedColors: []image.Color{color1, color1, color1, color1, color2, color3},
+		},
+		"bottom line": {
+			region:         image.AcceleratedImageLocation{X: 0, Y: 1, Width: 1, Height: 1},
+			expectedColors: []image.Color{color2, color1, color1, color1, color1, color1},
+		},
+		"column": {
+			region:         image.AcceleratedImageLocation{X: 2, Y: 0, Width: 1, Height: 2},
+			expectedColors: []image.Color{color1, color1, color2, color1, color1, color3},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+			defer openGL.Destroy()
+			img := openGL.Context().NewAcceleratedImage(3, 2)
+			img.Upload([]image.Color{color1, color1, color1, color1, color1, color1})
+			pixels := []image.Color{color2, color2, color2, color3, color3, color3}
+			// when
+			img.UploadRegion(pixels, test.region)
+			// then
+			assertColors(t, test.expectedColors, img)
+		})
+	}
+}
+
+func TestAcceleratedImage_DownloadRegion(t *testing.T) {
+	var (
+		color1 = image.RGBA(10, 20, 30, 40)
+		color2 = image.RGBA(50, 60, 70, 80)
+		color3 = image.RGBA(90, 100, 110, 120)
+		color4 = image.RGBA(130, 140, 150, 160)
+		color5 = image.RGBA(170, 180, 190, 200)
+		color6 = image.RGBA(210, 220, 230, 240)
+		pixels = []image.Color{color1, color2, color3, color4, color5, color6}
+		t0     = image.Transparent
+	)
+	tests := map[string]struct {
+		region         image.AcceleratedImageLocation
+		expectedOutput []image.Color
+	}{
+		"empty region": {
+			region:         image.AcceleratedImageLocation{X: 1, Y: 0},
+			expectedOutput: []image.Color{t0, t0, t0, t0, t0, t0},
+		},
+		"top line": {
+			region:         image.AcceleratedImageLocation{X: 1, Y: 0, Width: 2, Height: 1},
+			expectedOutput: []image.Color{t0, t0, t0, t0, color5, color6},
+		},
+		"bottom line": {
+			region:         image.AcceleratedImageLocation{X: 0, Y: 1, Width: 1, Height: 1},
+			expectedOutput: []image.Color{color1, t0, t0, t0, t0, t0},
+		},
+		"column": {
+			region:         image.AcceleratedImageLocation{X: 2, Y: 0, Width: 1, Height: 2},
+			expectedOutput: []image.Color{t0, t0, color3, t0, t0, color6},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+			defer openGL.Destroy()
+			img := openGL.Context().NewAcceleratedImage(3, 2)
+			img.Upload(pixels)
+			output := make([]image.Color, len(pixels))
+			// when
+			img.DownloadRegion(output, test.region)
+			// then
+			assert.Equal(t, test.expectedOutput, output)
+		})
+	}
+	t.Run("should not modify image when drawing after region download", func(t *testing.T) {
+		const colorBufferBit = 0x00004000
+		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+		defer openGL.Destroy()
+		api := openGL.ContextAPI()
+		img := openGL.Context().NewAcceleratedImage(3, 2)
+		img.Upload(pixels)
+		img.DownloadRegion(make([]image.Color, len(pixels)), image.AcceleratedImageLocation{Width: 1, Height: 1})
+		// when
+		api.ClearColor(1, 1, 1, 1)
+		api.Clear(colorBufferBit)
+		// then
+		output := make([]image.Color, len(pixels))
+		img.Download(output)
+		assert.Equal(t, pixels, output)
+	})
+}
+
 func TestAcceleratedImage_Delete(t *testing.T) {
 	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
 	defer openGL.Destroy()
@@ -1694,6 +1799,7 @@ func TestRenderer_SetBlend(t *testing.T) {
 	dstColor := image.RGBA(10, 20, 30, 40)
 	tests := map[string]struct {
 		blend         gl.BlendFactors
+		blendColor    image.Color
 		expectedColor image.Color
 	}{
 		"Zero, Zero": {
@@ -1780,6 +1886,123 @@ func TestRenderer_SetBlend(t *testing.T) {
 			},
 			expectedColor: image.RGBA(2, 3, 5, 6),
 		},
+		"SrcColor, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.SrcColor,
+				DstFactor: gl.Zero,
+			},
+			expectedColor: image.RGBA(10, 14, 19, 25),
+		},
+		"OneMinusSrcColor, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.OneMinusSrcColor,
+				DstFactor: gl.Zero,
+			},
+			expectedColor: image.RGBA(40, 46, 51, 55),
+		},
+		"Zero, DstColor": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.Zero,
+				DstFactor: gl.DstColor,
+			},
+			expectedColor: image.RGBA(0, 2, 4, 6),
+		},
+		"Zero, OneMinusDstColor": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.Zero,
+				DstFactor: gl.OneMinusDstColor,
+			},
+			expectedColor: image.RGBA(10, 18, 26, 34),
+		},
+		"SrcAlphaSaturate, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.SrcAlphaSaturate,
+				DstFactor: gl.Zero,
+			},
+			expectedColor: image.RGBA(16, 19, 22, 80),
+		},
+		"ConstantColor, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.ConstantColor,
+				DstFactor: gl.Zero,
+			},
+			blendColor:    image.RGBA(100, 150, 200, 250),
+			expectedColor: image.RGBA(20, 35, 55, 78),
+		},
+		"OneMinusConstantColor, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.OneMinusConstantColor,
+				DstFactor: gl.Zero,
+			},
+			blendColor:    image.RGBA(100, 150, 200, 250),
+			expectedColor: image.RGBA(30, 25, 15, 2),
+		},
+		"ConstantAlpha, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.ConstantAlpha,
+				DstFactor: gl.Zero,
+			},
+			blendColor:    image.RGBA(100, 150, 200, 250),
+			expectedColor: image.RGBA(49, 59, 69, 78),
+		},
+		"OneMinusConstantAlpha, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.OneMinusConstantAlpha,
+				DstFactor: gl.Zero,
+			},
+			blendColor:    image.RGBA(100, 150, 200, 250),
+			expectedColor: image.RGBA(1, 1, 1, 2),
+		},
+		"One, One, FuncSubtract": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.One,
+				DstFactor: gl.One,
+				Equation:  gl.FuncSubtract,
+			},
+			expectedColor: image.RGBA(40, 40, 40, 40),
+		},
+		"One, One, FuncReverseSubtract": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.One,
+				DstFactor: gl.One,
+				Equation:  gl.FuncReverseSubtract,
+			},
+			expectedColor: image.Transparent,
+		},
+		"Min": {
+			blend: gl.BlendFactors{
+				Equation: gl.Min,
+			},
+			expectedColor: dstColor,
+		},
+		"Max": {
+			blend: gl.BlendFactors{
+				Equation: gl.Max,
+			},
+			expectedColor: srcColor,
+		},
+		"separate alpha factors": {
+			blend: gl.BlendFactors{
+				SrcFactor:      gl.One,
+				DstFactor:      gl.Zero,
+				SeparateAlpha:  true,
+				SrcAlphaFactor: gl.Zero,
+				DstAlphaFactor: gl.One,
+			},
+			expectedColor: image.RGBA(50, 60, 70, 40),
+		},
+		"separate alpha equation": {
+			blend: gl.BlendFactors{
+				SrcFactor:      gl.One,
+				DstFactor:      gl.One,
+				Equation:       gl.FuncSubtract,
+				SeparateAlpha:  true,
+				SrcAlphaFactor: gl.One,
+				DstAlphaFactor: gl.One,
+				AlphaEquation:  gl.Max,
+			},
+			expectedColor: image.RGBA(40, 40, 40, 80),
+		},
 		"SourceBlendFactors": {
 			blend:         gl.SourceBlendFactors,
 			expectedColor: srcColor,
@@ -1818,6 +2041,7 @@ func TestRenderer_SetBlend(t *testing.T) {
 			glCommand := &command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
 				// when
 				renderer.SetBlendFactors(test.blend)
+				renderer.SetBlendColor(test.blendColor)
 				renderer.DrawArrays(array, gl.Points, 0, 1)
 			}}
 			command := program.AcceleratedCommand(glCommand)
@@ -3,6 +3,7 @@
 package glblend
 
 import (
+	"github.com/jacekolszak/pixiq/blend"
 	"github.com/jacekolszak/pixiq/gl"
 	"github.com/jacekolszak/pixiq/image"
 )
@@ -10,25 +11,36 @@ import (
 // NewSource creates a new blending tool which replaces target selection with source
 // colors. It is like coping of source selection colors into target.
 func NewSource(context *gl.Context) (*Source, error) {
-	command, err := newBlendCommand(context, gl.SourceBlendFactors)
-	if err != nil {
-		return nil, err
-	}
-	return &Source{command: command}, nil
+	return newSource(context, gl.SourceBlendFactors)
 }
 
 // NewSourceOver creates a new blending tool which blends together source and target
 // taking into account alpha channel of both. Source-over means that source will be
 // painted on top of the target.
+//
+// By default source colors are used as they are. Use SetOpacity and SetTint to
+// modify them during blending.
 func NewSourceOver(context *gl.Context) (*SourceOver, error) {
-	command, err := newBlendCommand(context, gl.BlendFactors{
+	source, err := newSource(context, gl.BlendFactors{
 		SrcFactor: gl.One,
 		DstFactor: gl.OneMinusSrcAlpha,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &SourceOver{source: &Source{command: command}}, nil
+	return &SourceOver{
+		source:  source,
+		opacity: 255,
+		tint:    image.RGB(255, 255, 255),
+	}, nil
+}
+
+func newSource(context *gl.Context, passes ...gl.BlendFactors) (*Source, error) {
+	command, blendCmd, err := newBlendCommand(context, passes...)
+	if err != nil {
+		return nil, err
+	}
+	return &Source{command: command, blend: blendCmd}, nil
 }
 
 const vertexShaderSrc = `
@@ -48,40 +60,43 @@ const fragmentShaderSrc = `
 #version 330 core
 
 uniform sampler2D tex;
+uniform vec4 modulation;
 in vec2 interpolatedST;
 out vec4 color;
 
 void main() {
 	// color is blended with buffer using formula: S * sf + D * df 
-	color = texture(tex, interpolatedST);
+	color = texture(tex, interpolatedST) * modulation;
 }
 `
 
-func newBlendCommand(context *gl.Context, factors gl.BlendFactors) (*gl.AcceleratedCommand, error) {
+// newBlendCommand creates command drawing the source once for each passed
+// BlendFactors
+func newBlendCommand(context *gl.Context, passes ...gl.BlendFactors) (*gl.AcceleratedCommand, *blendCommand, error) {
 	if context == nil {
 		panic("nil context")
 	}
 	vertexShader, err := context.CompileVertexShader(vertexShaderSrc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	fragmentShader, err := context.CompileFragmentShader(fragmentShaderSrc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	program, err := context.LinkProgram(vertexShader, fragmentShader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	vertexBuffer := context.NewFloatVertexBuffer(16, gl.DynamicDraw)
 	vertexArray := makeVertexArray(context, vertexBuffer)
-	command := program.AcceleratedCommand(
-		&blendCommand{
-			vertexBuffer: vertexBuffer,
-			vertexArray:  vertexArray,
-			factors:      factors,
-		})
-	return command, nil
+	blendCmd := &blendCommand{
+		vertexBuffer: vertexBuffer,
+		vertexArray:  vertexArray,
+		passes:       passes,
+		modulation:   [4]float32{1, 1, 1, 1},
+	}
+	return program.AcceleratedCommand(blendCmd), blendCmd, nil
 }
 
 func makeVertexArray(context *gl.Context, buffer *gl.FloatVertexBuffer) *gl.VertexArray {
@@ -96,7 +111,10 @@ func makeVertexArray(context *gl.Context, buffer *gl.FloatVertexBuffer) *gl.Vert
 type blendCommand struct {
 	vertexBuffer *gl.FloatVertexBuffer
 	vertexArray  *gl.VertexArray
-	factors      gl.BlendFactors
+	passes       []gl.BlendFactors
+	// modulation is multiplied with each source color
+	modulation [4]float32
+	transform  blend.Transform
 }
 
 func (c *blendCommand) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
@@ -110,51 +128,127 @@ func (c *blendCommand) RunGL(renderer *gl.Renderer, selections []image.Accelerat
 		top         = (imageHeight - float32(source.Location.Y)) / imageHeight
 		bottom      = (imageHeight - float32(source.Location.Y) - float32(source.Location.Height)) / imageHeight
 	)
+	var (
+		topLeft     = [2]float32{left, top}
+		topRight    = [2]float32{right, top}
+		bottomRight = [2]float32{right, bottom}
+		bottomLeft  = [2]float32{left, bottom}
+	)
+	// source corners drawn in the top-left, top-right, bottom-right and bottom-left
+	// corner of the target
+	var corners [4][2]float32
+	switch c.transform {
+	case blend.FlipX:
+		corners = [4][2]float32{topRight, topLeft, bottomLeft, bottomRight}
+	case blend.FlipY:
+		corners = [4][2]float32{bottomLeft, bottomRight, topRight, topLeft}
+	case blend.Rotate90:
+		corners = [4][2]float32{bottomLeft, topLeft, topRight, bottomRight}
+	case blend.Rotate180:
+		corners = [4][2]float32{bottomRight, bottomLeft, topLeft, topRight}
+	case blend.Rotate270:
+		corners = [4][2]float32{topRight, bottomRight, bottomLeft, topLeft}
+	default:
+		corners = [4][2]float32{topLeft, topRight, bottomRight, bottomLeft}
+	}
 	// xy -> st
 	vertices := []float32{
-		-1, 1, left, top,
-		1, 1, right, top,
-		1, -1, right, bottom,
-		-1, -1, left, bottom,
+		-1, 1, corners[0][0], corners[0][1],
+		1, 1, corners[1][0], corners[1][1],
+		1, -1, corners[2][0], corners[2][1],
+		-1, -1, corners[3][0], corners[3][1],
 	}
 	c.vertexBuffer.Upload(0, vertices)
-	renderer.SetBlendFactors(c.factors)
-	renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
+	renderer.SetVec4("modulation", c.modulation[0], c.modulation[1], c.modulation[2], c.modulation[3])
+	for _, factors := range c.passes {
+		renderer.SetBlendFactors(factors)
+		renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
+	}
 }
 
 // Source is a blending tool which replaces target selection with source
 // colors. It is like coping of source selection colors into target.
 type Source struct {
 	command *gl.AcceleratedCommand
+	blend   *blendCommand
 }
 
 // BlendSourceToTarget blends source into target selection.
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
 func (s *Source) BlendSourceToTarget(source image.Selection, target image.Selection) {
-	source = clampSourceToTargetImage(source, target)
-	target = target.WithSize(source.Width(), source.Height())
+	source, target = clampSourceToTargetImage(source, target, s.blend.transform)
 	// FIXME is it fast enough? or is it better to use the whole texture as a target and update xy in the vertextbuffer accordingly?
 	target.Modify(s.command, source)
 }
 
-func clampSourceToTargetImage(source image.Selection, target image.Selection) image.Selection {
-	width := source.Width()
+// clampSourceToTargetImage returns the part of the source which will be visible
+// in the target image after transformation and the target selection of the same size.
+func clampSourceToTargetImage(source image.Selection, target image.Selection, transform blend.Transform) (image.Selection, image.Selection) {
+	sourceWidth, sourceHeight := source.Width(), source.Height()
+	width, height := transform.TargetSize(sourceWidth, sourceHeight)
 	if width+target.ImageX() > target.Image().Width() {
 		width = target.Image().Width() - target.ImageX()
 	}
-	height := source.Height()
 	if height+target.ImageY() > target.Image().Height() {
 		height = target.Image().Height() - target.ImageY()
 	}
-	return source.WithSize(width, height)
+	target = target.WithSize(width, height)
+	if width <= 0 || height <= 0 {
+		return source.WithSize(0, 0), target
+	}
+	x1, y1 := transform.SourcePosition(0, 0, sourceWidth, sourceHeight)
+	x2, y2 := transform.SourcePosition(width-1, height-1, sourceWidth, sourceHeight)
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	return source.Selection(x1, y1).WithSize(x2-x1+1, y2-y1+1), target
+}
+
+// SetTransform sets the transformation (such as flip or rotation) of the source
+// applied during blending. Default is blend.NoTransform.
+func (s *Source) SetTransform(transform blend.Transform) {
+	s.blend.transform = transform
 }
 
 // SourceOver (aka Normal) is a blending tool which blends together source and target
 // taking into account alpha channel of both. Source-over means that source will be
 // painted on top of the target.
 type SourceOver struct {
-	source *Source
+	source  *Source
+	opacity byte
+	tint    image.Color
+}
+
+// SetOpacity sets the opacity of the source, where 0 is fully transparent and
+// 255 (default) is fully opaque. Source colors (including alpha) are multiplied by
+// opacity/255 before blending.
+func (s *SourceOver) SetOpacity(opacity byte) {
+	s.opacity = opacity
+	s.updateModulation()
+}
+
+// SetTint sets the color which is multiplied with each source color before blending.
+// Default tint is opaque white, which does not change source colors. Tint is
+// a premultiplied color, therefore translucent tint makes the source translucent too.
+func (s *SourceOver) SetTint(tint image.Color) {
+	s.tint = tint
+	s.updateModulation()
+}
+
+// SetTransform sets the transformation (such as flip or rotation) of the source
+// applied during blending. Default is blend.NoTransform.
+func (s *SourceOver) SetTransform(transform blend.Transform) {
+	s.source.SetTransform(transform)
+}
+
+func (s *SourceOver) updateModulation() {
+	opacity := float32(s.opacity) / 255
+	r, g, b, a := s.tint.RGBAf()
+	s.source.blend.modulation = [4]float32{r * opacity, g * opacity, b * opacity, a * opacity}
 }
 
 // BlendSourceToTarget blends source into target selection.
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/jacekolszak/pixiq/gl"
 	"github.com/jacekolszak/pixiq/glblend"
 )
 
@@ -15,3 +16,26 @@ func TestNewSource(t *testing.T) {
 		})
 	})
 }
+
+func TestNewModes(t *testing.T) {
+	constructors := map[string]func(*gl.Context) (interface{}, error){
+		"SourceOver":      func(c *gl.Context) (interface{}, error) { return glblend.NewSourceOver(c) },
+		"DestinationOver": func(c *gl.Context) (interface{}, error) { return glblend.NewDestinationOver(c) },
+		"SourceIn":        func(c *gl.Context) (interface{}, error) { return glblend.NewSourceIn(c) },
+		"SourceOut":       func(c *gl.Context) (interface{}, error) { return glblend.NewSourceOut(c) },
+		"SourceAtop":      func(c *gl.Context) (interface{}, error) { return glblend.NewSourceAtop(c) },
+		"Xor":             func(c *gl.Context) (interface{}, error) { return glblend.NewXor(c) },
+		"Clear":           func(c *gl.Context) (interface{}, error) { return glblend.NewClear(c) },
+		"Multiply":        func(c *gl.Context) (interface{}, error) { return glblend.NewMultiply(c) },
+		"Screen":          func(c *gl.Context) (interface{}, error) { return glblend.NewScreen(c) },
+		"Add":             func(c *gl.Context) (interface{}, error) { return glblend.NewAdd(c) },
+		"Subtract":        func(c *gl.Context) (interface{}, error) { return glblend.NewSubtract(c) },
+	}
+	for name, newTool := range constructors {
+		t.Run(name+" should panic when context is nil", func(t *testing.T) {
+			assert.Panics(t, func() {
+				_, _ = newTool(nil)
+			})
+		})
+	}
+}
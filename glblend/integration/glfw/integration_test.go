@@ -5,10 +5,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/jacekolszak/pixiq/blend"
 	"github.com/jacekolszak/pixiq/glblend"
 	"github.com/jacekolszak/pixiq/glfw"
 	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
 )
 
 var mainThreadLoop *glfw.MainThreadLoop
@@ -461,6 +464,258 @@ func TestBlendSourceToTarget(t *testing.T) {
 	}
 }
 
+func TestModes(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+	tools := map[string]struct {
+		gpu func() (blender, error)
+		cpu blender
+	}{
+		"DestinationOver": {
+			gpu: func() (blender, error) { return glblend.NewDestinationOver(context) },
+			cpu: blend.NewDestinationOver(),
+		},
+		"SourceIn": {
+			gpu: func() (blender, error) { return glblend.NewSourceIn(context) },
+			cpu: blend.NewSourceIn(),
+		},
+		"SourceOut": {
+			gpu: func() (blender, error) { return glblend.NewSourceOut(context) },
+			cpu: blend.NewSourceOut(),
+		},
+		"SourceAtop": {
+			gpu: func() (blender, error) { return glblend.NewSourceAtop(context) },
+			cpu: blend.NewSourceAtop(),
+		},
+		"Xor": {
+			gpu: func() (blender, error) { return glblend.NewXor(context) },
+			cpu: blend.NewXor(),
+		},
+		"Clear": {
+			gpu: func() (blender, error) { return glblend.NewClear(context) },
+			cpu: blend.NewClear(),
+		},
+		"Multiply": {
+			gpu: func() (blender, error) { return glblend.NewMultiply(context) },
+			cpu: blend.NewMultiply(),
+		},
+		"Screen": {
+			gpu: func() (blender, error) { return glblend.NewScreen(context) },
+			cpu: blend.NewScreen(),
+		},
+		"Add": {
+			gpu: func() (blender, error) { return glblend.NewAdd(context) },
+			cpu: blend.NewAdd(),
+		},
+		"Subtract": {
+			gpu: func() (blender, error) { return glblend.NewSubtract(context) },
+			cpu: blend.NewSubtract(),
+		},
+	}
+	colors := []image.Color{
+		image.Transparent,
+		image.RGB(255, 128, 0),
+		image.RGB(128, 128, 255),
+		image.RGBA(20, 40, 60, 100),
+		image.RGBA(127, 0, 0, 127),
+		image.RGBA(200, 10, 90, 230),
+		image.RGB(255, 255, 255),
+	}
+	// each source column is blended with each target row
+	pixels := func(color func(x, y int) image.Color) [][]image.Color {
+		lines := make([][]image.Color, len(colors))
+		for y := range lines {
+			lines[y] = make([]image.Color, len(colors))
+			for x := range lines[y] {
+				lines[y][x] = color(x, y)
+			}
+		}
+		return lines
+	}
+	sourcePixels := pixels(func(x, y int) image.Color { return colors[x] })
+	targetPixels := pixels(func(x, y int) image.Color { return colors[y] })
+	for name, tool := range tools {
+		t.Run(name+" should give the same results as CPU", func(t *testing.T) {
+			gpuTool, err := tool.gpu()
+			require.NoError(t, err)
+			source := newImage(openGL, sourcePixels).WholeImageSelection()
+			target := newImage(openGL, targetPixels)
+			cpuTarget := newFakeImage(targetPixels)
+			tool.cpu.BlendSourceToTarget(newFakeImage(sourcePixels).WholeImageSelection(), cpuTarget.WholeImageSelection())
+			// when
+			gpuTool.BlendSourceToTarget(source, target.WholeImageSelection())
+			// then
+			assertColors(t, target, pixels(cpuTarget.WholeImageSelection().Color))
+		})
+	}
+}
+
+func TestSourceOver_SetOpacityAndTint(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+	tests := map[string]struct {
+		opacity byte
+		tint    image.Color
+	}{
+		"default": {
+			opacity: 255,
+			tint:    image.RGB(255, 255, 255),
+		},
+		"half transparent": {
+			opacity: 128,
+			tint:    image.RGB(255, 255, 255),
+		},
+		"transparent": {
+			opacity: 0,
+			tint:    image.RGB(255, 255, 255),
+		},
+		"red tint": {
+			opacity: 255,
+			tint:    image.RGB(255, 0, 0),
+		},
+		"translucent tint with opacity": {
+			opacity: 200,
+			tint:    image.RGBA(50, 100, 150, 200),
+		},
+	}
+	sourcePixels := [][]image.Color{{image.RGB(200, 100, 50), image.RGBA(100, 50, 0, 128), image.Transparent}}
+	targetPixels := [][]image.Color{{image.RGB(0, 0, 255), image.Transparent, image.RGBA(10, 20, 30, 40)}}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gpuTool, err := glblend.NewSourceOver(context)
+			require.NoError(t, err)
+			gpuTool.SetOpacity(test.opacity)
+			gpuTool.SetTint(test.tint)
+			cpuTool := blend.NewSourceOver()
+			cpuTool.SetOpacity(test.opacity)
+			cpuTool.SetTint(test.tint)
+			target := newImage(openGL, targetPixels).WholeImageSelection()
+			cpuTarget := newFakeImage(targetPixels).WholeImageSelection()
+			cpuTool.BlendSourceToTarget(newFakeImage(sourcePixels).WholeImageSelection(), cpuTarget)
+			// when
+			gpuTool.BlendSourceToTarget(newImage(openGL, sourcePixels).WholeImageSelection(), target)
+			// then
+			for x := 0; x < target.Width(); x++ {
+				expected := cpuTarget.Color(x, 0)
+				actual := target.Color(x, 0)
+				const delta = 1
+				assert.InDelta(t, expected.R(), actual.R(), delta, "Red at %d", x)
+				assert.InDelta(t, expected.G(), actual.G(), delta, "Green at %d", x)
+				assert.InDelta(t, expected.B(), actual.B(), delta, "Blue at %d", x)
+				assert.InDelta(t, expected.A(), actual.A(), delta, "Alpha at %d", x)
+			}
+		})
+	}
+}
+
+func TestSetTransform(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+	type transformableBlender interface {
+		blender
+		SetTransform(transform blend.Transform)
+	}
+	tools := map[string]struct {
+		gpu func() (transformableBlender, error)
+		cpu func() transformableBlender
+	}{
+		"Source": {
+			gpu: func() (transformableBlender, error) { return glblend.NewSource(context) },
+			cpu: func() transformableBlender { return blend.NewSource() },
+		},
+		"SourceOver": {
+			gpu: func() (transformableBlender, error) { return glblend.NewSourceOver(context) },
+			cpu: func() transformableBlender { return blend.NewSourceOver() },
+		},
+	}
+	transforms := map[string]blend.Transform{
+		"NoTransform": blend.NoTransform,
+		"FlipX":       blend.FlipX,
+		"FlipY":       blend.FlipY,
+		"Rotate90":    blend.Rotate90,
+		"Rotate180":   blend.Rotate180,
+		"Rotate270":   blend.Rotate270,
+	}
+	sourcePixels := [][]image.Color{
+		{image.RGB(10, 20, 30), image.RGBA(40, 50, 60, 70), image.RGB(80, 90, 100)},
+		{image.RGBA(110, 120, 130, 140), image.RGB(150, 160, 170), image.Transparent},
+	}
+	targetPixels := [][]image.Color{
+		{image.RGB(0, 0, 255), image.RGB(0, 255, 0), image.RGB(255, 0, 0), image.RGB(0, 0, 0)},
+		{image.RGB(0, 255, 0), image.RGB(255, 0, 0), image.RGB(0, 0, 0), image.RGB(0, 0, 255)},
+		{image.RGB(255, 0, 0), image.RGB(0, 0, 0), image.RGB(0, 0, 255), image.RGB(0, 255, 0)},
+		{image.RGB(0, 0, 0), image.RGB(0, 0, 255), image.RGB(0, 255, 0), image.RGB(255, 0, 0)},
+	}
+	selections := map[string]struct {
+		source func(img *image.Image) image.Selection
+		target func(img *image.Image) image.Selection
+	}{
+		"whole source": {
+			source: func(img *image.Image) image.Selection { return img.WholeImageSelection() },
+			target: func(img *image.Image) image.Selection { return img.Selection(1, 1) },
+		},
+		"source partially outside the image": {
+			source: func(img *image.Image) image.Selection { return img.Selection(-1, 1).WithSize(3, 2) },
+			target: func(img *image.Image) image.Selection { return img.Selection(0, 0) },
+		},
+		"target clipped on the right and bottom": {
+			source: func(img *image.Image) image.Selection { return img.WholeImageSelection() },
+			target: func(img *image.Image) image.Selection { return img.Selection(3, 2) },
+		},
+		"target clipped on the left and top": {
+			source: func(img *image.Image) image.Selection { return img.WholeImageSelection() },
+			target: func(img *image.Image) image.Selection { return img.Selection(-1, -1) },
+		},
+	}
+	for toolName, tool := range tools {
+		for transformName, transform := range transforms {
+			for selectionName, selection := range selections {
+				t.Run(toolName+" "+transformName+" "+selectionName, func(t *testing.T) {
+					gpuTool, err := tool.gpu()
+					require.NoError(t, err)
+					gpuTool.SetTransform(transform)
+					cpuTool := tool.cpu()
+					cpuTool.SetTransform(transform)
+					target := newImage(openGL, targetPixels)
+					cpuTarget := newFakeImage(targetPixels)
+					cpuTool.BlendSourceToTarget(
+						selection.source(newFakeImage(sourcePixels)),
+						selection.target(cpuTarget),
+					)
+					// when
+					gpuTool.BlendSourceToTarget(
+						selection.source(newImage(openGL, sourcePixels)),
+						selection.target(target),
+					)
+					// then
+					expected := make([][]image.Color, len(targetPixels))
+					for y := range expected {
+						expected[y] = make([]image.Color, len(targetPixels[y]))
+						for x := range expected[y] {
+							expected[y][x] = cpuTarget.WholeImageSelection().Color(x, y)
+						}
+					}
+					assertColors(t, target, expected)
+				})
+			}
+		}
+	}
+}
+
+func newFakeImage(pixels [][]image.Color) *image.Image {
+	img := image.New(fake.NewAcceleratedImage(len(pixels[0]), len(pixels)))
+	selection := img.WholeImageSelection()
+	for y := range pixels {
+		for x := range pixels[y] {
+			selection.SetColor(x, y, pixels[y][x])
+		}
+	}
+	return img
+}
+
 func assertColors(t *testing.T, img *image.Image, expectedColorLines [][]image.Color) {
 	selection := img.WholeImageSelection()
 	for y := 0; y < selection.Height(); y++ {
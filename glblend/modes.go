@@ -0,0 +1,285 @@
+package glblend
+
+import (
+	"github.com/jacekolszak/pixiq/gl"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Blend modes below give the same results as their counterparts in the blend
+// package. Modes which cannot be expressed using OpenGL blend factors (such as
+// Overlay, Darken, Lighten or Difference) are not available.
+
+// NewDestinationOver creates a new blending tool which paints the source
+// behind the target. Source is visible only where the target is transparent
+// or translucent.
+func NewDestinationOver(context *gl.Context) (*DestinationOver, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor: gl.OneMinusDstAlpha,
+		DstFactor: gl.One,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DestinationOver{source: source}, nil
+}
+
+// DestinationOver is a blending tool which paints the source behind the target.
+type DestinationOver struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (d *DestinationOver) BlendSourceToTarget(source, target image.Selection) {
+	d.source.BlendSourceToTarget(source, target)
+}
+
+// NewSourceIn creates a new blending tool which replaces the target with
+// the source, but only where the target is opaque. Target alpha is used as
+// a mask.
+func NewSourceIn(context *gl.Context) (*SourceIn, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor: gl.DstAlpha,
+		DstFactor: gl.Zero,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SourceIn{source: source}, nil
+}
+
+// SourceIn is a blending tool which replaces the target with the source, but
+// only where the target is opaque.
+type SourceIn struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *SourceIn) BlendSourceToTarget(source, target image.Selection) {
+	s.source.BlendSourceToTarget(source, target)
+}
+
+// NewSourceOut creates a new blending tool which replaces the target with
+// the source, but only where the target is transparent. Inverted target alpha
+// is used as a mask.
+func NewSourceOut(context *gl.Context) (*SourceOut, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor: gl.OneMinusDstAlpha,
+		DstFactor: gl.Zero,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SourceOut{source: source}, nil
+}
+
+// SourceOut is a blending tool which replaces the target with the source, but
+// only where the target is transparent.
+type SourceOut struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *SourceOut) BlendSourceToTarget(source, target image.Selection) {
+	s.source.BlendSourceToTarget(source, target)
+}
+
+// NewSourceAtop creates a new blending tool which paints the source on top of
+// the target, but only where the target is opaque. Target alpha is preserved.
+func NewSourceAtop(context *gl.Context) (*SourceAtop, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor:      gl.DstAlpha,
+		DstFactor:      gl.OneMinusSrcAlpha,
+		SeparateAlpha:  true,
+		SrcAlphaFactor: gl.Zero,
+		DstAlphaFactor: gl.One,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SourceAtop{source: source}, nil
+}
+
+// SourceAtop is a blending tool which paints the source on top of the target,
+// but only where the target is opaque.
+type SourceAtop struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *SourceAtop) BlendSourceToTarget(source, target image.Selection) {
+	s.source.BlendSourceToTarget(source, target)
+}
+
+// NewXor creates a new blending tool which leaves only those parts of source
+// and target which do not overlap.
+func NewXor(context *gl.Context) (*Xor, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor: gl.OneMinusDstAlpha,
+		DstFactor: gl.OneMinusSrcAlpha,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Xor{source: source}, nil
+}
+
+// Xor is a blending tool which leaves only those parts of source and target
+// which do not overlap.
+type Xor struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (x *Xor) BlendSourceToTarget(source, target image.Selection) {
+	x.source.BlendSourceToTarget(source, target)
+}
+
+// NewClear creates a new blending tool which makes the target transparent.
+// Source colors are ignored, only the source size is used.
+func NewClear(context *gl.Context) (*Clear, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor: gl.Zero,
+		DstFactor: gl.Zero,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Clear{source: source}, nil
+}
+
+// Clear is a blending tool which makes the target transparent.
+type Clear struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (c *Clear) BlendSourceToTarget(source, target image.Selection) {
+	c.source.BlendSourceToTarget(source, target)
+}
+
+// NewMultiply creates a new blending tool which multiplies source and target
+// colors. The result is always darker (or the same).
+func NewMultiply(context *gl.Context) (*Multiply, error) {
+	source, err := newSource(context,
+		// d*(1-sa) + s*d, alpha is not changed
+		gl.BlendFactors{
+			SrcFactor:      gl.DstColor,
+			DstFactor:      gl.OneMinusSrcAlpha,
+			SeparateAlpha:  true,
+			SrcAlphaFactor: gl.Zero,
+			DstAlphaFactor: gl.One,
+		},
+		// s*(1-da) + result of the first pass
+		gl.BlendFactors{
+			SrcFactor: gl.OneMinusDstAlpha,
+			DstFactor: gl.One,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Multiply{source: source}, nil
+}
+
+// Multiply is a blending tool which multiplies source and target colors.
+type Multiply struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (m *Multiply) BlendSourceToTarget(source, target image.Selection) {
+	m.source.BlendSourceToTarget(source, target)
+}
+
+// NewScreen creates a new blending tool which multiplies inverted source and
+// target colors. The result is always lighter (or the same).
+func NewScreen(context *gl.Context) (*Screen, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor: gl.One,
+		DstFactor: gl.OneMinusSrcColor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Screen{source: source}, nil
+}
+
+// Screen is a blending tool which multiplies inverted source and target colors.
+type Screen struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *Screen) BlendSourceToTarget(source, target image.Selection) {
+	s.source.BlendSourceToTarget(source, target)
+}
+
+// NewAdd (aka Additive or Linear Dodge) creates a new blending tool which adds
+// source and target colors. Components are saturated at 255.
+func NewAdd(context *gl.Context) (*Add, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor: gl.One,
+		DstFactor: gl.One,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Add{source: source}, nil
+}
+
+// Add is a blending tool which adds source and target colors.
+type Add struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (a *Add) BlendSourceToTarget(source, target image.Selection) {
+	a.source.BlendSourceToTarget(source, target)
+}
+
+// NewSubtract creates a new blending tool which subtracts source colors from
+// target colors. Components are saturated at 0. Target alpha is preserved.
+func NewSubtract(context *gl.Context) (*Subtract, error) {
+	source, err := newSource(context, gl.BlendFactors{
+		SrcFactor:      gl.One,
+		DstFactor:      gl.One,
+		Equation:       gl.FuncReverseSubtract,
+		SeparateAlpha:  true,
+		SrcAlphaFactor: gl.Zero,
+		DstAlphaFactor: gl.One,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Subtract{source: source}, nil
+}
+
+// Subtract is a blending tool which subtracts source colors from target colors.
+type Subtract struct {
+	source *Source
+}
+
+// BlendSourceToTarget blends source into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *Subtract) BlendSourceToTarget(source, target image.Selection) {
+	s.source.BlendSourceToTarget(source, target)
+}
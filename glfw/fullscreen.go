@@ -0,0 +1,156 @@
+package glfw
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+
+	"github.com/jacekolszak/pixiq/glfw/internal"
+)
+
+// WindowMode defines whether the window is displayed in a window or
+// in fullscreen.
+type WindowMode int
+
+const (
+	// WindowedMode is a default mode of the window
+	WindowedMode WindowMode = iota
+	// FullscreenMode uses the whole monitor exclusively and may change its
+	// video mode
+	FullscreenMode
+	// BorderlessFullscreenMode uses a window without decorations covering
+	// the whole monitor. Video mode of the monitor is not changed.
+	BorderlessFullscreenMode
+)
+
+// Fullscreen opens the window in fullscreen mode on a given monitor using
+// the video mode. Use the Scaling option to define how the screen is
+// displayed when the video mode size is different than the screen size
+// multiplied by zoom.
+//
+// Will panic if monitor is nil.
+func Fullscreen(monitor *Monitor, mode VideoMode) WindowOption {
+	if monitor == nil {
+		panic("nil monitor")
+	}
+	return func(win *Window) {
+		win.mode = FullscreenMode
+		win.monitor = monitor
+		win.videoMode = mode
+	}
+}
+
+// BorderlessFullscreen opens the window without decorations covering the whole
+// monitor. Use the Scaling option to define how the screen is displayed when
+// the monitor size is different than the screen size multiplied by zoom.
+//
+// Will panic if monitor is nil.
+func BorderlessFullscreen(monitor *Monitor) WindowOption {
+	if monitor == nil {
+		panic("nil monitor")
+	}
+	return func(win *Window) {
+		win.mode = BorderlessFullscreenMode
+		win.monitor = monitor
+		win.videoMode = monitor.VideoMode()
+	}
+}
+
+// AutoZoom sets the zoom to the largest integer, so that the screen fits
+// the monitor. The monitor given in Fullscreen or BorderlessFullscreen option
+// is used. When the window is not opened in fullscreen the work area of the
+// primary monitor is used. AutoZoom overrides the Zoom option.
+func AutoZoom() WindowOption {
+	return func(win *Window) {
+		win.autoZoom = true
+	}
+}
+
+// calculateAutoZoom must be executed in the main thread
+func (w *Window) calculateAutoZoom() int {
+	var areaWidth, areaHeight int
+	switch {
+	case w.mode != WindowedMode:
+		areaWidth, areaHeight = w.videoMode.Width, w.videoMode.Height
+	default:
+		primary := glfw.GetPrimaryMonitor()
+		if primary == nil {
+			return w.zoom
+		}
+		_, _, areaWidth, areaHeight = primary.GetWorkarea()
+	}
+	return internal.IntegerScale(areaWidth, areaHeight, w.requestedWidth, w.requestedHeight)
+}
+
+// Mode returns the current mode of the window
+func (w *Window) Mode() WindowMode {
+	var mode WindowMode
+	// mode is modified in the main thread only
+	w.mainThreadLoop.Execute(func() {
+		mode = w.mode
+	})
+	return mode
+}
+
+// SetFullscreen switches the window to fullscreen mode on a given monitor
+// using the video mode.
+//
+// Will panic if monitor is nil.
+func (w *Window) SetFullscreen(monitor *Monitor, mode VideoMode) {
+	if monitor == nil {
+		panic("nil monitor")
+	}
+	w.mainThreadLoop.Execute(func() {
+		w.switchMode(FullscreenMode, monitor, mode)
+	})
+}
+
+// SetBorderlessFullscreen switches the window to borderless fullscreen mode
+// covering a given monitor.
+//
+// Will panic if monitor is nil.
+func (w *Window) SetBorderlessFullscreen(monitor *Monitor) {
+	if monitor == nil {
+		panic("nil monitor")
+	}
+	w.mainThreadLoop.Execute(func() {
+		w.switchMode(BorderlessFullscreenMode, monitor, monitor.VideoMode())
+	})
+}
+
+// SetWindowed switches the window back to windowed mode. Position, size and
+// decorations from before entering the fullscreen are restored.
+func (w *Window) SetWindowed() {
+	w.mainThreadLoop.Execute(func() {
+		w.switchMode(WindowedMode, nil, VideoMode{})
+	})
+}
+
+// switchMode must be executed in the main thread
+func (w *Window) switchMode(mode WindowMode, monitor *Monitor, videoMode VideoMode) {
+	if w.mode == WindowedMode && mode != WindowedMode {
+		w.windowed.x, w.windowed.y = w.glfwWindow.GetPos()
+		w.windowed.width, w.windowed.height = w.glfwWindow.GetSize()
+		w.windowed.decorated = w.glfwWindow.GetAttrib(glfw.Decorated)
+	}
+	switch mode {
+	case FullscreenMode:
+		w.glfwWindow.SetMonitor(monitor.glfwMonitor, 0, 0, videoMode.Width, videoMode.Height, videoMode.RefreshRate)
+	case BorderlessFullscreenMode:
+		x, y := monitor.Position()
+		w.glfwWindow.SetAttrib(glfw.Decorated, glfw.False)
+		w.glfwWindow.SetMonitor(nil, x, y, videoMode.Width, videoMode.Height, 0)
+	default:
+		w.glfwWindow.SetAttrib(glfw.Decorated, w.windowed.decorated)
+		w.glfwWindow.SetMonitor(nil, w.windowed.x, w.windowed.y, w.windowed.width, w.windowed.height, 0)
+	}
+	w.mode = mode
+	w.monitor = monitor
+	w.videoMode = videoMode
+}
+
+// windowedState is the state of the window in windowed mode, which is
+// restored after leaving the fullscreen
+type windowedState struct {
+	x, y          int
+	width, height int
+	decorated     int
+}
@@ -6,12 +6,14 @@
 package glfw
 
 import (
+	"sync"
 	"time"
 
 	gl33 "github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 
 	"github.com/jacekolszak/pixiq/gl"
+	"github.com/jacekolszak/pixiq/glfw/internal"
 	"github.com/jacekolszak/pixiq/goimage"
 	"github.com/jacekolszak/pixiq/image"
 )
@@ -169,8 +171,12 @@ func (g *OpenGL) NewImage(width, height int) *image.Image {
 
 // mouseWindow implements mouse.Window
 type mouseWindow struct {
-	glfwWindow *glfw.Window
-	zoom       int
+	glfwWindow    *glfw.Window
+	zoom          int
+	scalingPolicy ScalingPolicy
+	mutex         sync.Mutex
+	screenWidth   int
+	screenHeight  int
 }
 
 func (m *mouseWindow) CursorPosition() (float64, float64) {
@@ -182,8 +188,31 @@ func (m *mouseWindow) Size() (int, int) {
 	return m.glfwWindow.GetSize()
 }
 
-func (m *mouseWindow) Zoom() int {
-	return m.zoom
+// FramebufferSize() is thread-safe
+func (m *mouseWindow) FramebufferSize() (int, int) {
+	return m.glfwWindow.GetFramebufferSize()
+}
+
+// ScreenArea returns the area in framebuffer coordinates, the same as used
+// for drawing the screen
+func (m *mouseWindow) ScreenArea() internal.Area {
+	width, height := m.FramebufferSize()
+	screenWidth, screenHeight := m.ScreenSize()
+	return screenArea(m.scalingPolicy, width, height, screenWidth, screenHeight, m.zoom)
+}
+
+// ScreenSize() is thread-safe
+func (m *mouseWindow) ScreenSize() (int, int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.screenWidth, m.screenHeight
+}
+
+func (m *mouseWindow) setScreenSize(width, height int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.screenWidth = width
+	m.screenHeight = height
 }
 
 // OpenWindow creates and shows Window.
@@ -255,6 +284,62 @@ func Zoom(zoom int) WindowOption {
 	}
 }
 
+// Resizable makes the window resizable (and maximizable) by the user. Policy
+// defines how the screen is displayed when the size of the window changes.
+func Resizable(policy ScalingPolicy) WindowOption {
+	return func(win *Window) {
+		win.scalingPolicy = policy
+		win.glfwWindow.SetAttrib(glfw.Resizable, glfw.True)
+	}
+}
+
+// SwapInterval sets the number of screen updates to wait before swapping
+// the buffers (VSync). 0 disables VSync, 1 synchronizes with every screen
+// refresh. Negative value enables adaptive VSync when supported by the
+// platform. By default the platform setting is used.
+func SwapInterval(interval int) WindowOption {
+	return func(win *Window) {
+		win.swapInterval = interval
+		win.swapIntervalSet = true
+	}
+}
+
+// Scaling sets the policy defining how the screen is displayed when the size
+// of the window is different than the size of the screen multiplied by zoom,
+// for example in fullscreen mode. By default Stretch is used.
+func Scaling(policy ScalingPolicy) WindowOption {
+	return func(win *Window) {
+		win.scalingPolicy = policy
+	}
+}
+
+// Maximized maximizes the window after opening. It is usually used together
+// with Resizable option.
+func Maximized() WindowOption {
+	return func(win *Window) {
+		win.maximized = true
+	}
+}
+
+// BorderColor sets the color of the window area not covered by the screen.
+// By default it is transparent.
+func BorderColor(color image.Color) WindowOption {
+	return func(win *Window) {
+		win.drawer.borderColor = color
+	}
+}
+
+// OnResize sets the callback executed when the window was resized. Callback is
+// executed by SwapBuffers (or Draw) in the calling goroutine, after the screen
+// was adjusted to the new window size. With GrowScreen scaling policy the
+// selection returned by Window.Screen before the resize is no longer valid and
+// must be fetched again.
+func OnResize(callback func(window *Window)) WindowOption {
+	return func(win *Window) {
+		win.onResize = callback
+	}
+}
+
 // NewCursor creates a new custom cursor look that can be set for a Window with SetCursor.
 // The look is taken from a Selection. The size of the cursor is based on the Selection size
 // and zoom.
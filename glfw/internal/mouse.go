@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"math"
+
 	"github.com/go-gl/glfw/v3.3/glfw"
 
 	"github.com/jacekolszak/pixiq/mouse"
@@ -56,12 +58,21 @@ func (e *MouseEvents) OnScrollCallback(_ *glfw.Window, xoff float64, yoff float6
 	e.buffer.Add(mouse.NewScrolledEvent(-xoff, -yoff))
 }
 
-// Window is an abstraction for getting information about cursor position, size and zoom.
-// It is needed for generating mouse move events
+// Window is an abstraction for getting information about cursor position, size
+// and the area where the screen is drawn. It is needed for generating mouse move
+// events
 type Window interface {
+	// CursorPosition returns the position in window coordinates
 	CursorPosition() (float64, float64)
+	// Size returns the size in window coordinates
 	Size() (int, int)
-	Zoom() int
+	// FramebufferSize returns the size in pixels, which may be different than
+	// Size on HiDPI displays
+	FramebufferSize() (int, int)
+	// ScreenArea returns the area of the framebuffer where the screen is drawn
+	ScreenArea() Area
+	// ScreenSize returns the size of the screen image
+	ScreenSize() (int, int)
 }
 
 // Poll return next mapped event
@@ -75,14 +86,36 @@ func (e *MouseEvents) Poll() (mouse.Event, bool) {
 	realX, realY := e.window.CursorPosition()
 	if e.lastPosX != realX || e.lastPosY != realY {
 		w, h := e.window.Size()
-		zoom := float64(e.window.Zoom())
 		insideWindow := true
 		if int(realX) >= w || int(realY) >= h || realX < 0 || realY < 0 {
 			insideWindow = false
 		}
 		e.lastPosX = realX
 		e.lastPosY = realY
-		return mouse.NewMovedEvent(int(realX/zoom), int(realY/zoom), realX, realY, insideWindow), true
+		x, y := e.screenPosition(realX, realY)
+		return mouse.NewMovedEvent(x, y, realX, realY, insideWindow), true
 	}
 	return mouse.EmptyEvent, false
 }
+
+// screenPosition maps the cursor position in the window to the position of the
+// screen pixel. Cursor position is converted to framebuffer coordinates first,
+// because the screen area is given in framebuffer coordinates.
+func (e *MouseEvents) screenPosition(realX, realY float64) (int, int) {
+	area := e.window.ScreenArea()
+	if area.Width <= 0 || area.Height <= 0 {
+		return 0, 0
+	}
+	width, height := e.window.Size()
+	framebufferWidth, framebufferHeight := e.window.FramebufferSize()
+	if width > 0 && height > 0 {
+		realX = realX * float64(framebufferWidth) / float64(width)
+		realY = realY * float64(framebufferHeight) / float64(height)
+	}
+	screenWidth, screenHeight := e.window.ScreenSize()
+	x := (realX - float64(area.X)) * float64(screenWidth) / float64(area.Width)
+	y := (realY - float64(area.Y)) * float64(screenHeight) / float64(area.Height)
+	// floor instead of truncating toward zero, so the position left of or above
+	// the screen area is negative
+	return int(math.Floor(x)), int(math.Floor(y))
+}
@@ -212,6 +212,80 @@ func TestMouseEvents_Poll(t *testing.T) {
 				},
 				expectedEvent: mouse.NewMovedEvent(0, -1, 0, -1, false),
 			},
+			"stretched screen": {
+				window: &fakeWindow{
+					posX:         3,
+					posY:         5,
+					width:        4,
+					height:       6,
+					screenArea:   internal.Area{Width: 4, Height: 6},
+					screenWidth:  2,
+					screenHeight: 2,
+				},
+				expectedEvent: mouse.NewMovedEvent(1, 1, 3, 5, true),
+			},
+			"letterboxed screen": {
+				window: &fakeWindow{
+					posX:         5,
+					posY:         4,
+					width:        10,
+					height:       6,
+					screenArea:   internal.Area{X: 3, Width: 4, Height: 6},
+					screenWidth:  2,
+					screenHeight: 3,
+				},
+				expectedEvent: mouse.NewMovedEvent(1, 2, 5, 4, true),
+			},
+			"border of letterboxed screen": {
+				window: &fakeWindow{
+					posX:         1,
+					posY:         1,
+					width:        10,
+					height:       6,
+					screenArea:   internal.Area{X: 3, Width: 4, Height: 6},
+					screenWidth:  2,
+					screenHeight: 3,
+				},
+				expectedEvent: mouse.NewMovedEvent(-1, 0, 1, 1, true),
+			},
+			"sub-pixel border of letterboxed screen": {
+				window: &fakeWindow{
+					posX:         2,
+					posY:         1,
+					width:        10,
+					height:       6,
+					screenArea:   internal.Area{X: 3, Width: 4, Height: 6},
+					screenWidth:  2,
+					screenHeight: 3,
+				},
+				expectedEvent: mouse.NewMovedEvent(-1, 0, 2, 1, true),
+			},
+			"sub-pixel above letterboxed screen": {
+				window: &fakeWindow{
+					posX:         1,
+					posY:         2,
+					width:        6,
+					height:       10,
+					screenArea:   internal.Area{Y: 3, Width: 6, Height: 4},
+					screenWidth:  3,
+					screenHeight: 2,
+				},
+				expectedEvent: mouse.NewMovedEvent(0, -1, 1, 2, true),
+			},
+			"HiDPI framebuffer": {
+				window: &fakeWindow{
+					posX:              1.5,
+					posY:              1,
+					width:             4,
+					height:            3,
+					framebufferWidth:  8,
+					framebufferHeight: 6,
+					screenArea:        internal.Area{Width: 8, Height: 6},
+					screenWidth:       4,
+					screenHeight:      3,
+				},
+				expectedEvent: mouse.NewMovedEvent(1, 1, 1.5, 1, true),
+			},
 		}
 		for name, test := range tests {
 			t.Run(name, func(t *testing.T) {
@@ -293,6 +367,12 @@ type fakeWindow struct {
 	posX, posY    float64
 	width, height int
 	zoom          int
+	// framebuffer size is the same as window size when not set
+	framebufferWidth, framebufferHeight int
+	// screenArea, screenWidth and screenHeight are calculated using
+	// window size and zoom when not set
+	screenArea                internal.Area
+	screenWidth, screenHeight int
 }
 
 func (f *fakeWindow) CursorPosition() (float64, float64) {
@@ -303,6 +383,31 @@ func (f *fakeWindow) Size() (int, int) {
 	return f.width, f.height
 }
 
-func (f *fakeWindow) Zoom() int {
+func (f *fakeWindow) FramebufferSize() (int, int) {
+	if f.framebufferWidth != 0 || f.framebufferHeight != 0 {
+		return f.framebufferWidth, f.framebufferHeight
+	}
+	return f.width, f.height
+}
+
+func (f *fakeWindow) ScreenArea() internal.Area {
+	if f.screenArea != (internal.Area{}) {
+		return f.screenArea
+	}
+	width, height := f.ScreenSize()
+	return internal.Area{Width: width * f.zoomOrOne(), Height: height * f.zoomOrOne()}
+}
+
+func (f *fakeWindow) ScreenSize() (int, int) {
+	if f.screenWidth != 0 || f.screenHeight != 0 {
+		return f.screenWidth, f.screenHeight
+	}
+	return f.width / f.zoomOrOne(), f.height / f.zoomOrOne()
+}
+
+func (f *fakeWindow) zoomOrOne() int {
+	if f.zoom < 1 {
+		return 1
+	}
 	return f.zoom
 }
@@ -0,0 +1,53 @@
+package internal
+
+// Area is a rectangle in the window. Coordinates are in pixels, y grows
+// downwards.
+type Area struct {
+	X, Y, Width, Height int
+}
+
+// IntegerScaledScreen returns the area where the screen should be drawn, so
+// that it is scaled by the biggest integer factor fitting inside the window.
+// The area is centered. Scale is at least 1, even if the window is smaller
+// than the screen.
+func IntegerScaledScreen(windowWidth, windowHeight, screenWidth, screenHeight int) Area {
+	scale := IntegerScale(windowWidth, windowHeight, screenWidth, screenHeight)
+	width := screenWidth * scale
+	height := screenHeight * scale
+	return Area{
+		X:      (windowWidth - width) / 2,
+		Y:      (windowHeight - height) / 2,
+		Width:  width,
+		Height: height,
+	}
+}
+
+// IntegerScale returns the biggest integer factor by which the screen can be
+// scaled to fit inside the area. Returned scale is at least 1.
+func IntegerScale(areaWidth, areaHeight, screenWidth, screenHeight int) int {
+	if screenWidth < 1 || screenHeight < 1 {
+		return 1
+	}
+	scale := areaWidth / screenWidth
+	if verticalScale := areaHeight / screenHeight; verticalScale < scale {
+		scale = verticalScale
+	}
+	if scale < 1 {
+		return 1
+	}
+	return scale
+}
+
+// GrownScreenSize returns the size of the screen covering the whole window
+// when each screen pixel is zoom times bigger. Size is at least 1x1.
+func GrownScreenSize(windowWidth, windowHeight, zoom int) (width, height int) {
+	width = windowWidth / zoom
+	height = windowHeight / zoom
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	return
+}
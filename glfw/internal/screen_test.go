@@ -0,0 +1,133 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/glfw/internal"
+)
+
+func TestIntegerScaledScreen(t *testing.T) {
+	tests := map[string]struct {
+		windowWidth, windowHeight int
+		screenWidth, screenHeight int
+		expected                  internal.Area
+	}{
+		"same size": {
+			windowWidth: 2, windowHeight: 3,
+			screenWidth: 2, screenHeight: 3,
+			expected: internal.Area{Width: 2, Height: 3},
+		},
+		"window twice as big": {
+			windowWidth: 4, windowHeight: 6,
+			screenWidth: 2, screenHeight: 3,
+			expected: internal.Area{Width: 4, Height: 6},
+		},
+		"wider window": {
+			windowWidth: 10, windowHeight: 6,
+			screenWidth: 2, screenHeight: 3,
+			expected: internal.Area{X: 3, Width: 4, Height: 6},
+		},
+		"higher window": {
+			windowWidth: 4, windowHeight: 10,
+			screenWidth: 2, screenHeight: 3,
+			expected: internal.Area{Y: 2, Width: 4, Height: 6},
+		},
+		"not integer scale": {
+			windowWidth: 5, windowHeight: 7,
+			screenWidth: 2, screenHeight: 3,
+			expected: internal.Area{X: 0, Y: 0, Width: 4, Height: 6},
+		},
+		"window smaller than screen": {
+			windowWidth: 1, windowHeight: 1,
+			screenWidth: 2, screenHeight: 3,
+			expected: internal.Area{X: 0, Y: -1, Width: 2, Height: 3},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			area := internal.IntegerScaledScreen(test.windowWidth, test.windowHeight, test.screenWidth, test.screenHeight)
+			// then
+			assert.Equal(t, test.expected, area)
+		})
+	}
+}
+
+func TestIntegerScale(t *testing.T) {
+	tests := map[string]struct {
+		areaWidth, areaHeight     int
+		screenWidth, screenHeight int
+		expected                  int
+	}{
+		"same size": {
+			areaWidth: 2, areaHeight: 3,
+			screenWidth: 2, screenHeight: 3,
+			expected: 1,
+		},
+		"limited by width": {
+			areaWidth: 1920, areaHeight: 1080,
+			screenWidth: 320, screenHeight: 100,
+			expected: 6,
+		},
+		"limited by height": {
+			areaWidth: 1920, areaHeight: 1080,
+			screenWidth: 200, screenHeight: 180,
+			expected: 6,
+		},
+		"not integer scale": {
+			areaWidth: 1920, areaHeight: 1080,
+			screenWidth: 300, screenHeight: 200,
+			expected: 5,
+		},
+		"area smaller than screen": {
+			areaWidth: 1, areaHeight: 1,
+			screenWidth: 2, screenHeight: 2,
+			expected: 1,
+		},
+		"empty screen": {
+			areaWidth: 1, areaHeight: 1,
+			screenWidth: 0, screenHeight: 0,
+			expected: 1,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			scale := internal.IntegerScale(test.areaWidth, test.areaHeight, test.screenWidth, test.screenHeight)
+			// then
+			assert.Equal(t, test.expected, scale)
+		})
+	}
+}
+
+func TestGrownScreenSize(t *testing.T) {
+	tests := map[string]struct {
+		windowWidth, windowHeight     int
+		zoom                          int
+		expectedWidth, expectedHeight int
+	}{
+		"zoom 1": {
+			windowWidth: 2, windowHeight: 3, zoom: 1,
+			expectedWidth: 2, expectedHeight: 3,
+		},
+		"zoom 2": {
+			windowWidth: 5, windowHeight: 7, zoom: 2,
+			expectedWidth: 2, expectedHeight: 3,
+		},
+		"window smaller than zoom": {
+			windowWidth: 1, windowHeight: 1, zoom: 2,
+			expectedWidth: 1, expectedHeight: 1,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			width, height := internal.GrownScreenSize(test.windowWidth, test.windowHeight, test.zoom)
+			// then
+			assert.Equal(t, test.expectedWidth, width)
+			assert.Equal(t, test.expectedHeight, height)
+		})
+	}
+}
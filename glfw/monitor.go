@@ -0,0 +1,117 @@
+package glfw
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Monitor is a snapshot of information about the monitor connected to the
+// computer. It can be used for opening fullscreen windows.
+type Monitor struct {
+	glfwMonitor *glfw.Monitor
+	name        string
+	x, y        int
+	scaleX      float32
+	scaleY      float32
+	videoMode   VideoMode
+	videoModes  []VideoMode
+	workarea    [4]int
+}
+
+// VideoMode is a video mode of the monitor
+type VideoMode struct {
+	Width       int
+	Height      int
+	RefreshRate int
+}
+
+func newMonitor(glfwMonitor *glfw.Monitor) *Monitor {
+	x, y := glfwMonitor.GetPos()
+	scaleX, scaleY := glfwMonitor.GetContentScale()
+	workareaX, workareaY, workareaWidth, workareaHeight := glfwMonitor.GetWorkarea()
+	var videoModes []VideoMode
+	for _, mode := range glfwMonitor.GetVideoModes() {
+		videoModes = append(videoModes, newVideoMode(mode))
+	}
+	return &Monitor{
+		glfwMonitor: glfwMonitor,
+		name:        glfwMonitor.GetName(),
+		x:           x,
+		y:           y,
+		scaleX:      scaleX,
+		scaleY:      scaleY,
+		videoMode:   newVideoMode(glfwMonitor.GetVideoMode()),
+		videoModes:  videoModes,
+		workarea:    [4]int{workareaX, workareaY, workareaWidth, workareaHeight},
+	}
+}
+
+func newVideoMode(mode *glfw.VidMode) VideoMode {
+	if mode == nil {
+		return VideoMode{}
+	}
+	return VideoMode{
+		Width:       mode.Width,
+		Height:      mode.Height,
+		RefreshRate: mode.RefreshRate,
+	}
+}
+
+// Name returns a human-readable name of the monitor. Name is not guaranteed
+// to be unique.
+func (m *Monitor) Name() string {
+	return m.name
+}
+
+// Position returns the position of the monitor's viewport on the virtual
+// screen, in screen coordinates.
+func (m *Monitor) Position() (x, y int) {
+	return m.x, m.y
+}
+
+// ContentScale returns the ratio between the current DPI and the platform's
+// default DPI.
+func (m *Monitor) ContentScale() (x, y float32) {
+	return m.scaleX, m.scaleY
+}
+
+// Workarea returns the area of the monitor not occupied by global task bars or
+// menu bars, in screen coordinates.
+func (m *Monitor) Workarea() (x, y, width, height int) {
+	return m.workarea[0], m.workarea[1], m.workarea[2], m.workarea[3]
+}
+
+// VideoMode returns the current video mode of the monitor
+func (m *Monitor) VideoMode() VideoMode {
+	return m.videoMode
+}
+
+// VideoModes returns all video modes supported by the monitor. Modes are
+// sorted in ascending order.
+func (m *Monitor) VideoModes() []VideoMode {
+	modes := make([]VideoMode, len(m.videoModes))
+	copy(modes, m.videoModes)
+	return modes
+}
+
+// Monitors returns currently connected monitors. The primary monitor is
+// always first.
+func (g *OpenGL) Monitors() []*Monitor {
+	var monitors []*Monitor
+	g.mainThreadLoop.Execute(func() {
+		for _, glfwMonitor := range glfw.GetMonitors() {
+			monitors = append(monitors, newMonitor(glfwMonitor))
+		}
+	})
+	return monitors
+}
+
+// PrimaryMonitor returns the primary monitor or nil if no monitor was found.
+func (g *OpenGL) PrimaryMonitor() *Monitor {
+	var monitor *Monitor
+	g.mainThreadLoop.Execute(func() {
+		if glfwMonitor := glfw.GetPrimaryMonitor(); glfwMonitor != nil {
+			monitor = newMonitor(glfwMonitor)
+		}
+	})
+	return monitor
+}
@@ -0,0 +1,146 @@
+package glfw_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/glfw"
+)
+
+func TestOpenGL_Monitors(t *testing.T) {
+	t.Run("should return monitors", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		// when
+		monitors := openGL.Monitors()
+		// then
+		require.NotEmpty(t, monitors)
+		for _, monitor := range monitors {
+			assert.NotEmpty(t, monitor.VideoModes())
+			mode := monitor.VideoMode()
+			assert.True(t, mode.Width > 0)
+			assert.True(t, mode.Height > 0)
+		}
+	})
+}
+
+func TestOpenGL_PrimaryMonitor(t *testing.T) {
+	t.Run("should return primary monitor", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		// when
+		monitor := openGL.PrimaryMonitor()
+		// then
+		require.NotNil(t, monitor)
+		assert.Equal(t, openGL.Monitors()[0].Name(), monitor.Name())
+	})
+}
+
+func TestFullscreen(t *testing.T) {
+	t.Run("should panic when monitor is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			glfw.Fullscreen(nil, glfw.VideoMode{})
+		})
+	})
+	t.Run("should open fullscreen window", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		monitor := openGL.PrimaryMonitor()
+		require.NotNil(t, monitor)
+		// when
+		window, err := openGL.OpenWindow(1, 1, glfw.Fullscreen(monitor, monitor.VideoMode()))
+		// then
+		require.NoError(t, err)
+		defer window.Close()
+		assert.Equal(t, glfw.FullscreenMode, window.Mode())
+	})
+}
+
+func TestBorderlessFullscreen(t *testing.T) {
+	t.Run("should panic when monitor is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			glfw.BorderlessFullscreen(nil)
+		})
+	})
+	t.Run("should open borderless fullscreen window", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		monitor := openGL.PrimaryMonitor()
+		require.NotNil(t, monitor)
+		// when
+		window, err := openGL.OpenWindow(1, 1, glfw.BorderlessFullscreen(monitor))
+		// then
+		require.NoError(t, err)
+		defer window.Close()
+		assert.Equal(t, glfw.BorderlessFullscreenMode, window.Mode())
+	})
+}
+
+func TestAutoZoom(t *testing.T) {
+	t.Run("should use the largest zoom fitting the monitor", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		monitor := openGL.PrimaryMonitor()
+		require.NotNil(t, monitor)
+		mode := monitor.VideoMode()
+		// when
+		window, err := openGL.OpenWindow(mode.Width/3, mode.Height/3, glfw.BorderlessFullscreen(monitor), glfw.AutoZoom())
+		// then
+		require.NoError(t, err)
+		defer window.Close()
+		assert.Equal(t, 3, window.Zoom())
+	})
+}
+
+func TestWindow_SetFullscreen(t *testing.T) {
+	t.Run("should panic when monitor is nil", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		window, err := openGL.OpenWindow(1, 1)
+		require.NoError(t, err)
+		defer window.Close()
+		assert.Panics(t, func() {
+			window.SetFullscreen(nil, glfw.VideoMode{})
+		})
+	})
+	t.Run("should switch to fullscreen and back", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		window, err := openGL.OpenWindow(1, 1)
+		require.NoError(t, err)
+		defer window.Close()
+		monitor := openGL.PrimaryMonitor()
+		require.NotNil(t, monitor)
+		window.SetFullscreen(monitor, monitor.VideoMode())
+		// when
+		window.SetWindowed()
+		// then
+		assert.Equal(t, glfw.WindowedMode, window.Mode())
+	})
+}
+
+func TestWindow_SetBorderlessFullscreen(t *testing.T) {
+	t.Run("should switch to borderless fullscreen", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		window, err := openGL.OpenWindow(1, 1)
+		require.NoError(t, err)
+		defer window.Close()
+		monitor := openGL.PrimaryMonitor()
+		require.NotNil(t, monitor)
+		// when
+		window.SetBorderlessFullscreen(monitor)
+		// then
+		assert.Equal(t, glfw.BorderlessFullscreenMode, window.Mode())
+	})
+}
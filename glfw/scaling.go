@@ -0,0 +1,35 @@
+package glfw
+
+import "github.com/jacekolszak/pixiq/glfw/internal"
+
+// ScalingPolicy defines how the screen is displayed when the size of a
+// resizable window changes.
+type ScalingPolicy int
+
+const (
+	// Stretch stretches the screen to the whole window. Pixels may have
+	// different sizes and the aspect ratio is not preserved.
+	Stretch ScalingPolicy = iota
+	// IntegerScaling scales the screen by the biggest integer factor which
+	// makes it fit inside the window (pixel-perfect). The screen is centered
+	// and the rest of the window is filled with the border color.
+	IntegerScaling
+	// GrowScreen changes the size of the screen, so that it covers the whole
+	// window (taking zoom into account). The screen image is recreated each
+	// time the window is resized, therefore Window.Screen must be called
+	// again to get the new one. Pixels drawn so far are copied to the new
+	// screen. The rest of the window is filled with the border color.
+	GrowScreen
+)
+
+// screenArea returns the area of the window where the screen is drawn
+func screenArea(policy ScalingPolicy, windowWidth, windowHeight, screenWidth, screenHeight, zoom int) internal.Area {
+	switch policy {
+	case IntegerScaling:
+		return internal.IntegerScaledScreen(windowWidth, windowHeight, screenWidth, screenHeight)
+	case GrowScreen:
+		return internal.Area{Width: screenWidth * zoom, Height: screenHeight * zoom}
+	default:
+		return internal.Area{Width: windowWidth, Height: windowHeight}
+	}
+}
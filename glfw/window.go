@@ -2,9 +2,11 @@ package glfw
 
 import (
 	"log"
+	"sync"
 
 	gl33 "github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/jacekolszak/pixiq/blend"
 	"github.com/jacekolszak/pixiq/gl"
 	"github.com/jacekolszak/pixiq/glfw/internal"
 	"github.com/jacekolszak/pixiq/image"
@@ -26,6 +28,21 @@ type Window struct {
 	onClose         func(*Window)
 	closed          bool
 	drawer          windowDrawer
+	maximized       bool
+	scalingPolicy   ScalingPolicy
+	onResize        func(*Window)
+	resizeMutex     sync.Mutex
+	resizePending   bool
+	// framebuffer size used when the resize was applied last time
+	framebufferWidth  int
+	framebufferHeight int
+	mode              WindowMode
+	monitor           *Monitor
+	videoMode         VideoMode
+	windowed          windowedState
+	autoZoom          bool
+	swapInterval      int
+	swapIntervalSet   bool
 }
 
 type windowDrawer struct {
@@ -37,6 +54,9 @@ type windowDrawer struct {
 	sharedContext   *gl.Context // API for main context shared between all windows
 	context         *gl.Context
 	program         *gl.Program
+	scalingPolicy   ScalingPolicy
+	zoom            int
+	borderColor     image.Color
 }
 
 func newWindow(glfwWindow *glfw.Window, mainThreadLoop *MainThreadLoop, width, height int, context, sharedContext *gl.Context, onClose func(*Window), options []WindowOption) (*Window, error) {
@@ -63,9 +83,17 @@ func newWindow(glfwWindow *glfw.Window, mainThreadLoop *MainThreadLoop, width, h
 	var sizeIsSet <-chan bool
 	mainThreadLoop.Execute(func() {
 		applyOptions(win, options)
+		if win.autoZoom {
+			win.zoom = win.calculateAutoZoom()
+		}
+		win.drawer.scalingPolicy = win.scalingPolicy
+		win.drawer.zoom = win.zoom
 		win.mouseWindow = &mouseWindow{
-			glfwWindow: win.glfwWindow,
-			zoom:       win.zoom,
+			glfwWindow:    win.glfwWindow,
+			zoom:          win.zoom,
+			scalingPolicy: win.scalingPolicy,
+			screenWidth:   width,
+			screenHeight:  height,
 		}
 		win.mouseEvents = internal.NewMouseEvents(
 			mouse.NewEventBuffer(32), // FIXME: EventBuffer size should be configurable
@@ -79,9 +107,58 @@ func newWindow(glfwWindow *glfw.Window, mainThreadLoop *MainThreadLoop, width, h
 		win.glfwWindow.Show()
 	})
 	<-sizeIsSet
+	mainThreadLoop.Execute(func() {
+		win.framebufferWidth, win.framebufferHeight = win.glfwWindow.GetFramebufferSize()
+		win.glfwWindow.SetFramebufferSizeCallback(win.onFramebufferSizeCallback)
+		if win.swapIntervalSet {
+			mainThreadLoop.bind(win.glfwWindow)
+			glfw.SwapInterval(win.swapInterval)
+		}
+		if win.mode != WindowedMode {
+			mode := win.mode
+			win.mode = WindowedMode
+			win.switchMode(mode, win.monitor, win.videoMode)
+		} else if win.maximized {
+			win.glfwWindow.Maximize()
+		}
+	})
 	return win, nil
 }
 
+// onFramebufferSizeCallback is executed in the main thread. The resize is
+// applied later by SwapBuffers.
+func (w *Window) onFramebufferSizeCallback(_ *glfw.Window, _, _ int) {
+	w.resizeMutex.Lock()
+	defer w.resizeMutex.Unlock()
+	w.resizePending = true
+}
+
+func (w *Window) applyResize() {
+	w.resizeMutex.Lock()
+	pending := w.resizePending
+	w.resizePending = false
+	w.resizeMutex.Unlock()
+	if !pending {
+		return
+	}
+	var width, height int
+	w.mainThreadLoop.Execute(func() {
+		width, height = w.glfwWindow.GetFramebufferSize()
+	})
+	if width == w.framebufferWidth && height == w.framebufferHeight {
+		return
+	}
+	w.framebufferWidth, w.framebufferHeight = width, height
+	if w.scalingPolicy == GrowScreen {
+		screenWidth, screenHeight := internal.GrownScreenSize(width, height, w.zoom)
+		w.drawer.resizeScreen(screenWidth, screenHeight)
+		w.mouseWindow.setScreenSize(screenWidth, screenHeight)
+	}
+	if w.onResize != nil {
+		w.onResize(w)
+	}
+}
+
 func newWindowDrawer(glfwWindow *glfw.Window, mainThreadLoop *MainThreadLoop, width, height int, context, sharedContext *gl.Context) (windowDrawer, error) {
 	screenAcceleratedImage := sharedContext.NewAcceleratedImage(width, height)
 	program, err := compileProgram(context, vertexShaderSrc, fragmentShaderSrc)
@@ -166,10 +243,16 @@ func (d *windowDrawer) drawIntoBackBuffer() {
 	d.mainThreadLoop.Execute(func() {
 		width, height = d.glfwWindow.GetFramebufferSize()
 	})
+	area := screenArea(d.scalingPolicy, width, height, d.screenImage.Width(), d.screenImage.Height(), d.zoom)
 	api.Disable(gl33.BLEND)
 	api.Disable(gl33.SCISSOR_TEST)
 	api.BindFramebuffer(gl33.FRAMEBUFFER, 0)
-	api.Viewport(0, 0, int32(width), int32(height))
+	if area.X != 0 || area.Y != 0 || area.Width != width || area.Height != height {
+		api.ClearColor(d.borderColor.RGBAf())
+		api.Clear(gl33.COLOR_BUFFER_BIT)
+	}
+	// OpenGL y axis grows upwards
+	api.Viewport(int32(area.X), int32(height-area.Y-area.Height), int32(area.Width), int32(area.Height))
 	api.BindTexture(gl33.TEXTURE_2D, d.screenTextureID)
 	api.UseProgram(d.program.ID())
 	d.screenPolygon.draw()
@@ -181,6 +264,34 @@ func (w *Window) SwapBuffers() {
 		panic("SwapBuffers forbidden for a closed window")
 	}
 	w.mainThreadLoop.Execute(w.glfwWindow.SwapBuffers)
+	w.applyResize()
+}
+
+// resizeScreen recreates the screen image with a new size. Pixels are copied
+// to the new image and the old one is deleted.
+func (d *windowDrawer) resizeScreen(width, height int) {
+	if d.screenImage.Width() == width && d.screenImage.Height() == height {
+		return
+	}
+	acceleratedImage := d.sharedContext.NewAcceleratedImage(width, height)
+	screenImage := image.New(acceleratedImage)
+	blend.NewSource().BlendSourceToTarget(d.screenImage.WholeImageSelection(), screenImage.WholeImageSelection())
+	d.screenImage.Delete()
+	d.screenImage = screenImage
+	d.screenTextureID = acceleratedImage.TextureID()
+}
+
+// SetSwapInterval sets the number of screen updates to wait before swapping
+// the buffers (VSync). 0 disables VSync, 1 synchronizes with every screen
+// refresh. Negative value enables adaptive VSync when supported by the
+// platform.
+func (w *Window) SetSwapInterval(interval int) {
+	w.mainThreadLoop.executeCommand(command{
+		window: w.glfwWindow,
+		execute: func() {
+			glfw.SwapInterval(interval)
+		},
+	})
 }
 
 // Close closes the window and cleans resources.
@@ -192,6 +303,14 @@ func (w *Window) Close() {
 		w.glfwWindow.SetKeyCallback(nil)
 		w.glfwWindow.SetMouseButtonCallback(nil)
 		w.glfwWindow.SetScrollCallback(nil)
+		w.glfwWindow.SetFramebufferSizeCallback(nil)
+		if w.mode != WindowedMode {
+			w.switchMode(WindowedMode, nil, VideoMode{})
+		}
+		if w.maximized {
+			w.glfwWindow.Restore()
+		}
+		w.glfwWindow.SetAttrib(glfw.Resizable, glfw.False)
 		w.glfwWindow.Hide()
 	})
 	w.drawer.close()
@@ -246,7 +365,11 @@ func (w *Window) PollKeyboardEvent() (event keyboard.Event, ok bool) {
 	return
 }
 
-// Screen returns the image.Selection for the whole Window image
+// Screen returns the image.Selection for the whole Window image.
+//
+// When the window was created with GrowScreen scaling policy the screen image
+// is replaced after each resize and the old selection can no longer be used.
+// The selection must be fetched again, for example in the OnResize callback.
 func (w *Window) Screen() image.Selection {
 	return w.drawer.screenImage.WholeImageSelection()
 }
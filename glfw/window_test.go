@@ -242,6 +242,116 @@ func TestWindow_DrawIntoBackBuffer(t *testing.T) {
 
 }
 
+func TestResizable(t *testing.T) {
+	color := image.RGBA(10, 20, 30, 40)
+	policies := map[string]glfw.ScalingPolicy{
+		"Stretch":        glfw.Stretch,
+		"IntegerScaling": glfw.IntegerScaling,
+		"GrowScreen":     glfw.GrowScreen,
+	}
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should draw screen image covering the whole window", func(t *testing.T) {
+				openGL, err := glfw.NewOpenGL(mainThreadLoop)
+				require.NoError(t, err)
+				defer openGL.Destroy()
+				window, err := openGL.OpenWindow(1, 1, glfw.NoDecorationHint(), glfw.Zoom(2), glfw.Resizable(policy))
+				require.NoError(t, err)
+				defer window.Close()
+				window.Screen().SetColor(0, 0, color)
+				// when
+				window.DrawIntoBackBuffer()
+				// then
+				expected := []image.Color{color, color, color, color}
+				assert.Equal(t, expected, framebufferPixels(window.ContextAPI(), 0, 0, 2, 2))
+			})
+			t.Run("should not change the screen size", func(t *testing.T) {
+				openGL, err := glfw.NewOpenGL(mainThreadLoop)
+				require.NoError(t, err)
+				defer openGL.Destroy()
+				window, err := openGL.OpenWindow(2, 3, glfw.Resizable(policy))
+				require.NoError(t, err)
+				defer window.Close()
+				// when
+				window.Draw()
+				// then
+				assert.Equal(t, 2, window.Screen().Width())
+				assert.Equal(t, 3, window.Screen().Height())
+			})
+		})
+	}
+}
+
+func TestBorderColor(t *testing.T) {
+	t.Run("should open window with border color", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		color := image.RGBA(10, 20, 30, 40)
+		// when
+		window, err := openGL.OpenWindow(1, 1,
+			glfw.NoDecorationHint(),
+			glfw.Resizable(glfw.IntegerScaling),
+			glfw.BorderColor(image.RGB(255, 0, 0)))
+		// then
+		require.NoError(t, err)
+		defer window.Close()
+		window.Screen().SetColor(0, 0, color)
+		window.DrawIntoBackBuffer()
+		assert.Equal(t, []image.Color{color}, framebufferPixels(window.ContextAPI(), 0, 0, 1, 1))
+	})
+}
+
+func TestOnResize(t *testing.T) {
+	t.Run("should not execute callback when window was not resized", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		executed := false
+		window, err := openGL.OpenWindow(1, 1, glfw.Resizable(glfw.GrowScreen), glfw.OnResize(func(*glfw.Window) {
+			executed = true
+		}))
+		require.NoError(t, err)
+		defer window.Close()
+		// when
+		window.Draw()
+		// then
+		assert.False(t, executed)
+	})
+}
+
+func TestSwapInterval(t *testing.T) {
+	intervals := []int{0, 1}
+	for _, interval := range intervals {
+		t.Run(fmt.Sprintf("interval=%d", interval), func(t *testing.T) {
+			openGL, err := glfw.NewOpenGL(mainThreadLoop)
+			require.NoError(t, err)
+			defer openGL.Destroy()
+			// when
+			window, err := openGL.OpenWindow(1, 1, glfw.SwapInterval(interval))
+			// then
+			require.NoError(t, err)
+			defer window.Close()
+			window.Draw()
+		})
+	}
+}
+
+func TestWindow_SetSwapInterval(t *testing.T) {
+	t.Run("should set swap interval", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		window, err := openGL.OpenWindow(1, 1)
+		require.NoError(t, err)
+		defer window.Close()
+		// when
+		window.SetSwapInterval(1)
+		// then
+		window.Draw()
+	})
+}
+
 func TestWindow_Draw(t *testing.T) {
 	t.Run("should panic for closed window", func(t *testing.T) {
 		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
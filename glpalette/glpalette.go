@@ -0,0 +1,187 @@
+// Package glpalette provides GPU palette swap tool.
+//
+//	from := palette.Palette{red, darkRed}.NewImage(openGL)
+//	to := palette.Palette{blue, darkBlue}.NewImage(openGL)
+//	swap, err := glpalette.NewSwap(openGL.Context(), from.WholeImageSelection(), to.WholeImageSelection())
+//	swap.BlendSourceToTarget(sprite, screen.Selection(10, 24))
+package glpalette
+
+import (
+	"github.com/jacekolszak/pixiq/gl"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// NewSwap creates a palette swap tool, which replaces colors from the first row
+// of "from" selection with colors having the same position in the first row
+// of "to" selection. Both selections are used as lookup textures and are read
+// each time BlendSourceToTarget is executed, so they can be modified later on.
+//
+// Will panic if selections have different widths or their first rows are not
+// entirely inside the images.
+func NewSwap(context *gl.Context, from, to image.Selection) (*Swap, error) {
+	if context == nil {
+		panic("nil context")
+	}
+	if from.Width() != to.Width() {
+		panic("from and to selections have different widths")
+	}
+	if !firstRowInsideImage(from) {
+		panic("from selection outside the image")
+	}
+	if !firstRowInsideImage(to) {
+		panic("to selection outside the image")
+	}
+	vertexShader, err := context.CompileVertexShader(vertexShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	fragmentShader, err := context.CompileFragmentShader(fragmentShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	vertexBuffer := context.NewFloatVertexBuffer(16, gl.DynamicDraw)
+	vertexArray := makeVertexArray(context, vertexBuffer)
+	command := program.AcceleratedCommand(
+		&swapCommand{
+			vertexBuffer: vertexBuffer,
+			vertexArray:  vertexArray,
+		})
+	return &Swap{
+		command: command,
+		from:    from.WithSize(from.Width(), 1),
+		to:      to.WithSize(to.Width(), 1),
+	}, nil
+}
+
+func firstRowInsideImage(selection image.Selection) bool {
+	img := selection.Image()
+	return selection.ImageX() >= 0 && selection.ImageX()+selection.Width() <= img.Width() &&
+		selection.ImageY() >= 0 && selection.ImageY() < img.Height()
+}
+
+const vertexShaderSrc = `
+#version 330 core
+
+layout(location = 0) in vec2 xy;
+layout(location = 1) in vec2 st;
+out vec2 interpolatedST;
+
+void main() {
+	gl_Position = vec4(xy, 0.0, 1.0);
+	interpolatedST = st;
+}
+`
+
+const fragmentShaderSrc = `
+#version 330 core
+
+uniform sampler2D tex;
+uniform sampler2D fromTex;
+uniform sampler2D toTex;
+// texel coordinates of the first color in the lookup textures
+uniform ivec2 from;
+uniform ivec2 to;
+uniform int colors;
+in vec2 interpolatedST;
+out vec4 color;
+
+void main() {
+	color = texture(tex, interpolatedST);
+	for (int i = 0; i < colors; i++) {
+		vec4 fromColor = texelFetch(fromTex, from + ivec2(i, 0), 0);
+		if (all(lessThan(abs(color - fromColor), vec4(0.5 / 255.0)))) {
+			color = texelFetch(toTex, to + ivec2(i, 0), 0);
+			break;
+		}
+	}
+	// color is blended with buffer using formula: S * 1 + D * (1 - Sa)
+}
+`
+
+func makeVertexArray(context *gl.Context, buffer *gl.FloatVertexBuffer) *gl.VertexArray {
+	array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Vec2})
+	xy := gl.VertexBufferPointer{Offset: 0, Stride: 4, Buffer: buffer}
+	array.Set(0, xy)
+	st := gl.VertexBufferPointer{Offset: 2, Stride: 4, Buffer: buffer}
+	array.Set(1, st)
+	return array
+}
+
+type swapCommand struct {
+	vertexBuffer *gl.FloatVertexBuffer
+	vertexArray  *gl.VertexArray
+}
+
+func (c *swapCommand) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+	source, from, to := selections[0], selections[1], selections[2]
+	renderer.BindTexture(0, "tex", source.Image)
+	renderer.BindTexture(1, "fromTex", from.Image)
+	renderer.BindTexture(2, "toTex", to.Image)
+	fromX, fromY := texelPosition(from)
+	renderer.SetIVec2("from", fromX, fromY)
+	toX, toY := texelPosition(to)
+	renderer.SetIVec2("to", toX, toY)
+	renderer.SetInt("colors", int32(from.Location.Width))
+	var (
+		imageWidth  = float32(source.Image.Width())
+		left        = float32(source.Location.X) / imageWidth
+		right       = float32(source.Location.X+source.Location.Width) / imageWidth
+		imageHeight = float32(source.Image.Height())
+		top         = (imageHeight - float32(source.Location.Y)) / imageHeight
+		bottom      = (imageHeight - float32(source.Location.Y) - float32(source.Location.Height)) / imageHeight
+	)
+	// xy -> st
+	vertices := []float32{
+		-1, 1, left, top,
+		1, 1, right, top,
+		1, -1, right, bottom,
+		-1, -1, left, bottom,
+	}
+	c.vertexBuffer.Upload(0, vertices)
+	renderer.SetBlendFactors(gl.BlendFactors{
+		SrcFactor: gl.One,
+		DstFactor: gl.OneMinusSrcAlpha,
+	})
+	renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
+}
+
+// texelPosition returns the position of the top-left pixel of selection
+// in texture coordinates, where rows are stored bottom-up.
+func texelPosition(selection image.AcceleratedImageSelection) (x, y int32) {
+	return int32(selection.Location.X), int32(selection.Image.Height() - 1 - selection.Location.Y)
+}
+
+// Swap is a palette swap tool which recolors the source selection and blends
+// results into the target selection using source-over blending. Colors which
+// are not present in the "from" lookup texture are not replaced.
+//
+// Swap uses GPU.
+type Swap struct {
+	command  *gl.AcceleratedCommand
+	from, to image.Selection
+}
+
+// BlendSourceToTarget recolors source and blends it into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *Swap) BlendSourceToTarget(source, target image.Selection) {
+	source = clampSourceToTargetImage(source, target)
+	target = target.WithSize(source.Width(), source.Height())
+	target.Modify(s.command, source, s.from, s.to)
+}
+
+func clampSourceToTargetImage(source image.Selection, target image.Selection) image.Selection {
+	width := source.Width()
+	if width+target.ImageX() > target.Image().Width() {
+		width = target.Image().Width() - target.ImageX()
+	}
+	height := source.Height()
+	if height+target.ImageY() > target.Image().Height() {
+		height = target.Image().Height() - target.ImageY()
+	}
+	return source.WithSize(width, height)
+}
@@ -0,0 +1,20 @@
+package glpalette_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/glpalette"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+)
+
+func TestNewSwap(t *testing.T) {
+	t.Run("should panic when context is nil", func(t *testing.T) {
+		lookup := image.New(fake.NewAcceleratedImage(1, 1)).WholeImageSelection()
+		assert.Panics(t, func() {
+			_, _ = glpalette.NewSwap(nil, lookup, lookup)
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package glfw_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/glfw"
+	"github.com/jacekolszak/pixiq/glpalette"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/palette"
+)
+
+var mainThreadLoop *glfw.MainThreadLoop
+
+func TestMain(m *testing.M) {
+	var exit int
+	glfw.StartMainThreadLoop(func(main *glfw.MainThreadLoop) {
+		mainThreadLoop = main
+		exit = m.Run()
+	})
+	os.Exit(exit)
+}
+
+var (
+	red         = image.RGB(255, 0, 0)
+	green       = image.RGB(0, 255, 0)
+	blue        = image.RGB(0, 0, 255)
+	translucent = image.RGBA(0, 0, 100, 100)
+	background  = image.RGB(10, 20, 30)
+)
+
+func TestNewSwap(t *testing.T) {
+	openGL, err := glfw.NewOpenGL(mainThreadLoop)
+	require.NoError(t, err)
+	defer openGL.Destroy()
+	context := openGL.Context()
+	t.Run("should panic when selections have different widths", func(t *testing.T) {
+		from := openGL.NewImage(2, 1).WholeImageSelection()
+		to := openGL.NewImage(1, 1).WholeImageSelection()
+		assert.Panics(t, func() {
+			_, _ = glpalette.NewSwap(context, from, to)
+		})
+	})
+	t.Run("should panic when selection is outside the image", func(t *testing.T) {
+		lookup := openGL.NewImage(1, 1)
+		assert.Panics(t, func() {
+			_, _ = glpalette.NewSwap(context, lookup.Selection(1, 0).WithSize(1, 1), lookup.WholeImageSelection())
+		})
+		assert.Panics(t, func() {
+			_, _ = glpalette.NewSwap(context, lookup.WholeImageSelection(), lookup.Selection(0, -1).WithSize(1, 1))
+		})
+	})
+	t.Run("should create swap", func(t *testing.T) {
+		lookup := openGL.NewImage(1, 1).WholeImageSelection()
+		swap, err := glpalette.NewSwap(context, lookup, lookup)
+		require.NoError(t, err)
+		assert.NotNil(t, swap)
+	})
+}
+
+func TestSwap_BlendSourceToTarget(t *testing.T) {
+	openGL, err := glfw.NewOpenGL(mainThreadLoop)
+	require.NoError(t, err)
+	defer openGL.Destroy()
+	context := openGL.Context()
+	var (
+		from = palette.Palette{red, green}
+		to   = palette.Palette{blue, translucent}
+	)
+	lookup := openGL.NewImage(3, 3)
+	fromSelection := lookup.Selection(1, 1).WithSize(2, 1)
+	toSelection := lookup.Selection(0, 2).WithSize(2, 1)
+	for i := range from {
+		fromSelection.SetColor(i, 0, from[i])
+		toSelection.SetColor(i, 0, to[i])
+	}
+	gpuSwap, err := glpalette.NewSwap(context, fromSelection, toSelection)
+	require.NoError(t, err)
+	cpuSwap := palette.NewSwap(fromSelection, toSelection)
+
+	tests := map[string]struct {
+		source  func(img *image.Image) image.Selection
+		targetX int
+		targetY int
+	}{
+		"whole image": {
+			source: func(img *image.Image) image.Selection { return img.WholeImageSelection() },
+		},
+		"part of image": {
+			source: func(img *image.Image) image.Selection { return img.Selection(1, 1).WithSize(2, 2) },
+		},
+		"target position": {
+			source:  func(img *image.Image) image.Selection { return img.WholeImageSelection() },
+			targetX: 2,
+			targetY: 1,
+		},
+		"target partially outside the image": {
+			source:  func(img *image.Image) image.Selection { return img.WholeImageSelection() },
+			targetX: -1,
+			targetY: 3,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			source := openGL.NewImage(3, 3)
+			sourceColors := []image.Color{red, green, blue, image.Transparent, translucent}
+			for y := 0; y < 3; y++ {
+				for x := 0; x < 3; x++ {
+					source.WholeImageSelection().SetColor(x, y, sourceColors[(x+y*3)%len(sourceColors)])
+				}
+			}
+			gpuTarget := newFilledImage(openGL, 5, 5, background)
+			cpuTarget := newFilledImage(openGL, 5, 5, background)
+			// when
+			gpuSwap.BlendSourceToTarget(test.source(source), gpuTarget.Selection(test.targetX, test.targetY))
+			cpuSwap.BlendSourceToTarget(test.source(source), cpuTarget.Selection(test.targetX, test.targetY))
+			// then
+			assertSameImages(t, cpuTarget, gpuTarget)
+		})
+	}
+}
+
+func newFilledImage(gl *glfw.OpenGL, width, height int, color image.Color) *image.Image {
+	img := gl.NewImage(width, height)
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, color)
+		}
+	}
+	return img
+}
+
+func assertSameImages(t *testing.T, expected, actual *image.Image) {
+	expectedSelection := expected.WholeImageSelection()
+	actualSelection := actual.WholeImageSelection()
+	for y := 0; y < expected.Height(); y++ {
+		for x := 0; x < expected.Width(); x++ {
+			assert.InDelta(t, expectedSelection.Color(x, y).R(), actualSelection.Color(x, y).R(), 1, "position (%d,%d)", x, y)
+			assert.InDelta(t, expectedSelection.Color(x, y).G(), actualSelection.Color(x, y).G(), 1, "position (%d,%d)", x, y)
+			assert.InDelta(t, expectedSelection.Color(x, y).B(), actualSelection.Color(x, y).B(), 1, "position (%d,%d)", x, y)
+			assert.InDelta(t, expectedSelection.Color(x, y).A(), actualSelection.Color(x, y).A(), 1, "position (%d,%d)", x, y)
+		}
+	}
+}
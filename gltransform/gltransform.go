@@ -0,0 +1,262 @@
+// Package gltransform provides GPU tools for flipping, rotating by square angles
+// and scaling selections by integer factors.
+//
+//	tool, err := gltransform.NewRotate90(openGL.Context())
+//	tool.Transform(sprite, screen.Selection(10, 24))
+package gltransform
+
+import (
+	"github.com/jacekolszak/pixiq/gl"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// NewFlipHorizontal creates a new tool which mirrors the source selection
+// horizontally (left becomes right).
+func NewFlipHorizontal(context *gl.Context) (*Tool, error) {
+	return newTool(context, transformation{
+		targetSize: sameSize,
+		sourcePosition: func(x, y, width, height float32) (float32, float32) {
+			return width - x, y
+		},
+	})
+}
+
+// NewFlipVertical creates a new tool which mirrors the source selection
+// vertically (top becomes bottom).
+func NewFlipVertical(context *gl.Context) (*Tool, error) {
+	return newTool(context, transformation{
+		targetSize: sameSize,
+		sourcePosition: func(x, y, width, height float32) (float32, float32) {
+			return x, height - y
+		},
+	})
+}
+
+// NewRotate90 creates a new tool which rotates the source selection by 90 degrees
+// clockwise.
+func NewRotate90(context *gl.Context) (*Tool, error) {
+	return newTool(context, transformation{
+		targetSize: swappedSize,
+		sourcePosition: func(x, y, width, height float32) (float32, float32) {
+			return y, height - x
+		},
+	})
+}
+
+// NewRotate180 creates a new tool which rotates the source selection by 180 degrees.
+func NewRotate180(context *gl.Context) (*Tool, error) {
+	return newTool(context, transformation{
+		targetSize: sameSize,
+		sourcePosition: func(x, y, width, height float32) (float32, float32) {
+			return width - x, height - y
+		},
+	})
+}
+
+// NewRotate270 creates a new tool which rotates the source selection by 270 degrees
+// clockwise (which is the same as 90 degrees counterclockwise).
+func NewRotate270(context *gl.Context) (*Tool, error) {
+	return newTool(context, transformation{
+		targetSize: swappedSize,
+		sourcePosition: func(x, y, width, height float32) (float32, float32) {
+			return width - y, x
+		},
+	})
+}
+
+// NewScale creates a new tool which enlarges the source selection factor times
+// using nearest-neighbour algorithm - each source pixel becomes a square of
+// factor x factor pixels.
+//
+// Will panic if factor is lower than 1.
+func NewScale(context *gl.Context, factor int) (*Tool, error) {
+	if factor < 1 {
+		panic("factor lower than 1")
+	}
+	f := float32(factor)
+	return newTool(context, transformation{
+		targetSize: func(width, height int) (int, int) {
+			return width * factor, height * factor
+		},
+		sourcePosition: func(x, y, width, height float32) (float32, float32) {
+			return x / f, y / f
+		},
+	})
+}
+
+// NewDownscale creates a new tool which shrinks the source selection factor times
+// using nearest-neighbour algorithm - the top-left pixel of each square of
+// factor x factor pixels is used. Remaining source pixels, which do not form
+// the whole square, are skipped.
+//
+// Will panic if factor is lower than 1.
+func NewDownscale(context *gl.Context, factor int) (*Tool, error) {
+	if factor < 1 {
+		panic("factor lower than 1")
+	}
+	f := float32(factor)
+	// moves texture coordinates so that the center of the top-left pixel
+	// of each square is sampled
+	shift := (f - 1) / 2
+	return newTool(context, transformation{
+		targetSize: func(width, height int) (int, int) {
+			return width / factor, height / factor
+		},
+		sourcePosition: func(x, y, width, height float32) (float32, float32) {
+			return x*f - shift, y*f - shift
+		},
+	})
+}
+
+// transformation describes how the target is calculated from source
+type transformation struct {
+	targetSize func(width, height int) (int, int)
+	// sourcePosition maps the point in the target to the point in the source.
+	// Both points are local and may be fractional.
+	sourcePosition func(x, y, width, height float32) (float32, float32)
+}
+
+func sameSize(width, height int) (int, int) {
+	return width, height
+}
+
+func swappedSize(width, height int) (int, int) {
+	return height, width
+}
+
+const vertexShaderSrc = `
+#version 330 core
+
+layout(location = 0) in vec2 xy;
+layout(location = 1) in vec2 st;
+out vec2 interpolatedST;
+
+void main() {
+	gl_Position = vec4(xy, 0.0, 1.0);
+	interpolatedST = st;
+}
+`
+
+const fragmentShaderSrc = `
+#version 330 core
+
+uniform sampler2D tex;
+in vec2 interpolatedST;
+out vec4 color;
+
+void main() {
+	color = texture(tex, interpolatedST);
+}
+`
+
+func newTool(context *gl.Context, transformation transformation) (*Tool, error) {
+	if context == nil {
+		panic("nil context")
+	}
+	vertexShader, err := context.CompileVertexShader(vertexShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	fragmentShader, err := context.CompileFragmentShader(fragmentShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	vertexBuffer := context.NewFloatVertexBuffer(16, gl.DynamicDraw)
+	vertexArray := makeVertexArray(context, vertexBuffer)
+	command := &transformCommand{
+		vertexBuffer:   vertexBuffer,
+		vertexArray:    vertexArray,
+		transformation: transformation,
+	}
+	return &Tool{
+		command:            command,
+		acceleratedCommand: program.AcceleratedCommand(command),
+	}, nil
+}
+
+func makeVertexArray(context *gl.Context, buffer *gl.FloatVertexBuffer) *gl.VertexArray {
+	array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Vec2})
+	xy := gl.VertexBufferPointer{Offset: 0, Stride: 4, Buffer: buffer}
+	array.Set(0, xy)
+	st := gl.VertexBufferPointer{Offset: 2, Stride: 4, Buffer: buffer}
+	array.Set(1, st)
+	return array
+}
+
+type transformCommand struct {
+	vertexBuffer   *gl.FloatVertexBuffer
+	vertexArray    *gl.VertexArray
+	transformation transformation
+	// size of the target area drawn by the command
+	width, height int
+}
+
+func (c *transformCommand) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+	source := selections[0]
+	renderer.BindTexture(0, "tex", source.Image)
+	var (
+		imageWidth   = float32(source.Image.Width())
+		imageHeight  = float32(source.Image.Height())
+		sourceWidth  = float32(source.Location.Width)
+		sourceHeight = float32(source.Location.Height)
+		width        = float32(c.width)
+		height       = float32(c.height)
+	)
+	st := func(x, y float32) (s, t float32) {
+		sourceX, sourceY := c.transformation.sourcePosition(x, y, sourceWidth, sourceHeight)
+		s = (float32(source.Location.X) + sourceX) / imageWidth
+		t = (imageHeight - float32(source.Location.Y) - sourceY) / imageHeight
+		return
+	}
+	var (
+		topLeftS, topLeftT         = st(0, 0)
+		topRightS, topRightT       = st(width, 0)
+		bottomRightS, bottomRightT = st(width, height)
+		bottomLeftS, bottomLeftT   = st(0, height)
+	)
+	// xy -> st
+	vertices := []float32{
+		-1, 1, topLeftS, topLeftT,
+		1, 1, topRightS, topRightT,
+		1, -1, bottomRightS, bottomRightT,
+		-1, -1, bottomLeftS, bottomLeftT,
+	}
+	c.vertexBuffer.Upload(0, vertices)
+	renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
+}
+
+// Tool is a transformation tool which transforms the source selection and puts
+// the results into the target selection.
+//
+// Tool uses GPU.
+type Tool struct {
+	command            *transformCommand
+	acceleratedCommand *gl.AcceleratedCommand
+}
+
+// TargetSize returns the size of the target area which will be modified when
+// the source selection with given size is transformed.
+func (t *Tool) TargetSize(sourceWidth, sourceHeight int) (width, height int) {
+	return t.command.transformation.targetSize(sourceWidth, sourceHeight)
+}
+
+// Transform transforms the source selection and puts the results into the target
+// selection. Only position of the target Selection is used - the size of the
+// modified area is calculated by TargetSize. Source pixels outside the source
+// image are transparent.
+func (t *Tool) Transform(source, target image.Selection) {
+	width, height := t.TargetSize(source.Width(), source.Height())
+	if width+target.ImageX() > target.Image().Width() {
+		width = target.Image().Width() - target.ImageX()
+	}
+	if height+target.ImageY() > target.Image().Height() {
+		height = target.Image().Height() - target.ImageY()
+	}
+	t.command.width = width
+	t.command.height = height
+	target.WithSize(width, height).Modify(t.acceleratedCommand, source)
+}
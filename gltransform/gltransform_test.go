@@ -0,0 +1,25 @@
+package gltransform_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/gltransform"
+)
+
+func TestNewFlipHorizontal(t *testing.T) {
+	t.Run("should panic when context is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = gltransform.NewFlipHorizontal(nil)
+		})
+	})
+}
+
+func TestNewScale(t *testing.T) {
+	t.Run("should panic when factor is lower than 1", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = gltransform.NewScale(nil, 0)
+		})
+	})
+}
@@ -0,0 +1,124 @@
+package glfw_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/glfw"
+	"github.com/jacekolszak/pixiq/gltransform"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/transform"
+)
+
+var mainThreadLoop *glfw.MainThreadLoop
+
+func TestMain(m *testing.M) {
+	var exit int
+	glfw.StartMainThreadLoop(func(main *glfw.MainThreadLoop) {
+		mainThreadLoop = main
+		exit = m.Run()
+	})
+	os.Exit(exit)
+}
+
+func TestTool_Transform(t *testing.T) {
+	openGL, err := glfw.NewOpenGL(mainThreadLoop)
+	require.NoError(t, err)
+	defer openGL.Destroy()
+	context := openGL.Context()
+
+	tools := map[string]struct {
+		gpu func() (*gltransform.Tool, error)
+		cpu *transform.Tool
+	}{
+		"flip horizontal": {
+			gpu: func() (*gltransform.Tool, error) { return gltransform.NewFlipHorizontal(context) },
+			cpu: transform.NewFlipHorizontal(),
+		},
+		"flip vertical": {
+			gpu: func() (*gltransform.Tool, error) { return gltransform.NewFlipVertical(context) },
+			cpu: transform.NewFlipVertical(),
+		},
+		"rotate 90": {
+			gpu: func() (*gltransform.Tool, error) { return gltransform.NewRotate90(context) },
+			cpu: transform.NewRotate90(),
+		},
+		"rotate 180": {
+			gpu: func() (*gltransform.Tool, error) { return gltransform.NewRotate180(context) },
+			cpu: transform.NewRotate180(),
+		},
+		"rotate 270": {
+			gpu: func() (*gltransform.Tool, error) { return gltransform.NewRotate270(context) },
+			cpu: transform.NewRotate270(),
+		},
+		"scale 3": {
+			gpu: func() (*gltransform.Tool, error) { return gltransform.NewScale(context, 3) },
+			cpu: transform.NewScale(3),
+		},
+		"downscale 2": {
+			gpu: func() (*gltransform.Tool, error) { return gltransform.NewDownscale(context, 2) },
+			cpu: transform.NewDownscale(2),
+		},
+	}
+	selections := map[string]struct {
+		source           func(img *image.Image) image.Selection
+		targetX, targetY int
+	}{
+		"whole image": {
+			source: func(img *image.Image) image.Selection { return img.WholeImageSelection() },
+		},
+		"part of image": {
+			source: func(img *image.Image) image.Selection { return img.Selection(1, 2).WithSize(4, 3) },
+		},
+		"source partially outside the image": {
+			source: func(img *image.Image) image.Selection { return img.Selection(-1, 3).WithSize(5, 4) },
+		},
+		"target partially outside the image": {
+			source:  func(img *image.Image) image.Selection { return img.WholeImageSelection() },
+			targetX: -2,
+			targetY: 15,
+		},
+	}
+	for toolName, tool := range tools {
+		t.Run(toolName, func(t *testing.T) {
+			gpuTool, err := tool.gpu()
+			require.NoError(t, err)
+			for name, selection := range selections {
+				t.Run(name, func(t *testing.T) {
+					source := newImage(openGL, 6, 5)
+					gpuTarget := openGL.NewImage(20, 20)
+					cpuTarget := openGL.NewImage(20, 20)
+					// when
+					gpuTool.Transform(selection.source(source), gpuTarget.Selection(selection.targetX, selection.targetY))
+					tool.cpu.Transform(selection.source(source), cpuTarget.Selection(selection.targetX, selection.targetY))
+					// then
+					assertSameImages(t, cpuTarget, gpuTarget)
+				})
+			}
+		})
+	}
+}
+
+func newImage(gl *glfw.OpenGL, width, height int) *image.Image {
+	img := gl.NewImage(width, height)
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, image.RGBA(byte(x+1), byte(y+1), 0, 255))
+		}
+	}
+	return img
+}
+
+func assertSameImages(t *testing.T, expected, actual *image.Image) {
+	expectedSelection := expected.WholeImageSelection()
+	actualSelection := actual.WholeImageSelection()
+	for y := 0; y < expected.Height(); y++ {
+		for x := 0; x < expected.Width(); x++ {
+			assert.Equal(t, expectedSelection.Color(x, y), actualSelection.Color(x, y), "position (%d,%d)", x, y)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package headless makes it possible to use OpenGL-accelerated images and
+// commands (such as glblend, glclear or glpalette) without a visible window
+// and without a display server. It can be used by batch image processing
+// tools and in continuous integration.
+//
+// On Linux the OpenGL 3.3 core context is created using EGL. Mesa's
+// surfaceless platform is used when available, therefore software renderer
+// (llvmpipe) works even on machines without a GPU. EGL support requires libEGL
+// and must be enabled with egl build tag:
+//
+//	go build -tags egl
+//
+// Without the tag (or on other platforms) NewOpenGL returns error.
+package headless
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/jacekolszak/pixiq/gl"
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/internal/gogl"
+)
+
+// NewOpenGL creates OpenGL instance with offscreen context. All OpenGL
+// functions are executed in a dedicated OS thread, therefore NewOpenGL
+// (contrary to glfw.NewOpenGL) does not need a main thread loop.
+//
+// You should always remember to destroy the object by executing Destroy
+// method.
+//
+// NewOpenGL may return error for different reasons, such as EGL or OpenGL 3.3
+// is not supported on the platform.
+func NewOpenGL() (*OpenGL, error) {
+	t := startThread()
+	var (
+		platform *platformContext
+		err      error
+	)
+	t.run(func() {
+		platform, err = newPlatformContext()
+	})
+	if err != nil {
+		t.stop()
+		return nil, err
+	}
+	return &OpenGL{
+		thread:   t,
+		platform: platform,
+		context:  gl.NewContext(gogl.New(t.run, t.runAsync)),
+	}, nil
+}
+
+// OpenGL provides method for creating OpenGL-accelerated image.Image without
+// a window.
+type OpenGL struct {
+	thread      *thread
+	platform    *platformContext
+	context     *gl.Context
+	destroyOnce sync.Once
+}
+
+// Destroy cleans all the OpenGL resources associated with this instance.
+// After Destroy the instance, its context and images created by it must not be
+// used anymore - all OpenGL calls will panic. Calling Destroy again does nothing.
+func (g *OpenGL) Destroy() {
+	g.destroyOnce.Do(func() {
+		g.thread.run(g.platform.destroy)
+		g.thread.stop()
+	})
+}
+
+// NewImage creates an *image.Image which is using OpenGL acceleration
+// under-the-hood.
+//
+// Will panic if width or height are negative or higher than MAX_TEXTURE_SIZE
+func (g *OpenGL) NewImage(width, height int) *image.Image {
+	if width < 0 {
+		panic("negative width")
+	}
+	if height < 0 {
+		panic("negative height")
+	}
+	acceleratedImage := g.context.NewAcceleratedImage(width, height)
+	return image.New(acceleratedImage)
+}
+
+// Context returns OpenGL's context. It's methods can be invoked from any goroutine.
+// Each invocation will return the same instance.
+func (g *OpenGL) Context() *gl.Context {
+	return g.context
+}
+
+// ContextAPI returns gl.API, which can be used to OpenGL direct access.
+// It's methods can be invoked from any goroutine.
+func (g *OpenGL) ContextAPI() gl.API {
+	return g.context.API()
+}
+
+// thread executes jobs in order in a single locked OS thread, where the
+// OpenGL context is current.
+type thread struct {
+	jobs chan func()
+	// mutex guards jobs channel from being used after it was closed
+	mutex   sync.RWMutex
+	stopped bool
+}
+
+func startThread() *thread {
+	t := &thread{
+		jobs: make(chan func(), 4096),
+	}
+	go func() {
+		runtime.LockOSThread()
+		for job := range t.jobs {
+			job()
+		}
+	}()
+	return t
+}
+
+func (t *thread) run(job func()) {
+	done := make(chan struct{})
+	t.runAsync(func() {
+		job()
+		done <- struct{}{}
+	})
+	<-done
+}
+
+func (t *thread) runAsync(job func()) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	if t.stopped {
+		panic("OpenGL destroyed")
+	}
+	t.jobs <- job
+}
+
+func (t *thread) stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.stopped = true
+	close(t.jobs)
+}
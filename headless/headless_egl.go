@@ -0,0 +1,109 @@
+// +build linux,egl
+
+package headless
+
+// #cgo LDFLAGS: -lEGL
+// #include <stdlib.h>
+// #include <EGL/egl.h>
+// #include <EGL/eglext.h>
+//
+// static EGLDisplay getDisplay() {
+// 	PFNEGLGETPLATFORMDISPLAYEXTPROC getPlatformDisplay =
+// 		(PFNEGLGETPLATFORMDISPLAYEXTPROC) eglGetProcAddress("eglGetPlatformDisplayEXT");
+// 	if (getPlatformDisplay != NULL) {
+// 		EGLDisplay display = getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, EGL_DEFAULT_DISPLAY, NULL);
+// 		if (display != EGL_NO_DISPLAY) {
+// 			return display;
+// 		}
+// 	}
+// 	return eglGetDisplay(EGL_DEFAULT_DISPLAY);
+// }
+//
+// static EGLContext createContext(EGLDisplay display) {
+// 	EGLint contextAttribs[] = {
+// 		EGL_CONTEXT_MAJOR_VERSION, 3,
+// 		EGL_CONTEXT_MINOR_VERSION, 3,
+// 		EGL_CONTEXT_OPENGL_PROFILE_MASK, EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT,
+// 		EGL_NONE
+// 	};
+// 	// context without a config can be created when EGL_KHR_no_config_context
+// 	// is supported
+// 	EGLContext context = eglCreateContext(display, EGL_NO_CONFIG_KHR, EGL_NO_CONTEXT, contextAttribs);
+// 	if (context != EGL_NO_CONTEXT) {
+// 		return context;
+// 	}
+// 	EGLint configAttribs[] = {
+// 		EGL_RENDERABLE_TYPE, EGL_OPENGL_BIT,
+// 		EGL_NONE
+// 	};
+// 	EGLConfig config;
+// 	EGLint configs;
+// 	if (!eglChooseConfig(display, configAttribs, &config, 1, &configs) || configs == 0) {
+// 		return EGL_NO_CONTEXT;
+// 	}
+// 	return eglCreateContext(display, config, EGL_NO_CONTEXT, contextAttribs);
+// }
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	gl33 "github.com/go-gl/gl/v3.3-core/gl"
+)
+
+type platformContext struct {
+	display C.EGLDisplay
+	context C.EGLContext
+}
+
+// newPlatformContext creates EGL context and makes it current in the calling
+// thread. Context does not have any surface - all drawing is done to
+// framebuffer objects.
+func newPlatformContext() (*platformContext, error) {
+	display := C.getDisplay()
+	if display == C.EGLDisplay(C.EGL_NO_DISPLAY) {
+		return nil, fmt.Errorf("eglGetDisplay failed: %s", eglError())
+	}
+	if C.eglInitialize(display, nil, nil) == C.EGL_FALSE {
+		return nil, fmt.Errorf("eglInitialize failed: %s", eglError())
+	}
+	if C.eglBindAPI(C.EGL_OPENGL_API) == C.EGL_FALSE {
+		return nil, fmt.Errorf("eglBindAPI failed: %s", eglError())
+	}
+	context := C.createContext(display)
+	if context == C.EGLContext(C.EGL_NO_CONTEXT) {
+		return nil, fmt.Errorf("eglCreateContext failed: %s", eglError())
+	}
+	noSurface := C.EGLSurface(C.EGL_NO_SURFACE)
+	if C.eglMakeCurrent(display, noSurface, noSurface, context) == C.EGL_FALSE {
+		C.eglDestroyContext(display, context)
+		return nil, fmt.Errorf("eglMakeCurrent failed: %s", eglError())
+	}
+	if err := gl33.InitWithProcAddrFunc(getProcAddress); err != nil {
+		C.eglDestroyContext(display, context)
+		return nil, err
+	}
+	return &platformContext{
+		display: display,
+		context: context,
+	}, nil
+}
+
+func getProcAddress(name string) unsafe.Pointer {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return unsafe.Pointer(C.eglGetProcAddress(cname))
+}
+
+func eglError() string {
+	return fmt.Sprintf("EGL error 0x%X", int(C.eglGetError()))
+}
+
+// destroy releases the context. Display is not terminated, because it is
+// shared by all contexts created in the process.
+func (c *platformContext) destroy() {
+	noSurface := C.EGLSurface(C.EGL_NO_SURFACE)
+	C.eglMakeCurrent(c.display, noSurface, noSurface, C.EGLContext(C.EGL_NO_CONTEXT))
+	C.eglDestroyContext(c.display, c.context)
+}
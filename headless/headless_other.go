@@ -0,0 +1,13 @@
+// +build !linux !egl
+
+package headless
+
+import "errors"
+
+type platformContext struct{}
+
+func newPlatformContext() (*platformContext, error) {
+	return nil, errors.New("headless OpenGL is supported only on Linux with egl build tag")
+}
+
+func (c *platformContext) destroy() {}
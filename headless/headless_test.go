@@ -0,0 +1,125 @@
+package headless_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/glblend"
+	"github.com/jacekolszak/pixiq/glclear"
+	"github.com/jacekolszak/pixiq/headless"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+func TestNewOpenGL(t *testing.T) {
+	t.Run("should create OpenGL", func(t *testing.T) {
+		// when
+		openGL := newOpenGL(t)
+		// then
+		defer openGL.Destroy()
+		assert.NotNil(t, openGL.Context())
+		assert.NotNil(t, openGL.ContextAPI())
+	})
+	t.Run("should create two independent instances", func(t *testing.T) {
+		openGL1 := newOpenGL(t)
+		defer openGL1.Destroy()
+		// when
+		openGL2, err := headless.NewOpenGL()
+		// then
+		require.NoError(t, err)
+		defer openGL2.Destroy()
+		assert.NotSame(t, openGL1.Context(), openGL2.Context())
+	})
+}
+
+func TestOpenGL_Destroy(t *testing.T) {
+	t.Run("should destroy twice", func(t *testing.T) {
+		openGL := newOpenGL(t)
+		openGL.Destroy()
+		assert.NotPanics(t, func() {
+			openGL.Destroy()
+		})
+	})
+	t.Run("should panic when image is created after destroy", func(t *testing.T) {
+		openGL := newOpenGL(t)
+		openGL.Destroy()
+		assert.PanicsWithValue(t, "OpenGL destroyed", func() {
+			openGL.NewImage(1, 1)
+		})
+	})
+}
+
+func TestOpenGL_NewImage(t *testing.T) {
+	t.Run("should panic when width is negative", func(t *testing.T) {
+		openGL := newOpenGL(t)
+		defer openGL.Destroy()
+		assert.Panics(t, func() {
+			openGL.NewImage(-1, 0)
+		})
+	})
+	t.Run("should panic when height is negative", func(t *testing.T) {
+		openGL := newOpenGL(t)
+		defer openGL.Destroy()
+		assert.Panics(t, func() {
+			openGL.NewImage(0, -1)
+		})
+	})
+}
+
+func TestAcceleratedImage(t *testing.T) {
+	t.Run("should download uploaded pixels", func(t *testing.T) {
+		openGL := newOpenGL(t)
+		defer openGL.Destroy()
+		img := openGL.Context().NewAcceleratedImage(2, 1)
+		pixels := []image.Color{image.RGBA(10, 20, 30, 40), image.RGBA(50, 60, 70, 80)}
+		// when
+		img.Upload(pixels)
+		// then
+		output := make([]image.Color, 2)
+		img.Download(output)
+		assert.Equal(t, pixels, output)
+		assert.NoError(t, openGL.Context().Error())
+	})
+}
+
+func TestCommands(t *testing.T) {
+	t.Run("should clear image", func(t *testing.T) {
+		openGL := newOpenGL(t)
+		defer openGL.Destroy()
+		tool := glclear.New(openGL.Context())
+		color := image.RGBA(10, 20, 30, 40)
+		tool.SetColor(color)
+		img := openGL.NewImage(2, 2)
+		// when
+		tool.Clear(img.WholeImageSelection())
+		// then
+		assert.Equal(t, color, img.WholeImageSelection().Color(1, 1))
+		assert.NoError(t, openGL.Context().Error())
+	})
+	t.Run("should blend images", func(t *testing.T) {
+		openGL := newOpenGL(t)
+		defer openGL.Destroy()
+		tool, err := glblend.NewSource(openGL.Context())
+		require.NoError(t, err)
+		color := image.RGBA(10, 20, 30, 40)
+		source := openGL.NewImage(1, 1)
+		source.WholeImageSelection().SetColor(0, 0, color)
+		target := openGL.NewImage(1, 1)
+		// when
+		tool.BlendSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assert.Equal(t, color, target.WholeImageSelection().Color(0, 0))
+		assert.NoError(t, openGL.Context().Error())
+	})
+}
+
+// newOpenGL skips the test when headless OpenGL is not available on this
+// machine, for example because it has no EGL or the egl build tag was not set
+func newOpenGL(t *testing.T) *headless.OpenGL {
+	openGL, err := headless.NewOpenGL()
+	if err != nil {
+		t.Skipf("headless OpenGL not available: %s", err)
+	}
+	return openGL
+}
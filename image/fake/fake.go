@@ -43,6 +43,18 @@ func (i *AcceleratedImage) Upload(pixels []image.Color) {
 	copy(i.pixels, pixels)
 }
 
+// UploadRegion copies pixels in a given region to a container in RAM
+func (i *AcceleratedImage) UploadRegion(pixels []image.Color, region image.AcceleratedImageLocation) {
+	if len(pixels) != i.width*i.height {
+		panic("pixels slice is not of length width*height")
+	}
+	for y := region.Y; y < region.Y+region.Height; y++ {
+		start := (i.height-1-y)*i.width + region.X
+		stop := start + region.Width
+		copy(i.pixels[start:stop], pixels[start:stop])
+	}
+}
+
 // Download fills output slice with image colors
 func (i *AcceleratedImage) Download(output []image.Color) {
 	if len(output) != i.width*i.height {
@@ -53,6 +65,19 @@ func (i *AcceleratedImage) Download(output []image.Color) {
 	}
 }
 
+// DownloadRegion fills output slice with image colors in a given region.
+// Colors outside the region are left untouched.
+func (i *AcceleratedImage) DownloadRegion(output []image.Color, region image.AcceleratedImageLocation) {
+	if len(output) != i.width*i.height {
+		panic("output slice is not of length width*height")
+	}
+	for y := region.Y; y < region.Y+region.Height; y++ {
+		start := (i.height-1-y)*i.width + region.X
+		stop := start + region.Width
+		copy(output[start:stop], i.pixels[start:stop])
+	}
+}
+
 // Width returns the number of pixels in a row.
 func (i *AcceleratedImage) Width() int {
 	return i.width
@@ -176,6 +176,81 @@ func TestAcceleratedImage_Upload(t *testing.T) {
 	})
 }
 
+func TestAcceleratedImage_UploadRegion(t *testing.T) {
+	t.Run("should panic when input slice is not of width*height length", func(t *testing.T) {
+		img := fake.NewAcceleratedImage(1, 2)
+		assert.Panics(t, func() {
+			img.UploadRegion(make([]image.Color, 1), image.AcceleratedImageLocation{Width: 1, Height: 1})
+		})
+	})
+	t.Run("should upload colors only in a given region", func(t *testing.T) {
+		var (
+			color0 = image.RGB(0, 0, 0)
+			color1 = image.RGB(1, 1, 1)
+			color2 = image.RGB(2, 2, 2)
+		)
+		img := fake.NewAcceleratedImage(2, 2)
+		img.Upload([]image.Color{color0, color0, color0, color0})
+		// when
+		img.UploadRegion(
+			[]image.Color{color1, color1, color2, color2},
+			image.AcceleratedImageLocation{X: 1, Y: 0, Width: 1, Height: 2},
+		)
+		// then
+		expected := [][]image.Color{
+			{color0, color1},
+			{color0, color2},
+		}
+		assert.Equal(t, expected, img.PixelsTable())
+	})
+}
+
+func TestAcceleratedImage_DownloadRegion(t *testing.T) {
+	t.Run("should panic when output slice is not of width*height length", func(t *testing.T) {
+		img := fake.NewAcceleratedImage(1, 2)
+		assert.Panics(t, func() {
+			img.DownloadRegion(make([]image.Color, 1), image.AcceleratedImageLocation{Width: 1, Height: 1})
+		})
+	})
+	t.Run("should download colors only in a given region", func(t *testing.T) {
+		var (
+			color0 = image.RGB(0, 0, 0)
+			color1 = image.RGB(1, 1, 1)
+			color2 = image.RGB(2, 2, 2)
+			color3 = image.RGB(3, 3, 3)
+			color4 = image.RGB(4, 4, 4)
+		)
+		tests := map[string]struct {
+			region   image.AcceleratedImageLocation
+			expected []image.Color
+		}{
+			"right column": {
+				region:   image.AcceleratedImageLocation{X: 1, Y: 0, Width: 1, Height: 2},
+				expected: []image.Color{color0, color2, color0, color4},
+			},
+			"top row": {
+				region:   image.AcceleratedImageLocation{X: 0, Y: 0, Width: 2, Height: 1},
+				expected: []image.Color{color0, color0, color3, color4},
+			},
+			"bottom-left pixel": {
+				region:   image.AcceleratedImageLocation{X: 0, Y: 1, Width: 1, Height: 1},
+				expected: []image.Color{color1, color0, color0, color0},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := fake.NewAcceleratedImage(2, 2)
+				img.Upload([]image.Color{color1, color2, color3, color4})
+				output := []image.Color{color0, color0, color0, color0}
+				// when
+				img.DownloadRegion(output, test.region)
+				// then
+				assert.Equal(t, test.expected, output)
+			})
+		}
+	})
+}
+
 func TestAcceleratedImage_PixelsTable(t *testing.T) {
 	t.Run("should return 2d slice", func(t *testing.T) {
 		color0 := image.RGB(0, 0, 0)
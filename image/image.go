@@ -31,6 +31,36 @@ type AcceleratedImage interface {
 	Delete()
 }
 
+// RegionUploader is an optional interface which can be implemented by
+// AcceleratedImage. When implemented, Image uploads only the region which has
+// been modified since the last upload instead of all pixels.
+type RegionUploader interface {
+	// UploadRegion transfers pixels in a given region from RAM to external
+	// memory (such as VRAM).
+	//
+	// Pixels slice holds all image pixels in the same order as for
+	// AcceleratedImage.Upload. Region uses image coordinates, where (0,0) is
+	// the top-left corner of the image. Region is always inside the image.
+	//
+	// Implementations must not retain pixels slice and make a copy instead.
+	UploadRegion(pixels []Color, region AcceleratedImageLocation)
+}
+
+// RegionDownloader is an optional interface which can be implemented by
+// AcceleratedImage. When implemented, Image downloads only the region which
+// has been modified by AcceleratedCommand instead of all pixels.
+type RegionDownloader interface {
+	// DownloadRegion transfers pixels in a given region from external memory
+	// (such as VRAM) to RAM. Pixels outside the region are left untouched.
+	//
+	// Output holds all image pixels in the same order as for
+	// AcceleratedImage.Download. Region uses image coordinates, where (0,0) is
+	// the top-left corner of the image. Region is always inside the image.
+	//
+	// Implementations must not retain output.
+	DownloadRegion(output []Color, region AcceleratedImageLocation)
+}
+
 // New creates an Image with same size as provided AcceleratedImage.
 // Will panic if AcceleratedImage is nil or width and height of
 // AcceleratedImage are negative
@@ -46,12 +76,16 @@ func New(acceleratedImage AcceleratedImage) *Image {
 	if height < 0 {
 		panic("negative height")
 	}
+	regionUploader, _ := acceleratedImage.(RegionUploader)
+	regionDownloader, _ := acceleratedImage.(RegionDownloader)
 	return &Image{
 		width:            width,
 		height:           height,
 		heightMinusOne:   height - 1,
 		pixels:           make([]Color, width*height),
 		acceleratedImage: acceleratedImage,
+		regionUploader:   regionUploader,
+		regionDownloader: regionDownloader,
 		selectionsCache:  make([]AcceleratedImageSelection, 0, 4),
 	}
 }
@@ -69,9 +103,52 @@ type Image struct {
 	// pixel colors line by line, starting from the bottom
 	pixels                   []Color
 	acceleratedImage         AcceleratedImage
+	regionUploader           RegionUploader
+	regionDownloader         RegionDownloader
 	selectionsCache          []AcceleratedImageSelection
 	acceleratedImageModified bool
 	ramModified              bool
+	// areas modified since the last download and upload
+	acceleratedImageModifiedArea area
+	ramModifiedArea              area
+}
+
+// area is a rectangle in image coordinates. End coordinates are exclusive.
+type area struct {
+	x1, y1, x2, y2 int
+}
+
+func (a *area) empty() bool {
+	return a.x1 >= a.x2 || a.y1 >= a.y2
+}
+
+// extend extends the area so it contains the given rectangle
+func (a *area) extend(x, y, width, height int) {
+	if a.empty() {
+		*a = area{x1: x, y1: y, x2: x + width, y2: y + height}
+		return
+	}
+	if x < a.x1 {
+		a.x1 = x
+	}
+	if y < a.y1 {
+		a.y1 = y
+	}
+	if x+width > a.x2 {
+		a.x2 = x + width
+	}
+	if y+height > a.y2 {
+		a.y2 = y + height
+	}
+}
+
+func (a *area) location() AcceleratedImageLocation {
+	return AcceleratedImageLocation{
+		X:      a.x1,
+		Y:      a.y1,
+		Width:  a.x2 - a.x1,
+		Height: a.y2 - a.y1,
+	}
 }
 
 // Width returns the number of pixels in a row.
@@ -100,16 +177,50 @@ func (i *Image) WholeImageSelection() Selection {
 	return i.Selection(0, 0).WithSize(i.width, i.height)
 }
 
-// Upload uploads all image pixels to associated AcceleratedImage.
+// Upload uploads modified image pixels to associated AcceleratedImage.
 // This method should be called rarely. Image pixels are uploaded automatically
 // when needed.
 //
+// If AcceleratedImage implements RegionUploader then only the modified region
+// is uploaded. Otherwise all pixels are uploaded.
+//
 // DEPRECATED - this method will be removed in next release
 func (i *Image) Upload() {
-	if i.ramModified {
+	if !i.ramModified {
+		return
+	}
+	region := i.ramModifiedArea.location()
+	if i.regionUploader != nil && !i.isWholeImage(region) {
+		i.regionUploader.UploadRegion(i.pixels, region)
+	} else {
 		i.acceleratedImage.Upload(i.pixels)
-		i.ramModified = false
 	}
+	i.ramModified = false
+	i.ramModifiedArea = area{}
+}
+
+// markModified extends the area modified in RAM by a horizontal line of pixels
+// starting at x, y (image coordinates).
+func (i *Image) markModified(x, y, width int) {
+	i.ramModified = true
+	i.ramModifiedArea.extend(x, y, width, 1)
+}
+
+// download downloads pixels modified by AcceleratedCommand. If AcceleratedImage
+// implements RegionDownloader then only the modified region is downloaded.
+func (i *Image) download() {
+	region := i.acceleratedImageModifiedArea.location()
+	if i.regionDownloader == nil || i.isWholeImage(region) {
+		i.acceleratedImage.Download(i.pixels)
+	} else if !i.acceleratedImageModifiedArea.empty() {
+		i.regionDownloader.DownloadRegion(i.pixels, region)
+	}
+	i.acceleratedImageModified = false
+	i.acceleratedImageModifiedArea = area{}
+}
+
+func (i *Image) isWholeImage(region AcceleratedImageLocation) bool {
+	return region.Width == i.width && region.Height == i.height
 }
 
 // Delete cleans resources allocated outside the Go heap. This method must be
@@ -188,8 +299,7 @@ func (s Selection) Selection(localX, localY int) Selection {
 // It is also possible to get the color outside the selection.
 func (s Selection) Color(localX, localY int) Color {
 	if s.image.acceleratedImageModified {
-		s.image.acceleratedImage.Download(s.image.pixels)
-		s.image.acceleratedImageModified = false
+		s.image.download()
 	}
 	x := localX + s.x
 	if x < 0 {
@@ -216,8 +326,7 @@ func (s Selection) Color(localX, localY int) Color {
 // It is possible to set the color outside the selection.
 func (s Selection) SetColor(localX, localY int, color Color) {
 	if s.image.acceleratedImageModified {
-		s.image.acceleratedImage.Download(s.image.pixels)
-		s.image.acceleratedImageModified = false
+		s.image.download()
 	}
 	x := localX + s.x
 	if x < 0 {
@@ -234,7 +343,7 @@ func (s Selection) SetColor(localX, localY int, color Color) {
 	if index >= len(s.image.pixels) {
 		return
 	}
-	s.image.ramModified = true
+	s.image.markModified(x, localY+s.y, 1)
 	s.image.pixels[index] = color
 }
 
@@ -279,6 +388,29 @@ func (s Selection) Modify(command AcceleratedCommand, selections ...Selection) {
 	s.image.Upload()
 	command.Run(s.toAcceleratedImageSelection(), convertedSelections)
 	s.image.acceleratedImageModified = true
+	s.markAcceleratedImageModified()
+}
+
+// markAcceleratedImageModified extends the area modified by AcceleratedCommand
+// by the part of selection which is inside the image.
+func (s Selection) markAcceleratedImageModified() {
+	x1, y1 := s.x, s.y
+	x2, y2 := s.x+s.width, s.y+s.height
+	if x1 < 0 {
+		x1 = 0
+	}
+	if y1 < 0 {
+		y1 = 0
+	}
+	if x2 > s.image.width {
+		x2 = s.image.width
+	}
+	if y2 > s.image.height {
+		y2 = s.image.height
+	}
+	if x1 < x2 && y1 < y2 {
+		s.image.acceleratedImageModifiedArea.extend(x1, y1, x2-x1, y2-y1)
+	}
 }
 
 func (s Selection) toAcceleratedImageSelection() AcceleratedImageSelection {
@@ -374,7 +506,9 @@ func (l Lines) YOffset() int {
 // AcceleratedCommand and changes will not be immediately reflected in a slice.
 func (l Lines) LineForWrite(line int) []Color {
 	pixels := l.line(line)
-	l.image.ramModified = true
+	if len(pixels) > 0 {
+		l.image.markModified(l.startX+l.xOffset, l.startY+l.yOffset+line, len(pixels))
+	}
 	return pixels
 }
 
@@ -419,8 +553,7 @@ func (l Lines) line(line int) []Color {
 		return []Color{}
 	}
 	if l.image.acceleratedImageModified {
-		l.image.acceleratedImage.Download(l.image.pixels)
-		l.image.acceleratedImageModified = false
+		l.image.download()
 	}
 	return l.image.pixels[start:stop]
 }
@@ -443,7 +443,7 @@ func TestSelection_SetColor(t *testing.T) {
 			commandColor = image.RGBA(50, 60, 70, 80)
 			accImg       = fake.NewAcceleratedImage(2, 1)
 			img          = image.New(accImg)
-			selection    = img.Selection(0, 0)
+			selection    = img.WholeImageSelection()
 		)
 		selection.Modify(&acceleratedCommandMock{
 			command: func(image.AcceleratedImageSelection, []image.AcceleratedImageSelection) {
@@ -519,6 +519,222 @@ func TestImage_Upload(t *testing.T) {
 		// then
 		assert.Equal(t, [][]image.Color{{color}}, acceleratedImage.PixelsTable())
 	})
+	t.Run("should upload only modified region", func(t *testing.T) {
+		color := image.RGBA(10, 20, 30, 40)
+		tests := map[string]struct {
+			modify         func(img *image.Image)
+			expectedRegion image.AcceleratedImageLocation
+		}{
+			"SetColor": {
+				modify: func(img *image.Image) {
+					img.Selection(1, 2).SetColor(0, 0, color)
+				},
+				expectedRegion: image.AcceleratedImageLocation{X: 1, Y: 2, Width: 1, Height: 1},
+			},
+			"SetColor twice": {
+				modify: func(img *image.Image) {
+					selection := img.WholeImageSelection()
+					selection.SetColor(2, 1, color)
+					selection.SetColor(1, 3, color)
+				},
+				expectedRegion: image.AcceleratedImageLocation{X: 1, Y: 1, Width: 2, Height: 3},
+			},
+			"SetColor outside the image": {
+				modify: func(img *image.Image) {
+					selection := img.WholeImageSelection()
+					selection.SetColor(1, 1, color)
+					selection.SetColor(-1, 0, color)
+					selection.SetColor(4, 3, color)
+				},
+				expectedRegion: image.AcceleratedImageLocation{X: 1, Y: 1, Width: 1, Height: 1},
+			},
+			"LineForWrite": {
+				modify: func(img *image.Image) {
+					img.Selection(1, 2).WithSize(2, 2).Lines().LineForWrite(1)
+				},
+				expectedRegion: image.AcceleratedImageLocation{X: 1, Y: 3, Width: 2, Height: 1},
+			},
+			"LineForWrite for selection partially outside the image": {
+				modify: func(img *image.Image) {
+					img.Selection(-1, -1).WithSize(3, 3).Lines().LineForWrite(0)
+				},
+				expectedRegion: image.AcceleratedImageLocation{X: 0, Y: 0, Width: 2, Height: 1},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				acceleratedImage := &regionAcceleratedImageMock{acceleratedImageStub: acceleratedImageStub{width: 3, height: 4}}
+				img := image.New(acceleratedImage)
+				test.modify(img)
+				// when
+				img.Upload()
+				// then
+				assert.Equal(t, []image.AcceleratedImageLocation{test.expectedRegion}, acceleratedImage.uploadedRegions)
+				assert.Equal(t, 0, acceleratedImage.uploads)
+			})
+		}
+	})
+	t.Run("should upload all pixels when whole image was modified", func(t *testing.T) {
+		acceleratedImage := &regionAcceleratedImageMock{acceleratedImageStub: acceleratedImageStub{width: 2, height: 2}}
+		img := image.New(acceleratedImage)
+		selection := img.WholeImageSelection()
+		selection.SetColor(0, 0, image.RGB(255, 255, 255))
+		selection.SetColor(1, 1, image.RGB(255, 255, 255))
+		// when
+		img.Upload()
+		// then
+		assert.Empty(t, acceleratedImage.uploadedRegions)
+		assert.Equal(t, 1, acceleratedImage.uploads)
+	})
+	t.Run("should not upload pixels when image was not modified since last upload", func(t *testing.T) {
+		acceleratedImage := &regionAcceleratedImageMock{acceleratedImageStub: acceleratedImageStub{width: 2, height: 2}}
+		img := image.New(acceleratedImage)
+		img.WholeImageSelection().SetColor(0, 0, image.RGB(255, 255, 255))
+		img.Upload()
+		// when
+		img.Upload()
+		// then
+		assert.Len(t, acceleratedImage.uploadedRegions, 1)
+		assert.Equal(t, 0, acceleratedImage.uploads)
+	})
+	t.Run("should reset modified region after upload", func(t *testing.T) {
+		acceleratedImage := &regionAcceleratedImageMock{acceleratedImageStub: acceleratedImageStub{width: 2, height: 2}}
+		img := image.New(acceleratedImage)
+		selection := img.WholeImageSelection()
+		selection.SetColor(0, 0, image.RGB(255, 255, 255))
+		img.Upload()
+		selection.SetColor(1, 1, image.RGB(255, 255, 255))
+		// when
+		img.Upload()
+		// then
+		expected := []image.AcceleratedImageLocation{
+			{X: 0, Y: 0, Width: 1, Height: 1},
+			{X: 1, Y: 1, Width: 1, Height: 1},
+		}
+		assert.Equal(t, expected, acceleratedImage.uploadedRegions)
+	})
+	t.Run("should upload modified region to fake AcceleratedImage", func(t *testing.T) {
+		var (
+			color1           = image.RGBA(10, 20, 30, 40)
+			color2           = image.RGBA(50, 60, 70, 80)
+			acceleratedImage = fake.NewAcceleratedImage(2, 2)
+			img              = image.New(acceleratedImage)
+			selection        = img.WholeImageSelection()
+		)
+		selection.SetColor(0, 0, color1)
+		img.Upload()
+		selection.SetColor(1, 1, color2)
+		// when
+		img.Upload()
+		// then
+		expected := [][]image.Color{
+			{image.Transparent, color2},
+			{color1, image.Transparent},
+		}
+		assert.Equal(t, expected, acceleratedImage.PixelsTable())
+	})
+}
+
+func TestImage_Download(t *testing.T) {
+	t.Run("should download only region modified by AcceleratedCommand", func(t *testing.T) {
+		tests := map[string]struct {
+			modify         func(img *image.Image)
+			expectedRegion image.AcceleratedImageLocation
+		}{
+			"selection": {
+				modify: func(img *image.Image) {
+					img.Selection(1, 2).WithSize(2, 1).Modify(&acceleratedCommandMock{})
+				},
+				expectedRegion: image.AcceleratedImageLocation{X: 1, Y: 2, Width: 2, Height: 1},
+			},
+			"selection partially outside the image": {
+				modify: func(img *image.Image) {
+					img.Selection(-1, 2).WithSize(3, 3).Modify(&acceleratedCommandMock{})
+				},
+				expectedRegion: image.AcceleratedImageLocation{X: 0, Y: 2, Width: 2, Height: 2},
+			},
+			"two selections": {
+				modify: func(img *image.Image) {
+					img.Selection(0, 0).WithSize(1, 1).Modify(&acceleratedCommandMock{})
+					img.Selection(1, 2).WithSize(1, 1).Modify(&acceleratedCommandMock{})
+				},
+				expectedRegion: image.AcceleratedImageLocation{X: 0, Y: 0, Width: 2, Height: 3},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				acceleratedImage := &regionAcceleratedImageMock{acceleratedImageStub: acceleratedImageStub{width: 3, height: 4}}
+				img := image.New(acceleratedImage)
+				test.modify(img)
+				// when
+				img.WholeImageSelection().Color(0, 0)
+				// then
+				assert.Equal(t, []image.AcceleratedImageLocation{test.expectedRegion}, acceleratedImage.downloadedRegions)
+				assert.Equal(t, 0, acceleratedImage.downloads)
+			})
+		}
+	})
+	t.Run("should download all pixels when whole image was modified", func(t *testing.T) {
+		acceleratedImage := &regionAcceleratedImageMock{acceleratedImageStub: acceleratedImageStub{width: 2, height: 2}}
+		img := image.New(acceleratedImage)
+		img.WholeImageSelection().Modify(&acceleratedCommandMock{})
+		// when
+		img.WholeImageSelection().Lines().LineForRead(0)
+		// then
+		assert.Empty(t, acceleratedImage.downloadedRegions)
+		assert.Equal(t, 1, acceleratedImage.downloads)
+	})
+	t.Run("should not download pixels when selection is outside the image", func(t *testing.T) {
+		acceleratedImage := &regionAcceleratedImageMock{acceleratedImageStub: acceleratedImageStub{width: 2, height: 2}}
+		img := image.New(acceleratedImage)
+		img.Selection(2, 0).WithSize(1, 1).Modify(&acceleratedCommandMock{})
+		// when
+		img.WholeImageSelection().SetColor(0, 0, image.Transparent)
+		// then
+		assert.Empty(t, acceleratedImage.downloadedRegions)
+		assert.Equal(t, 0, acceleratedImage.downloads)
+	})
+	t.Run("should download pixels only once", func(t *testing.T) {
+		acceleratedImage := &regionAcceleratedImageMock{acceleratedImageStub: acceleratedImageStub{width: 2, height: 2}}
+		img := image.New(acceleratedImage)
+		img.Selection(0, 0).WithSize(1, 1).Modify(&acceleratedCommandMock{})
+		selection := img.WholeImageSelection()
+		selection.Color(0, 0)
+		// when
+		selection.Color(0, 0)
+		// then
+		assert.Len(t, acceleratedImage.downloadedRegions, 1)
+	})
+	t.Run("should download modified region from fake AcceleratedImage", func(t *testing.T) {
+		var (
+			color            = image.RGBA(10, 20, 30, 40)
+			acceleratedImage = fake.NewAcceleratedImage(2, 2)
+			img              = image.New(acceleratedImage)
+			selection        = img.WholeImageSelection()
+		)
+		selection.Modify(&acceleratedCommandMock{
+			command: func(image.AcceleratedImageSelection, []image.AcceleratedImageSelection) {
+				acceleratedImage.Upload([]image.Color{color, color, color, color})
+			},
+		})
+		selection.Color(0, 0)
+		img.Selection(1, 0).WithSize(1, 1).Modify(&acceleratedCommandMock{
+			command: func(image.AcceleratedImageSelection, []image.AcceleratedImageSelection) {
+				acceleratedImage.Upload(make([]image.Color, 4))
+			},
+		})
+		// when
+		lines := [][]image.Color{
+			{selection.Color(0, 0), selection.Color(1, 0)},
+			{selection.Color(0, 1), selection.Color(1, 1)},
+		}
+		// then
+		expected := [][]image.Color{
+			{color, image.Transparent},
+			{color, color},
+		}
+		assert.Equal(t, expected, lines)
+	})
 }
 
 func TestImage_Delete(t *testing.T) {
@@ -1241,6 +1457,30 @@ func (i acceleratedImageStub) Height() int {
 }
 func (i acceleratedImageStub) Delete() {}
 
+type regionAcceleratedImageMock struct {
+	acceleratedImageStub
+	uploads           int
+	uploadedRegions   []image.AcceleratedImageLocation
+	downloads         int
+	downloadedRegions []image.AcceleratedImageLocation
+}
+
+func (i *regionAcceleratedImageMock) Upload([]image.Color) {
+	i.uploads++
+}
+
+func (i *regionAcceleratedImageMock) UploadRegion(pixels []image.Color, region image.AcceleratedImageLocation) {
+	i.uploadedRegions = append(i.uploadedRegions, region)
+}
+
+func (i *regionAcceleratedImageMock) Download([]image.Color) {
+	i.downloads++
+}
+
+func (i *regionAcceleratedImageMock) DownloadRegion(output []image.Color, region image.AcceleratedImageLocation) {
+	i.downloadedRegions = append(i.downloadedRegions, region)
+}
+
 type acceleratedCommandMock struct {
 	timesExecuted int
 	output        image.AcceleratedImageSelection
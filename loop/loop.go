@@ -0,0 +1,258 @@
+// Package loop provides a game loop with fixed-timestep updates and variable
+// rendering.
+//
+// Game state is updated with a fixed time step, no matter how fast frames are
+// rendered. Rendering is done once per frame and can be limited to a target
+// FPS:
+//
+//	l := loop.New(window, loop.UpdateRate(60), loop.TargetFPS(60))
+//	l.Run(func(step time.Duration) {
+//		// update game state by step
+//		if window.ShouldClose() {
+//			l.Stop()
+//		}
+//	}, func(alpha float64) {
+//		// draw on window.Screen()
+//	})
+package loop
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Screen is drawn at the end of each frame, for example glfw.Window
+type Screen interface {
+	Draw()
+}
+
+// Clock provides the current time and sleeping. It can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	Sleep(duration time.Duration)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+// Option is an option used when creating the Loop
+type Option func(loop *Loop)
+
+// UpdateRate sets the number of updates per second. By default it is 60.
+// For updatesPerSecond <= 0 the default is used.
+func UpdateRate(updatesPerSecond int) Option {
+	return func(loop *Loop) {
+		if updatesPerSecond > 0 {
+			loop.step = time.Second / time.Duration(updatesPerSecond)
+		}
+	}
+}
+
+// TargetFPS limits the number of rendered frames per second. The loop sleeps
+// when the frame was rendered faster. By default (or when fps <= 0) the number
+// of frames is not limited.
+func TargetFPS(fps int) Option {
+	return func(loop *Loop) {
+		if fps > 0 {
+			loop.targetFrameTime = time.Second / time.Duration(fps)
+		} else {
+			loop.targetFrameTime = 0
+		}
+	}
+}
+
+// MaxUpdatesPerFrame limits the number of updates executed in a single frame.
+// When the game can't keep up, the remaining time is dropped instead of
+// executing more and more updates each frame. By default it is 5. For
+// max <= 0 the default is used.
+func MaxUpdatesPerFrame(max int) Option {
+	return func(loop *Loop) {
+		if max > 0 {
+			loop.maxUpdatesPerFrame = max
+		}
+	}
+}
+
+// WithClock replaces the system clock. Useful in tests.
+//
+// Will panic if clock is nil.
+func WithClock(clock Clock) Option {
+	if clock == nil {
+		panic("nil clock")
+	}
+	return func(loop *Loop) {
+		loop.clock = clock
+	}
+}
+
+// New creates a new Loop drawing the screen at the end of each frame.
+//
+// Will panic if screen is nil.
+func New(screen Screen, options ...Option) *Loop {
+	if screen == nil {
+		panic("nil screen")
+	}
+	loop := &Loop{
+		screen:             screen,
+		clock:              systemClock{},
+		step:               time.Second / 60,
+		maxUpdatesPerFrame: 5,
+	}
+	for _, option := range options {
+		if option != nil {
+			option(loop)
+		}
+	}
+	return loop
+}
+
+// Loop runs the game loop with fixed-timestep updates and variable rendering.
+type Loop struct {
+	screen             Screen
+	clock              Clock
+	step               time.Duration
+	targetFrameTime    time.Duration
+	maxUpdatesPerFrame int
+	// stopped is 1 when Stop was called. It is accessed atomically, because
+	// Stop can be called from any goroutine.
+	stopped    int32
+	statsMutex sync.Mutex
+	stats      Stats
+}
+
+// Step returns the fixed time step passed to update function
+func (l *Loop) Step() time.Duration {
+	return l.step
+}
+
+// Run runs the loop until Stop is called. Each frame update is executed zero
+// or more times (each time with a fixed step), then render is executed once and
+// the screen is drawn.
+//
+// Alpha passed to render is the fraction of the step which has already
+// elapsed but was not yet simulated. It is in range [0,1) and can be used to
+// interpolate the state between two last updates.
+//
+// Will panic if update or render is nil.
+func (l *Loop) Run(update func(step time.Duration), render func(alpha float64)) {
+	if update == nil {
+		panic("nil update")
+	}
+	if render == nil {
+		panic("nil render")
+	}
+	atomic.StoreInt32(&l.stopped, 0)
+	var (
+		accumulator  time.Duration
+		maxFrameTime = l.step * time.Duration(l.maxUpdatesPerFrame)
+		frameStart   = l.clock.Now()
+	)
+	for {
+		for accumulator >= l.step && !l.isStopped() {
+			update(l.step)
+			accumulator -= l.step
+		}
+		if l.isStopped() {
+			return
+		}
+		render(float64(accumulator) / float64(l.step))
+		l.screen.Draw()
+		if l.targetFrameTime > 0 {
+			elapsed := l.clock.Now().Sub(frameStart)
+			if elapsed < l.targetFrameTime {
+				l.clock.Sleep(l.targetFrameTime - elapsed)
+			}
+		}
+		now := l.clock.Now()
+		frameTime := now.Sub(frameStart)
+		frameStart = now
+		l.statsMutex.Lock()
+		l.stats.add(frameTime)
+		l.statsMutex.Unlock()
+		if l.isStopped() {
+			return
+		}
+		accumulator += frameTime
+		if accumulator > maxFrameTime {
+			accumulator = maxFrameTime
+		}
+	}
+}
+
+// Stop stops the loop. Can be called from update or render function or from
+// any other goroutine. When called from update no more updates and rendering
+// is done. Calling Stop before Run has no effect.
+func (l *Loop) Stop() {
+	atomic.StoreInt32(&l.stopped, 1)
+}
+
+func (l *Loop) isStopped() bool {
+	return atomic.LoadInt32(&l.stopped) == 1
+}
+
+// Stats returns frame statistics gathered since the loop was started or
+// ResetStats was called. Can be called from any goroutine.
+func (l *Loop) Stats() Stats {
+	l.statsMutex.Lock()
+	defer l.statsMutex.Unlock()
+	return l.stats
+}
+
+// ResetStats resets frame statistics. Can be called from any goroutine.
+func (l *Loop) ResetStats() {
+	l.statsMutex.Lock()
+	l.stats = Stats{}
+	l.statsMutex.Unlock()
+}
+
+// Stats contains frame time statistics. Frame time is the time between
+// the start of two consecutive frames, including sleeping.
+type Stats struct {
+	// Frames is the number of measured frames
+	Frames int
+	// Total is the sum of all measured frame times
+	Total time.Duration
+	// Min is the shortest frame time
+	Min time.Duration
+	// Max is the longest frame time
+	Max time.Duration
+	// Last is the most recent frame time
+	Last time.Duration
+}
+
+func (s *Stats) add(frameTime time.Duration) {
+	if s.Frames == 0 || frameTime < s.Min {
+		s.Min = frameTime
+	}
+	if frameTime > s.Max {
+		s.Max = frameTime
+	}
+	s.Frames++
+	s.Total += frameTime
+	s.Last = frameTime
+}
+
+// Avg returns the average frame time or 0 if no frames were measured
+func (s Stats) Avg() time.Duration {
+	if s.Frames == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Frames)
+}
+
+// FPS returns the average number of frames per second or 0 if no frames
+// were measured
+func (s Stats) FPS() float64 {
+	if s.Total <= 0 {
+		return 0
+	}
+	return float64(s.Frames) / s.Total.Seconds()
+}
@@ -0,0 +1,347 @@
+package loop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/loop"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should panic when screen is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			loop.New(nil)
+		})
+	})
+	t.Run("should use 60 updates per second by default", func(t *testing.T) {
+		// when
+		l := loop.New(&fakeScreen{})
+		// then
+		assert.Equal(t, time.Second/60, l.Step())
+	})
+	t.Run("should skip nil option", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			loop.New(&fakeScreen{}, nil)
+		})
+	})
+}
+
+func TestUpdateRate(t *testing.T) {
+	tests := map[string]struct {
+		updatesPerSecond int
+		expectedStep     time.Duration
+	}{
+		"10": {
+			updatesPerSecond: 10,
+			expectedStep:     100 * time.Millisecond,
+		},
+		"0": {
+			updatesPerSecond: 0,
+			expectedStep:     time.Second / 60,
+		},
+		"-1": {
+			updatesPerSecond: -1,
+			expectedStep:     time.Second / 60,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			l := loop.New(&fakeScreen{}, loop.UpdateRate(test.updatesPerSecond))
+			// then
+			assert.Equal(t, test.expectedStep, l.Step())
+		})
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	t.Run("should panic when clock is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			loop.WithClock(nil)
+		})
+	})
+}
+
+func TestLoop_Run(t *testing.T) {
+	t.Run("should panic when update is nil", func(t *testing.T) {
+		l := loop.New(&fakeScreen{})
+		assert.Panics(t, func() {
+			l.Run(nil, func(float64) {})
+		})
+	})
+	t.Run("should panic when render is nil", func(t *testing.T) {
+		l := loop.New(&fakeScreen{})
+		assert.Panics(t, func() {
+			l.Run(func(time.Duration) {}, nil)
+		})
+	})
+	t.Run("should render and draw screen until stopped", func(t *testing.T) {
+		screen := &fakeScreen{}
+		l := loop.New(screen, loop.WithClock(&fakeClock{}))
+		renders := 0
+		// when
+		l.Run(func(time.Duration) {}, func(float64) {
+			renders++
+			if renders == 3 {
+				l.Stop()
+			}
+		})
+		// then
+		assert.Equal(t, 3, renders)
+		assert.Equal(t, 3, screen.draws)
+	})
+	t.Run("should stop when Stop was called from another goroutine", func(t *testing.T) {
+		l := loop.New(&fakeScreen{}, loop.TargetFPS(1000))
+		rendered := make(chan struct{}, 1)
+		stopped := make(chan struct{})
+		go func() {
+			l.Run(func(time.Duration) {}, func(float64) {
+				select {
+				case rendered <- struct{}{}:
+				default:
+				}
+			})
+			close(stopped)
+		}()
+		<-rendered
+		// when
+		l.Stop()
+		// then
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "loop was not stopped")
+		}
+	})
+	t.Run("should update with fixed step", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.UpdateRate(10), loop.WithClock(clock))
+		var (
+			steps   []time.Duration
+			renders int
+		)
+		// when
+		l.Run(func(step time.Duration) {
+			steps = append(steps, step)
+		}, func(float64) {
+			renders++
+			if renders == 3 {
+				l.Stop()
+			}
+			clock.advance(250 * time.Millisecond)
+		})
+		// then
+		// 500ms elapsed before the third frame
+		assert.Equal(t, []time.Duration{
+			100 * time.Millisecond,
+			100 * time.Millisecond,
+			100 * time.Millisecond,
+			100 * time.Millisecond,
+			100 * time.Millisecond,
+		}, steps)
+	})
+	t.Run("should pass alpha to render", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.UpdateRate(10), loop.WithClock(clock))
+		var alphas []float64
+		// when
+		l.Run(func(time.Duration) {}, func(alpha float64) {
+			alphas = append(alphas, alpha)
+			if len(alphas) == 3 {
+				l.Stop()
+			}
+			clock.advance(125 * time.Millisecond)
+		})
+		// then
+		require.Len(t, alphas, 3)
+		assert.InDelta(t, 0, alphas[0], 0.0001)
+		assert.InDelta(t, 0.25, alphas[1], 0.0001)
+		assert.InDelta(t, 0.5, alphas[2], 0.0001)
+	})
+	t.Run("should stop without rendering when stopped in update", func(t *testing.T) {
+		clock := &fakeClock{}
+		screen := &fakeScreen{}
+		l := loop.New(screen, loop.UpdateRate(10), loop.WithClock(clock))
+		renders := 0
+		// when
+		l.Run(func(time.Duration) {
+			l.Stop()
+		}, func(float64) {
+			renders++
+			clock.advance(100 * time.Millisecond)
+		})
+		// then
+		assert.Equal(t, 1, renders)
+		assert.Equal(t, 1, screen.draws)
+	})
+	t.Run("should limit the number of updates per frame", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.UpdateRate(10), loop.MaxUpdatesPerFrame(2), loop.WithClock(clock))
+		var (
+			updates int
+			renders int
+		)
+		// when
+		l.Run(func(time.Duration) {
+			updates++
+		}, func(float64) {
+			renders++
+			if renders == 2 {
+				l.Stop()
+			}
+			clock.advance(time.Second)
+		})
+		// then
+		assert.Equal(t, 2, updates)
+	})
+	t.Run("should sleep to reach target FPS", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.TargetFPS(10), loop.WithClock(clock))
+		renders := 0
+		// when
+		l.Run(func(time.Duration) {}, func(float64) {
+			renders++
+			if renders == 2 {
+				l.Stop()
+			}
+			clock.advance(30 * time.Millisecond)
+		})
+		// then
+		assert.Equal(t, []time.Duration{70 * time.Millisecond, 70 * time.Millisecond}, clock.sleeps)
+	})
+	t.Run("should not sleep when frame took longer than target frame time", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.TargetFPS(10), loop.WithClock(clock))
+		// when
+		l.Run(func(time.Duration) {}, func(float64) {
+			l.Stop()
+			clock.advance(200 * time.Millisecond)
+		})
+		// then
+		assert.Empty(t, clock.sleeps)
+	})
+	t.Run("should not sleep by default", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.WithClock(clock))
+		// when
+		l.Run(func(time.Duration) {}, func(float64) {
+			l.Stop()
+			clock.advance(time.Millisecond)
+		})
+		// then
+		assert.Empty(t, clock.sleeps)
+	})
+}
+
+func TestLoop_Stats(t *testing.T) {
+	t.Run("should return empty stats when loop was not run", func(t *testing.T) {
+		l := loop.New(&fakeScreen{})
+		// when
+		stats := l.Stats()
+		// then
+		assert.Equal(t, loop.Stats{}, stats)
+		assert.Equal(t, time.Duration(0), stats.Avg())
+		assert.Equal(t, 0.0, stats.FPS())
+	})
+	t.Run("should return frame statistics", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.WithClock(clock))
+		frameTimes := []time.Duration{20 * time.Millisecond, 10 * time.Millisecond, 30 * time.Millisecond}
+		frame := 0
+		// when
+		l.Run(func(time.Duration) {}, func(float64) {
+			clock.advance(frameTimes[frame])
+			frame++
+			if frame == len(frameTimes) {
+				l.Stop()
+			}
+		})
+		// then
+		stats := l.Stats()
+		assert.Equal(t, 3, stats.Frames)
+		assert.Equal(t, 60*time.Millisecond, stats.Total)
+		assert.Equal(t, 10*time.Millisecond, stats.Min)
+		assert.Equal(t, 30*time.Millisecond, stats.Max)
+		assert.Equal(t, 30*time.Millisecond, stats.Last)
+		assert.Equal(t, 20*time.Millisecond, stats.Avg())
+		assert.InDelta(t, 50.0, stats.FPS(), 0.0001)
+	})
+	t.Run("should include sleeping in frame time", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.TargetFPS(10), loop.WithClock(clock))
+		// when
+		l.Run(func(time.Duration) {}, func(float64) {
+			l.Stop()
+			clock.advance(30 * time.Millisecond)
+		})
+		// then
+		assert.Equal(t, 100*time.Millisecond, l.Stats().Last)
+	})
+	t.Run("should return stats when called from another goroutine", func(t *testing.T) {
+		l := loop.New(&fakeScreen{}, loop.TargetFPS(1000))
+		stopped := make(chan struct{})
+		frames := 0
+		go func() {
+			l.Run(func(time.Duration) {}, func(float64) {
+				frames++
+				if frames == 10 {
+					l.Stop()
+				}
+			})
+			close(stopped)
+		}()
+		// when
+		for {
+			stats := l.Stats()
+			l.ResetStats()
+			// then
+			assert.True(t, stats.Frames <= 10)
+			select {
+			case <-stopped:
+				return
+			default:
+			}
+		}
+	})
+	t.Run("ResetStats should reset statistics", func(t *testing.T) {
+		clock := &fakeClock{}
+		l := loop.New(&fakeScreen{}, loop.WithClock(clock))
+		l.Run(func(time.Duration) {}, func(float64) {
+			l.Stop()
+			clock.advance(time.Millisecond)
+		})
+		// when
+		l.ResetStats()
+		// then
+		assert.Equal(t, loop.Stats{}, l.Stats())
+	})
+}
+
+type fakeScreen struct {
+	draws int
+}
+
+func (f *fakeScreen) Draw() {
+	f.draws++
+}
+
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) Sleep(duration time.Duration) {
+	f.sleeps = append(f.sleeps, duration)
+	f.advance(duration)
+}
+
+func (f *fakeClock) advance(duration time.Duration) {
+	f.now = f.now.Add(duration)
+}
@@ -0,0 +1,221 @@
+package palette
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Reader is the equivalent of io.Reader
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+// DecodeGPL decodes palette in GIMP format (.gpl). Color names are ignored.
+func DecodeGPL(reader Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	palette := Palette{}
+	headerFound := false
+	err := scanLines(reader, func(lineNumber int, line string) error {
+		if lineNumber == 1 {
+			if line != "GIMP Palette" {
+				return errors.New("missing GIMP Palette header")
+			}
+			headerFound = true
+			return nil
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.Contains(line, ":") {
+			return nil
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return errors.New("expected 3 color components")
+		}
+		color, err := parseRGB(fields[:3])
+		if err != nil {
+			return err
+		}
+		palette = append(palette, color)
+		return nil
+	})
+	if err == nil && !headerFound {
+		err = errors.New("missing GIMP Palette header")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid GPL palette: %s", err)
+	}
+	return palette, nil
+}
+
+// DecodeJASC decodes palette in JASC (Paint Shop Pro) format (.pal).
+func DecodeJASC(reader Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	palette := Palette{}
+	count := -1
+	err := scanLines(reader, func(lineNumber int, line string) error {
+		switch lineNumber {
+		case 1:
+			if line != "JASC-PAL" {
+				return errors.New("missing JASC-PAL header")
+			}
+		case 2:
+			// version, usually 0100
+		case 3:
+			var err error
+			count, err = strconv.Atoi(line)
+			if err != nil || count < 0 {
+				return errors.New("invalid number of colors")
+			}
+		default:
+			if line == "" {
+				return nil
+			}
+			color, err := parseRGB(strings.Fields(line))
+			if err != nil {
+				return err
+			}
+			palette = append(palette, color)
+		}
+		return nil
+	})
+	if err == nil && count < 0 {
+		err = errors.New("missing number of colors")
+	}
+	if err == nil && len(palette) != count {
+		err = fmt.Errorf("expected %d colors, got %d", count, len(palette))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JASC palette: %s", err)
+	}
+	return palette, nil
+}
+
+// DecodePaintNET decodes palette in Paint.NET format (.txt). Each color is
+// written in AARRGGBB hex format. Lines starting with ';' are comments.
+func DecodePaintNET(reader Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	palette := Palette{}
+	err := scanLines(reader, func(lineNumber int, line string) error {
+		if line == "" || strings.HasPrefix(line, ";") {
+			return nil
+		}
+		if len(line) != 8 {
+			return errors.New("expected color in AARRGGBB format")
+		}
+		c, err := parseHex(line)
+		if err != nil {
+			return err
+		}
+		palette = append(palette, image.NRGBA(c[1], c[2], c[3], c[0]))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid Paint.NET palette: %s", err)
+	}
+	return palette, nil
+}
+
+// DecodeHex decodes palette in Lospec hex format (.hex). Each color is
+// written in RRGGBB hex format in a separate line.
+func DecodeHex(reader Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	palette := Palette{}
+	err := scanLines(reader, func(lineNumber int, line string) error {
+		if line == "" {
+			return nil
+		}
+		line = strings.TrimPrefix(line, "#")
+		if len(line) != 6 {
+			return errors.New("expected color in RRGGBB format")
+		}
+		c, err := parseHex(line)
+		if err != nil {
+			return err
+		}
+		palette = append(palette, image.RGB(c[0], c[1], c[2]))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex palette: %s", err)
+	}
+	return palette, nil
+}
+
+// DecodeFile decodes palette file. Format is determined by file extension:
+// .gpl (GIMP), .pal (JASC), .txt (Paint.NET) or .hex (Lospec).
+func DecodeFile(fileName string) (Palette, error) {
+	var decode func(Reader) (Palette, error)
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".gpl":
+		decode = DecodeGPL
+	case ".pal":
+		decode = DecodeJASC
+	case ".txt":
+		decode = DecodePaintNET
+	case ".hex":
+		decode = DecodeHex
+	default:
+		return nil, fmt.Errorf("unsupported palette file extension: %s", filepath.Ext(fileName))
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return decode(file)
+}
+
+// scanLines executes parseLine for each trimmed line
+func scanLines(reader Reader, parseLine func(lineNumber int, line string) error) error {
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if err := parseLine(lineNumber, line); err != nil {
+			return fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func parseRGB(fields []string) (image.Color, error) {
+	if len(fields) != 3 {
+		return image.Color{}, errors.New("expected 3 color components")
+	}
+	var rgb [3]byte
+	for i, field := range fields {
+		component, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return image.Color{}, errors.New("invalid color component " + field)
+		}
+		rgb[i] = byte(component)
+	}
+	return image.RGB(rgb[0], rgb[1], rgb[2]), nil
+}
+
+func parseHex(s string) ([]byte, error) {
+	bytes := make([]byte, len(s)/2)
+	for i := range bytes {
+		b, err := strconv.ParseUint(s[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return nil, errors.New("invalid hex color " + s)
+		}
+		bytes[i] = byte(b)
+	}
+	return bytes, nil
+}
@@ -0,0 +1,201 @@
+package palette_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/palette"
+)
+
+type decodeFunc func(palette.Reader) (palette.Palette, error)
+
+var decoders = map[string]decodeFunc{
+	"GPL":      palette.DecodeGPL,
+	"JASC":     palette.DecodeJASC,
+	"PaintNET": palette.DecodePaintNET,
+	"Hex":      palette.DecodeHex,
+}
+
+func TestDecode(t *testing.T) {
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should panic for nil reader", func(t *testing.T) {
+				assert.Panics(t, func() {
+					_, _ = decode(nil)
+				})
+			})
+			t.Run("should return error when reader returned error", func(t *testing.T) {
+				p, err := decode(&erroneousReader{err: errors.New("read error")})
+				assert.Nil(t, p)
+				assert.Error(t, err)
+			})
+		})
+	}
+}
+
+func TestDecodeGPL(t *testing.T) {
+	t.Run("should decode palette", func(t *testing.T) {
+		const gpl = `GIMP Palette
+Name: Test
+Columns: 2
+#
+  0   0   0	Black
+255 128   1	Orange
+ 10  20  30
+`
+		// when
+		p, err := palette.DecodeGPL(strings.NewReader(gpl))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, palette.Palette{black, image.RGB(255, 128, 1), image.RGB(10, 20, 30)}, p)
+	})
+	tests := map[string]string{
+		"empty":             "",
+		"missing header":    "0 0 0",
+		"missing component": "GIMP Palette\n0 0",
+		"invalid component": "GIMP Palette\n0 0 x",
+		"too big component": "GIMP Palette\n0 0 256",
+	}
+	for name, gpl := range tests {
+		t.Run("should return error for "+name, func(t *testing.T) {
+			p, err := palette.DecodeGPL(strings.NewReader(gpl))
+			assert.Nil(t, p)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeJASC(t *testing.T) {
+	t.Run("should decode palette", func(t *testing.T) {
+		const jasc = "JASC-PAL\r\n0100\r\n2\r\n0 0 0\r\n255 128 1\r\n"
+		// when
+		p, err := palette.DecodeJASC(strings.NewReader(jasc))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, palette.Palette{black, image.RGB(255, 128, 1)}, p)
+	})
+	tests := map[string]string{
+		"empty":                     "",
+		"missing header":            "0100\n1\n0 0 0",
+		"missing number of colors":  "JASC-PAL\n0100",
+		"invalid number of colors":  "JASC-PAL\n0100\nx",
+		"too many colors":           "JASC-PAL\n0100\n1\n0 0 0\n0 0 0",
+		"not enough colors":         "JASC-PAL\n0100\n2\n0 0 0",
+		"invalid component":         "JASC-PAL\n0100\n1\n0 0 x",
+		"too many color components": "JASC-PAL\n0100\n1\n0 0 0 0",
+	}
+	for name, jasc := range tests {
+		t.Run("should return error for "+name, func(t *testing.T) {
+			p, err := palette.DecodeJASC(strings.NewReader(jasc))
+			assert.Nil(t, p)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodePaintNET(t *testing.T) {
+	t.Run("should decode palette", func(t *testing.T) {
+		const txt = `; paint.net Palette File
+; Colors: 2
+FF000000
+80FF0000
+`
+		// when
+		p, err := palette.DecodePaintNET(strings.NewReader(txt))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, palette.Palette{black, image.NRGBA(255, 0, 0, 128)}, p)
+	})
+	tests := map[string]string{
+		"missing alpha": "FF0000",
+		"invalid hex":   "FFXX0000",
+	}
+	for name, txt := range tests {
+		t.Run("should return error for "+name, func(t *testing.T) {
+			p, err := palette.DecodePaintNET(strings.NewReader(txt))
+			assert.Nil(t, p)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeHex(t *testing.T) {
+	t.Run("should decode palette", func(t *testing.T) {
+		const hex = "000000\nff8001\n#0A141E\n"
+		// when
+		p, err := palette.DecodeHex(strings.NewReader(hex))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, palette.Palette{black, image.RGB(255, 128, 1), image.RGB(10, 20, 30)}, p)
+	})
+	tests := map[string]string{
+		"too short":   "00000",
+		"too long":    "0000000",
+		"invalid hex": "00000x",
+	}
+	for name, hex := range tests {
+		t.Run("should return error for "+name, func(t *testing.T) {
+			p, err := palette.DecodeHex(strings.NewReader(hex))
+			assert.Nil(t, p)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	tests := map[string]string{
+		"palette.gpl": "GIMP Palette\n255 255 255",
+		"palette.pal": "JASC-PAL\n0100\n1\n255 255 255",
+		"palette.txt": "FFFFFFFF",
+		"palette.hex": "ffffff",
+		"PALETTE.HEX": "ffffff",
+	}
+	for fileName, contents := range tests {
+		t.Run(fileName, func(t *testing.T) {
+			file := tempFile(t, fileName, contents)
+			// when
+			p, err := palette.DecodeFile(file)
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, palette.Palette{white}, p)
+		})
+	}
+	t.Run("should return error for unsupported extension", func(t *testing.T) {
+		file := tempFile(t, "palette.png", "ffffff")
+		p, err := palette.DecodeFile(file)
+		assert.Nil(t, p)
+		assert.Error(t, err)
+	})
+	t.Run("should return error for missing file", func(t *testing.T) {
+		p, err := palette.DecodeFile("missing.hex")
+		assert.Nil(t, p)
+		assert.Error(t, err)
+	})
+}
+
+func tempFile(t *testing.T, fileName, contents string) string {
+	dir, err := ioutil.TempDir("", "palette")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	file := filepath.Join(dir, fileName)
+	require.NoError(t, ioutil.WriteFile(file, []byte(contents), 0644))
+	return file
+}
+
+type erroneousReader struct {
+	err error
+}
+
+func (r *erroneousReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,147 @@
+// Package palette provides palettes - ordered lists of colors used by pixel art
+// images. Palettes can be loaded from files in popular formats and used to
+// quantize images or recolor sprites (see Swap):
+//
+//	p, err := palette.DecodeFile("pico-8.hex")
+//	tool := palette.New(p)
+//	tool.Quantize(screen)
+package palette
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// Palette is an ordered list of colors.
+type Palette []image.Color
+
+// Nearest returns the color from the palette which is the most similar to
+// the given color. Will panic if palette is empty.
+func (p Palette) Nearest(color image.Color) image.Color {
+	return p[p.NearestIndex(color)]
+}
+
+// NearestIndex returns the index of color from the palette which is the most
+// similar to the given color. When there are many such colors then the lowest
+// index is returned. Will panic if palette is empty.
+//
+// Similarity is measured as a squared euclidean distance between RGBA
+// components.
+func (p Palette) NearestIndex(color image.Color) int {
+	if len(p) == 0 {
+		panic("empty palette")
+	}
+	nearest := 0
+	minDistance := -1
+	for i, paletteColor := range p {
+		d := distance(color, paletteColor)
+		if minDistance < 0 || d < minDistance {
+			nearest = i
+			minDistance = d
+			if d == 0 {
+				break
+			}
+		}
+	}
+	return nearest
+}
+
+// Index returns the index of the color in the palette or -1 when the palette
+// does not contain the color.
+func (p Palette) Index(color image.Color) int {
+	for i, paletteColor := range p {
+		if paletteColor == color {
+			return i
+		}
+	}
+	return -1
+}
+
+// ImageFactory creates a new image with given dimensions.
+//
+// *glfw.OpenGL instance can be used as an ImageFactory implementation.
+type ImageFactory interface {
+	NewImage(width, height int) *image.Image
+}
+
+// NewImage creates a new image with palette colors placed in a single row. Such
+// image can be used as a lookup row by Swap and glpalette.Swap.
+func (p Palette) NewImage(imageFactory ImageFactory) *image.Image {
+	if imageFactory == nil {
+		panic("nil imageFactory")
+	}
+	img := imageFactory.NewImage(len(p), 1)
+	selection := img.WholeImageSelection()
+	for i, color := range p {
+		selection.SetColor(i, 0, color)
+	}
+	return img
+}
+
+func distance(c1, c2 image.Color) int {
+	r1, g1, b1, a1 := c1.RGBAi()
+	r2, g2, b2, a2 := c2.RGBAi()
+	r := r1 - r2
+	g := g1 - g2
+	b := b1 - b2
+	a := a1 - a2
+	return r*r + g*g + b*b + a*a
+}
+
+// New creates a quantization Tool using given palette. Will panic if palette
+// is empty.
+func New(palette Palette) *Tool {
+	if len(palette) == 0 {
+		panic("empty palette")
+	}
+	p := make(Palette, len(palette))
+	copy(p, palette)
+	return &Tool{
+		palette: p,
+		cache:   map[image.Color]image.Color{},
+	}
+}
+
+// maxCacheSize limits the memory used by Tool when quantizing true-color images
+const maxCacheSize = 65536
+
+// Tool is a quantization tool. It replaces colors in the selection with
+// the nearest colors from the palette.
+//
+// Tool uses CPU.
+type Tool struct {
+	palette Palette
+	// cache contains nearest colors found so far. Pixel art images usually
+	// contain only a few distinct colors, therefore cache hit ratio is high.
+	cache map[image.Color]image.Color
+}
+
+// Palette returns the copy of palette used by the tool.
+func (t *Tool) Palette() Palette {
+	p := make(Palette, len(t.palette))
+	copy(p, t.palette)
+	return p
+}
+
+// Quantize replaces each pixel in the selection with the nearest color from
+// the palette. It can be used for remapping the image to a different palette too.
+func (t *Tool) Quantize(selection image.Selection) {
+	lines := selection.Lines()
+	for y := 0; y < lines.Length(); y++ {
+		line := lines.LineForWrite(y)
+		for x := 0; x < len(line); x++ {
+			line[x] = t.nearest(line[x])
+		}
+	}
+}
+
+func (t *Tool) nearest(color image.Color) image.Color {
+	nearest, ok := t.cache[color]
+	if !ok {
+		if len(t.cache) >= maxCacheSize {
+			t.cache = map[image.Color]image.Color{}
+		}
+		nearest = t.palette.Nearest(color)
+		t.cache[color] = nearest
+	}
+	return nearest
+}
@@ -0,0 +1,27 @@
+package palette_test
+
+import (
+	"testing"
+
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/palette"
+)
+
+func BenchmarkTool_Quantize(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(640, 360))
+		selection = img.WholeImageSelection()
+		tool      = palette.New(palette.Palette{black, white, red, green})
+	)
+	for y := 0; y < img.Height(); y++ {
+		for x := 0; x < img.Width(); x++ {
+			selection.SetColor(x, y, image.RGB(byte(x%16), byte(y%16), 0))
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.Quantize(selection)
+	}
+}
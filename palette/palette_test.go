@@ -0,0 +1,173 @@
+package palette_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/palette"
+)
+
+var (
+	black = image.RGB(0, 0, 0)
+	white = image.RGB(255, 255, 255)
+	red   = image.RGB(255, 0, 0)
+	green = image.RGB(0, 255, 0)
+)
+
+func TestPalette_NearestIndex(t *testing.T) {
+	t.Run("should panic when palette is empty", func(t *testing.T) {
+		assert.Panics(t, func() {
+			palette.Palette{}.NearestIndex(black)
+		})
+	})
+	tests := map[string]struct {
+		palette       palette.Palette
+		color         image.Color
+		expectedIndex int
+	}{
+		"single color": {
+			palette:       palette.Palette{red},
+			color:         green,
+			expectedIndex: 0,
+		},
+		"exact color": {
+			palette:       palette.Palette{black, white, red},
+			color:         red,
+			expectedIndex: 2,
+		},
+		"similar color": {
+			palette:       palette.Palette{black, white, red},
+			color:         image.RGB(200, 20, 30),
+			expectedIndex: 2,
+		},
+		"similar dark color": {
+			palette:       palette.Palette{black, white, red},
+			color:         image.RGB(40, 30, 30),
+			expectedIndex: 0,
+		},
+		"transparent color": {
+			palette:       palette.Palette{black, image.Transparent, white},
+			color:         image.RGBA(10, 10, 10, 10),
+			expectedIndex: 1,
+		},
+		"lowest index when many colors are equally similar": {
+			palette:       palette.Palette{black, white, black},
+			color:         black,
+			expectedIndex: 0,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			index := test.palette.NearestIndex(test.color)
+			// then
+			assert.Equal(t, test.expectedIndex, index)
+			assert.Equal(t, test.palette[test.expectedIndex], test.palette.Nearest(test.color))
+		})
+	}
+}
+
+func TestPalette_Index(t *testing.T) {
+	p := palette.Palette{black, white}
+	assert.Equal(t, 1, p.Index(white))
+	assert.Equal(t, -1, p.Index(red))
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should panic when palette is empty", func(t *testing.T) {
+		assert.Panics(t, func() {
+			palette.New(palette.Palette{})
+		})
+		assert.Panics(t, func() {
+			palette.New(nil)
+		})
+	})
+	t.Run("should create tool", func(t *testing.T) {
+		tool := palette.New(palette.Palette{black})
+		assert.NotNil(t, tool)
+	})
+	t.Run("should copy palette", func(t *testing.T) {
+		p := palette.Palette{black}
+		tool := palette.New(p)
+		// when
+		p[0] = white
+		// then
+		assert.Equal(t, palette.Palette{black}, tool.Palette())
+	})
+}
+
+func TestTool_Quantize(t *testing.T) {
+	t.Run("should replace colors with nearest colors from palette", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(3, 2))
+		selection := img.WholeImageSelection()
+		selection.SetColor(0, 0, image.RGB(250, 240, 230))
+		selection.SetColor(1, 0, image.RGB(10, 20, 5))
+		selection.SetColor(2, 0, image.RGB(250, 240, 230))
+		selection.SetColor(0, 1, image.RGB(200, 10, 10))
+		selection.SetColor(1, 1, black)
+		selection.SetColor(2, 1, white)
+		tool := palette.New(palette.Palette{black, white, red})
+		// when
+		tool.Quantize(selection)
+		// then
+		assert.Equal(t, white, selection.Color(0, 0))
+		assert.Equal(t, black, selection.Color(1, 0))
+		assert.Equal(t, white, selection.Color(2, 0))
+		assert.Equal(t, red, selection.Color(0, 1))
+		assert.Equal(t, black, selection.Color(1, 1))
+		assert.Equal(t, white, selection.Color(2, 1))
+	})
+	t.Run("should quantize only selection", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(3, 1))
+		whole := img.WholeImageSelection()
+		whole.SetColor(0, 0, green)
+		whole.SetColor(1, 0, green)
+		whole.SetColor(2, 0, green)
+		tool := palette.New(palette.Palette{black})
+		// when
+		tool.Quantize(img.Selection(1, 0).WithSize(1, 1))
+		// then
+		assert.Equal(t, green, whole.Color(0, 0))
+		assert.Equal(t, black, whole.Color(1, 0))
+		assert.Equal(t, green, whole.Color(2, 0))
+	})
+	t.Run("should quantize selection partially outside the image", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		whole := img.WholeImageSelection()
+		whole.SetColor(0, 0, green)
+		tool := palette.New(palette.Palette{black})
+		// when
+		tool.Quantize(img.Selection(-1, -1).WithSize(3, 3))
+		// then
+		assert.Equal(t, black, whole.Color(0, 0))
+	})
+}
+
+func TestPalette_NewImage(t *testing.T) {
+	t.Run("should panic for nil imageFactory", func(t *testing.T) {
+		assert.Panics(t, func() {
+			palette.Palette{black}.NewImage(nil)
+		})
+	})
+	t.Run("should create image with palette colors in a single row", func(t *testing.T) {
+		p := palette.Palette{black, white, red}
+		// when
+		img := p.NewImage(fakeImageFactory{})
+		// then
+		assert.Equal(t, 3, img.Width())
+		assert.Equal(t, 1, img.Height())
+		selection := img.WholeImageSelection()
+		for i, color := range p {
+			assert.Equal(t, color, selection.Color(i, 0))
+		}
+	})
+}
+
+type fakeImageFactory struct{}
+
+func (f fakeImageFactory) NewImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
@@ -0,0 +1,89 @@
+package palette
+
+import (
+	"github.com/jacekolszak/pixiq/blend"
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// NewSwap creates a palette swap tool, which replaces colors from the first row
+// of "from" selection with colors having the same position in the first row
+// of "to" selection. It can be used to recolor sprites, for example to show team
+// colors or damage flashes. Both rows are read each time BlendSourceToTarget is
+// executed, so they can be modified later on. It is a CPU equivalent of
+// glpalette.Swap and takes the same lookup rows (see Palette.NewImage).
+//
+// Will panic if selections have different widths or their first rows are not
+// entirely inside the images.
+func NewSwap(from, to image.Selection) *Swap {
+	if from.Width() != to.Width() {
+		panic("from and to selections have different widths")
+	}
+	if !firstRowInsideImage(from) {
+		panic("from selection outside the image")
+	}
+	if !firstRowInsideImage(to) {
+		panic("to selection outside the image")
+	}
+	colors := &colorSwap{
+		colors:     map[image.Color]image.Color{},
+		sourceOver: blend.NewSourceOver(),
+	}
+	return &Swap{
+		from:   from.WithSize(from.Width(), 1),
+		to:     to.WithSize(to.Width(), 1),
+		colors: colors,
+		tool:   blend.New(colors),
+	}
+}
+
+func firstRowInsideImage(selection image.Selection) bool {
+	img := selection.Image()
+	return selection.ImageX() >= 0 && selection.ImageX()+selection.Width() <= img.Width() &&
+		selection.ImageY() >= 0 && selection.ImageY() < img.Height()
+}
+
+// Swap is a palette swap tool which recolors the source selection and blends
+// results into the target selection using source-over blending. Colors which
+// are not present in the "from" row are not replaced. Source pixels outside
+// the source image are treated as transparent.
+//
+// Swap uses CPU.
+type Swap struct {
+	from, to image.Selection
+	colors   *colorSwap
+	tool     *blend.Tool
+}
+
+// BlendSourceToTarget recolors source and blends it into target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (s *Swap) BlendSourceToTarget(source, target image.Selection) {
+	s.colors.update(s.from, s.to)
+	s.tool.BlendSourceToTarget(source, target)
+}
+
+// colorSwap is a blend.ColorBlender replacing source color before blending
+type colorSwap struct {
+	colors     map[image.Color]image.Color
+	sourceOver *blend.SourceOver
+}
+
+// update reads colors from lookup rows. When the color occurs many times in
+// the "from" row, the first occurrence is used.
+func (c *colorSwap) update(from, to image.Selection) {
+	for color := range c.colors {
+		delete(c.colors, color)
+	}
+	fromLine := from.Lines().LineForRead(0)
+	toLine := to.Lines().LineForRead(0)
+	for i := len(fromLine) - 1; i >= 0; i-- {
+		c.colors[fromLine[i]] = toLine[i]
+	}
+}
+
+func (c *colorSwap) BlendSourceToTargetColor(source, target image.Color) image.Color {
+	if color, ok := c.colors[source]; ok {
+		source = color
+	}
+	return c.sourceOver.BlendSourceToTargetColor(source, target)
+}
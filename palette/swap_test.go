@@ -0,0 +1,223 @@
+package palette_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/palette"
+)
+
+func TestNewSwap(t *testing.T) {
+	lookup := newSelection(2, 1)
+	t.Run("should panic when selections have different widths", func(t *testing.T) {
+		assert.Panics(t, func() {
+			palette.NewSwap(lookup.WithSize(1, 1), lookup)
+		})
+	})
+	t.Run("should panic when from selection is outside the image", func(t *testing.T) {
+		assert.Panics(t, func() {
+			palette.NewSwap(lookup.Selection(1, 0), lookup)
+		})
+	})
+	t.Run("should panic when to selection is outside the image", func(t *testing.T) {
+		assert.Panics(t, func() {
+			palette.NewSwap(lookup, lookup.Selection(0, -1))
+		})
+	})
+	t.Run("should create tool", func(t *testing.T) {
+		swap := palette.NewSwap(lookup, lookup)
+		assert.NotNil(t, swap)
+	})
+}
+
+func TestSwap_BlendSourceToTarget(t *testing.T) {
+	var (
+		blue         = image.RGB(0, 0, 255)
+		translucent  = image.RGBA(0, 0, 100, 100)
+		background   = image.RGB(10, 20, 30)
+		blendedColor = image.RGB(6, 12, 118)
+	)
+	tests := map[string]struct {
+		from, to palette.Palette
+		source   image.Color
+		target   image.Color
+		expected image.Color
+	}{
+		"color from palette": {
+			from:     palette.Palette{black, red},
+			to:       palette.Palette{white, blue},
+			source:   red,
+			target:   background,
+			expected: blue,
+		},
+		"color not in palette": {
+			from:     palette.Palette{black},
+			to:       palette.Palette{white},
+			source:   green,
+			target:   background,
+			expected: green,
+		},
+		"first occurrence of color": {
+			from:     palette.Palette{red, red},
+			to:       palette.Palette{white, blue},
+			source:   red,
+			target:   background,
+			expected: white,
+		},
+		"translucent color": {
+			from:     palette.Palette{red},
+			to:       palette.Palette{translucent},
+			source:   red,
+			target:   background,
+			expected: blendedColor,
+		},
+		"transparent color": {
+			from:     palette.Palette{red},
+			to:       palette.Palette{blue},
+			source:   image.Transparent,
+			target:   background,
+			expected: background,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			source := newSelection(1, 1)
+			source.SetColor(0, 0, test.source)
+			target := newSelection(1, 1)
+			target.SetColor(0, 0, test.target)
+			swap := palette.NewSwap(lookupRow(test.from), lookupRow(test.to))
+			// when
+			swap.BlendSourceToTarget(source, target)
+			// then
+			assert.Equal(t, test.expected, target.Color(0, 0))
+		})
+	}
+	t.Run("should use lookup rows from larger images", func(t *testing.T) {
+		lookup := newSelection(3, 3)
+		lookup.SetColor(1, 1, red)
+		lookup.SetColor(0, 2, white)
+		source := newSelection(1, 1)
+		source.SetColor(0, 0, red)
+		target := newSelection(1, 1)
+		swap := palette.NewSwap(lookup.Selection(1, 1).WithSize(1, 1), lookup.Selection(0, 2).WithSize(1, 1))
+		// when
+		swap.BlendSourceToTarget(source, target)
+		// then
+		assert.Equal(t, white, target.Color(0, 0))
+	})
+	t.Run("should read lookup rows each time", func(t *testing.T) {
+		from := lookupRow(palette.Palette{red})
+		to := lookupRow(palette.Palette{white})
+		swap := palette.NewSwap(from, to)
+		to.SetColor(0, 0, green)
+		source := newSelection(1, 1)
+		source.SetColor(0, 0, red)
+		target := newSelection(1, 1)
+		// when
+		swap.BlendSourceToTarget(source, target)
+		// then
+		assert.Equal(t, green, target.Color(0, 0))
+	})
+	t.Run("should blend selections", func(t *testing.T) {
+		tests := map[string]struct {
+			source, target func(source, target *image.Image) (image.Selection, image.Selection)
+			expected       [3][3]image.Color
+		}{
+			"target position": {
+				source: func(source, target *image.Image) (image.Selection, image.Selection) {
+					return source.WholeImageSelection(), target.Selection(1, 2)
+				},
+				expected: [3][3]image.Color{
+					{black, black, black},
+					{black, black, black},
+					{black, white, green},
+				},
+			},
+			"source selection": {
+				source: func(source, target *image.Image) (image.Selection, image.Selection) {
+					return source.Selection(1, 0).WithSize(1, 1), target.WholeImageSelection()
+				},
+				expected: [3][3]image.Color{
+					{green, black, black},
+					{black, black, black},
+					{black, black, black},
+				},
+			},
+			"source partially outside the image": {
+				source: func(source, target *image.Image) (image.Selection, image.Selection) {
+					return source.Selection(-1, -1).WithSize(3, 2), target.WholeImageSelection()
+				},
+				expected: [3][3]image.Color{
+					{black, black, black},
+					{black, white, green},
+					{black, black, black},
+				},
+			},
+			"target partially outside the image": {
+				source: func(source, target *image.Image) (image.Selection, image.Selection) {
+					return source.WholeImageSelection(), target.Selection(-1, -1)
+				},
+				expected: [3][3]image.Color{
+					{black, black, black},
+					{black, black, black},
+					{black, black, black},
+				},
+			},
+			"target partially outside the image on the left": {
+				source: func(source, target *image.Image) (image.Selection, image.Selection) {
+					return source.WholeImageSelection(), target.Selection(-1, 0)
+				},
+				expected: [3][3]image.Color{
+					{green, black, black},
+					{black, black, black},
+					{black, black, black},
+				},
+			},
+			"target outside the image": {
+				source: func(source, target *image.Image) (image.Selection, image.Selection) {
+					return source.WholeImageSelection(), target.Selection(3, 0)
+				},
+				expected: [3][3]image.Color{
+					{black, black, black},
+					{black, black, black},
+					{black, black, black},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				sourceImage := image.New(fake.NewAcceleratedImage(2, 1))
+				sourceImage.WholeImageSelection().SetColor(0, 0, red)
+				sourceImage.WholeImageSelection().SetColor(1, 0, green)
+				targetImage := image.New(fake.NewAcceleratedImage(3, 3))
+				for y := 0; y < 3; y++ {
+					for x := 0; x < 3; x++ {
+						targetImage.WholeImageSelection().SetColor(x, y, black)
+					}
+				}
+				source, target := test.source(sourceImage, targetImage)
+				swap := palette.NewSwap(lookupRow(palette.Palette{red}), lookupRow(palette.Palette{white}))
+				// when
+				swap.BlendSourceToTarget(source, target)
+				// then
+				whole := targetImage.WholeImageSelection()
+				for y, line := range test.expected {
+					for x, expected := range line {
+						assert.Equal(t, expected, whole.Color(x, y), "position (%d,%d)", x, y)
+					}
+				}
+			})
+		}
+	})
+}
+
+func lookupRow(p palette.Palette) image.Selection {
+	return p.NewImage(fakeImageFactory{}).WholeImageSelection()
+}
+
+func newSelection(width, height int) image.Selection {
+	return image.New(fake.NewAcceleratedImage(width, height)).WholeImageSelection()
+}
@@ -0,0 +1,94 @@
+// Package parallel provides a pool of goroutines which can be used by CPU tools
+// to process image lines in parallel.
+//
+//	pool := parallel.NewPool(runtime.NumCPU())
+//	defer pool.Close()
+//	blender := blend.NewSourceOver()
+//	blender.SetPool(pool)
+package parallel
+
+import (
+	"sync"
+)
+
+// NewPool creates a Pool with given number of worker goroutines. Pool must be
+// closed when it is not used anymore.
+func NewPool(workers int) *Pool {
+	if workers < 1 {
+		panic("workers lower than 1")
+	}
+	pool := &Pool{
+		workers: workers,
+		jobs:    make(chan job, workers),
+	}
+	for i := 0; i < workers; i++ {
+		go pool.work()
+	}
+	return pool
+}
+
+// Pool is a fixed-size pool of worker goroutines. It is safe to use the Pool
+// from many goroutines at the same time.
+type Pool struct {
+	workers int
+	jobs    chan job
+}
+
+type job struct {
+	process    func(start, end int)
+	start, end int
+	done       *sync.WaitGroup
+}
+
+func (p *Pool) work() {
+	for j := range p.jobs {
+		j.process(j.start, j.end)
+		j.done.Done()
+	}
+}
+
+// Workers returns the number of worker goroutines.
+func (p *Pool) Workers() int {
+	return p.workers
+}
+
+// Run splits the range [0,n) into continuous chunks, one per worker, and calls
+// process for each chunk in worker goroutines. Run returns when all chunks
+// are processed. Chunks never overlap, so process can safely modify data indexed
+// by the range without additional synchronization.
+//
+// When n is lower than 2, Pool has only one worker or Pool is nil, process is
+// called in the current goroutine.
+func (p *Pool) Run(n int, process func(start, end int)) {
+	if n <= 0 {
+		return
+	}
+	if p == nil {
+		process(0, n)
+		return
+	}
+	chunks := p.workers
+	if chunks > n {
+		chunks = n
+	}
+	if chunks == 1 {
+		process(0, n)
+		return
+	}
+	var done sync.WaitGroup
+	done.Add(chunks)
+	for i := 0; i < chunks; i++ {
+		p.jobs <- job{
+			process: process,
+			start:   i * n / chunks,
+			end:     (i + 1) * n / chunks,
+			done:    &done,
+		}
+	}
+	done.Wait()
+}
+
+// Close stops all worker goroutines. Pool cannot be used after Close.
+func (p *Pool) Close() {
+	close(p.jobs)
+}
@@ -0,0 +1,19 @@
+package parallel_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/jacekolszak/pixiq/parallel"
+)
+
+func BenchmarkPool_Run(b *testing.B) {
+	pool := parallel.NewPool(runtime.NumCPU())
+	defer pool.Close()
+	process := func(start, end int) {}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pool.Run(1080, process)
+	}
+}
@@ -0,0 +1,85 @@
+package parallel_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/parallel"
+)
+
+func TestNewPool(t *testing.T) {
+	t.Run("should panic when workers is lower than 1", func(t *testing.T) {
+		for _, workers := range []int{-1, 0} {
+			assert.Panics(t, func() {
+				parallel.NewPool(workers)
+			}, "workers %d", workers)
+		}
+	})
+	t.Run("should create pool", func(t *testing.T) {
+		pool := parallel.NewPool(2)
+		defer pool.Close()
+		assert.Equal(t, 2, pool.Workers())
+	})
+}
+
+func TestPool_Run(t *testing.T) {
+	t.Run("should process each index exactly once", func(t *testing.T) {
+		for _, workers := range []int{1, 2, 3, 8} {
+			for _, n := range []int{1, 2, 3, 7, 100} {
+				pool := parallel.NewPool(workers)
+				counts := make([]int, n)
+				// when
+				pool.Run(n, func(start, end int) {
+					for i := start; i < end; i++ {
+						counts[i]++
+					}
+				})
+				// then
+				for i, count := range counts {
+					assert.Equal(t, 1, count, "workers %d, n %d, index %d", workers, n, i)
+				}
+				pool.Close()
+			}
+		}
+	})
+	t.Run("should not call process when n is 0", func(t *testing.T) {
+		pool := parallel.NewPool(2)
+		defer pool.Close()
+		called := false
+		// when
+		pool.Run(0, func(start, end int) {
+			called = true
+		})
+		// then
+		assert.False(t, called)
+	})
+	t.Run("should split range into chunks", func(t *testing.T) {
+		pool := parallel.NewPool(4)
+		defer pool.Close()
+		var (
+			mutex  sync.Mutex
+			chunks int
+		)
+		// when
+		pool.Run(100, func(start, end int) {
+			mutex.Lock()
+			chunks++
+			mutex.Unlock()
+		})
+		// then
+		assert.Equal(t, 4, chunks)
+	})
+	t.Run("nil pool should process whole range in current goroutine", func(t *testing.T) {
+		var pool *parallel.Pool
+		var start, end int
+		// when
+		pool.Run(5, func(s, e int) {
+			start, end = s, e
+		})
+		// then
+		assert.Equal(t, 0, start)
+		assert.Equal(t, 5, end)
+	})
+}
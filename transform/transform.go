@@ -0,0 +1,175 @@
+// Package transform provides CPU tools for flipping, rotating by square angles
+// and scaling selections by integer factors. These are the only transformations
+// which preserve the look of pixel art.
+//
+//	tool := transform.NewRotate90()
+//	tool.Transform(sprite, screen.Selection(10, 24))
+package transform
+
+import (
+	"github.com/jacekolszak/pixiq/image"
+)
+
+// NewFlipHorizontal creates a new tool which mirrors the source selection
+// horizontally (left becomes right).
+func NewFlipHorizontal() *Tool {
+	return &Tool{
+		targetSize: sameSize,
+		sourcePosition: func(x, y, width, height int) (int, int) {
+			return width - 1 - x, y
+		},
+	}
+}
+
+// NewFlipVertical creates a new tool which mirrors the source selection
+// vertically (top becomes bottom).
+func NewFlipVertical() *Tool {
+	return &Tool{
+		targetSize: sameSize,
+		sourcePosition: func(x, y, width, height int) (int, int) {
+			return x, height - 1 - y
+		},
+	}
+}
+
+// NewRotate90 creates a new tool which rotates the source selection by 90 degrees
+// clockwise.
+func NewRotate90() *Tool {
+	return &Tool{
+		targetSize: swappedSize,
+		sourcePosition: func(x, y, width, height int) (int, int) {
+			return y, height - 1 - x
+		},
+	}
+}
+
+// NewRotate180 creates a new tool which rotates the source selection by 180 degrees.
+func NewRotate180() *Tool {
+	return &Tool{
+		targetSize: sameSize,
+		sourcePosition: func(x, y, width, height int) (int, int) {
+			return width - 1 - x, height - 1 - y
+		},
+	}
+}
+
+// NewRotate270 creates a new tool which rotates the source selection by 270 degrees
+// clockwise (which is the same as 90 degrees counterclockwise).
+func NewRotate270() *Tool {
+	return &Tool{
+		targetSize: swappedSize,
+		sourcePosition: func(x, y, width, height int) (int, int) {
+			return width - 1 - y, x
+		},
+	}
+}
+
+// NewScale creates a new tool which enlarges the source selection factor times
+// using nearest-neighbour algorithm - each source pixel becomes a square of
+// factor x factor pixels.
+//
+// Will panic if factor is lower than 1.
+func NewScale(factor int) *Tool {
+	if factor < 1 {
+		panic("factor lower than 1")
+	}
+	return &Tool{
+		targetSize: func(width, height int) (int, int) {
+			return width * factor, height * factor
+		},
+		sourcePosition: func(x, y, width, height int) (int, int) {
+			return x / factor, y / factor
+		},
+	}
+}
+
+// NewDownscale creates a new tool which shrinks the source selection factor times
+// using nearest-neighbour algorithm - the top-left pixel of each square of
+// factor x factor pixels is used. Remaining source pixels, which do not form
+// the whole square, are skipped.
+//
+// Will panic if factor is lower than 1.
+func NewDownscale(factor int) *Tool {
+	if factor < 1 {
+		panic("factor lower than 1")
+	}
+	return &Tool{
+		targetSize: func(width, height int) (int, int) {
+			return width / factor, height / factor
+		},
+		sourcePosition: func(x, y, width, height int) (int, int) {
+			return x * factor, y * factor
+		},
+	}
+}
+
+func sameSize(width, height int) (int, int) {
+	return width, height
+}
+
+func swappedSize(width, height int) (int, int) {
+	return height, width
+}
+
+// Tool is a transformation tool which transforms the source selection and puts
+// the results into the target selection.
+//
+// Tool uses CPU.
+type Tool struct {
+	targetSize     func(width, height int) (int, int)
+	sourcePosition func(x, y, width, height int) (int, int)
+	// sourceLines is reused by Transform to avoid allocations
+	sourceLines [][]image.Color
+}
+
+// TargetSize returns the size of the target area which will be modified when
+// the source selection with given size is transformed.
+func (t *Tool) TargetSize(sourceWidth, sourceHeight int) (width, height int) {
+	return t.targetSize(sourceWidth, sourceHeight)
+}
+
+// Transform transforms the source selection and puts the results into the target
+// selection. Only position of the target Selection is used - the size of the
+// modified area is calculated by TargetSize. Source pixels outside the source
+// image are transparent.
+func (t *Tool) Transform(source, target image.Selection) {
+	var (
+		sourceWidth   = source.Width()
+		sourceHeight  = source.Height()
+		width, height = t.targetSize(sourceWidth, sourceHeight)
+		sourceLines   = source.Lines()
+		sourceXOffset = sourceLines.XOffset()
+		sourceYOffset = sourceLines.YOffset()
+		targetLines   = target.WithSize(width, height).Lines()
+		targetXOffset = targetLines.XOffset()
+		targetYOffset = targetLines.YOffset()
+	)
+	for y := 0; y < sourceLines.Length(); y++ {
+		t.sourceLines = append(t.sourceLines, sourceLines.LineForRead(y))
+	}
+	for y := 0; y < targetLines.Length(); y++ {
+		targetLine := targetLines.LineForWrite(y)
+		for x := 0; x < len(targetLine); x++ {
+			sourceX, sourceY := t.sourcePosition(x+targetXOffset, y+targetYOffset, sourceWidth, sourceHeight)
+			targetLine[x] = t.sourceColor(sourceX-sourceXOffset, sourceY-sourceYOffset)
+		}
+	}
+	// do not retain image pixels
+	for i := range t.sourceLines {
+		t.sourceLines[i] = nil
+	}
+	t.sourceLines = t.sourceLines[:0]
+}
+
+// sourceColor returns the color at given position in sourceLines or
+// transparent color when position is outside the source image
+func (t *Tool) sourceColor(x, y int) image.Color {
+	if y < 0 || y >= len(t.sourceLines) {
+		return image.Transparent
+	}
+	line := t.sourceLines[y]
+	if x < 0 || x >= len(line) {
+		return image.Transparent
+	}
+	return line[x]
+}
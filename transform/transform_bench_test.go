@@ -0,0 +1,30 @@
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/transform"
+)
+
+func BenchmarkTool_Transform(b *testing.B) {
+	tools := map[string]*transform.Tool{
+		"flip horizontal": transform.NewFlipHorizontal(),
+		"rotate 90":       transform.NewRotate90(),
+		"scale 2":         transform.NewScale(2),
+	}
+	for name, tool := range tools {
+		b.Run(name, func(b *testing.B) {
+			var (
+				source = image.New(fake.NewAcceleratedImage(320, 180)).WholeImageSelection()
+				target = image.New(fake.NewAcceleratedImage(640, 360)).WholeImageSelection()
+			)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tool.Transform(source, target)
+			}
+		})
+	}
+}
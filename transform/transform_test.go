@@ -0,0 +1,208 @@
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jacekolszak/pixiq/image"
+	"github.com/jacekolszak/pixiq/image/fake"
+	"github.com/jacekolszak/pixiq/transform"
+)
+
+// palette maps characters used in test patterns to colors
+var palette = map[byte]image.Color{
+	'.': image.Transparent,
+	'#': image.RGB(10, 20, 30),
+	'a': image.RGB(1, 0, 0),
+	'b': image.RGB(2, 0, 0),
+	'c': image.RGB(3, 0, 0),
+	'd': image.RGB(4, 0, 0),
+	'e': image.RGB(5, 0, 0),
+	'f': image.RGB(6, 0, 0),
+}
+
+var source = []string{
+	"abc",
+	"def",
+}
+
+func TestTool_Transform(t *testing.T) {
+	tests := map[string]struct {
+		tool     *transform.Tool
+		target   []string
+		expected []string
+	}{
+		"flip horizontal": {
+			tool:     transform.NewFlipHorizontal(),
+			target:   []string{"...", "..."},
+			expected: []string{"cba", "fed"},
+		},
+		"flip vertical": {
+			tool:     transform.NewFlipVertical(),
+			target:   []string{"...", "..."},
+			expected: []string{"def", "abc"},
+		},
+		"rotate 90": {
+			tool:     transform.NewRotate90(),
+			target:   []string{"..", "..", ".."},
+			expected: []string{"da", "eb", "fc"},
+		},
+		"rotate 180": {
+			tool:     transform.NewRotate180(),
+			target:   []string{"...", "..."},
+			expected: []string{"fed", "cba"},
+		},
+		"rotate 270": {
+			tool:     transform.NewRotate270(),
+			target:   []string{"..", "..", ".."},
+			expected: []string{"cf", "be", "ad"},
+		},
+		"scale 1": {
+			tool:     transform.NewScale(1),
+			target:   []string{"...", "..."},
+			expected: []string{"abc", "def"},
+		},
+		"scale 2": {
+			tool:     transform.NewScale(2),
+			target:   []string{"......", "......", "......", "......"},
+			expected: []string{"aabbcc", "aabbcc", "ddeeff", "ddeeff"},
+		},
+		"downscale 2": {
+			tool:     transform.NewDownscale(2),
+			target:   []string{"..", ".."},
+			expected: []string{"a.", ".."},
+		},
+		"target bigger than result": {
+			tool:     transform.NewRotate90(),
+			target:   []string{"###", "###", "###", "###"},
+			expected: []string{"da#", "eb#", "fc#", "###"},
+		},
+		"target smaller than result": {
+			tool:     transform.NewFlipHorizontal(),
+			target:   []string{".."},
+			expected: []string{"cb"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sourceImage := newImage(source)
+			targetImage := newImage(test.target)
+			// when
+			test.tool.Transform(sourceImage.WholeImageSelection(), targetImage.WholeImageSelection())
+			// then
+			assertPixels(t, targetImage, test.expected)
+		})
+	}
+	t.Run("should use target position", func(t *testing.T) {
+		sourceImage := newImage(source)
+		targetImage := newImage([]string{"####", "####", "####"})
+		// when
+		transform.NewFlipVertical().Transform(sourceImage.WholeImageSelection(), targetImage.Selection(1, 1))
+		// then
+		assertPixels(t, targetImage, []string{"####", "#def", "#abc"})
+	})
+	t.Run("should transform target partially outside the image", func(t *testing.T) {
+		sourceImage := newImage(source)
+		targetImage := newImage([]string{"##", "##"})
+		// when
+		transform.NewFlipHorizontal().Transform(sourceImage.WholeImageSelection(), targetImage.Selection(-1, -1))
+		// then
+		assertPixels(t, targetImage, []string{"ed", "##"})
+	})
+	t.Run("should skip target outside the image", func(t *testing.T) {
+		sourceImage := newImage(source)
+		targetImage := newImage([]string{"##"})
+		tool := transform.NewFlipHorizontal()
+		// when
+		tool.Transform(sourceImage.WholeImageSelection(), targetImage.Selection(2, 0))
+		tool.Transform(sourceImage.WholeImageSelection(), targetImage.Selection(-3, 0))
+		tool.Transform(sourceImage.WholeImageSelection(), targetImage.Selection(0, 1))
+		tool.Transform(sourceImage.WholeImageSelection(), targetImage.Selection(0, -2))
+		// then
+		assertPixels(t, targetImage, []string{"##"})
+	})
+	t.Run("should use source selection", func(t *testing.T) {
+		sourceImage := newImage(source)
+		targetImage := newImage([]string{"..", ".."})
+		// when
+		transform.NewRotate180().Transform(sourceImage.Selection(1, 0).WithSize(2, 2), targetImage.WholeImageSelection())
+		// then
+		assertPixels(t, targetImage, []string{"fe", "cb"})
+	})
+	t.Run("should use transparent color for source pixels outside the image", func(t *testing.T) {
+		sourceImage := newImage(source)
+		targetImage := newImage([]string{"###", "###"})
+		// when
+		transform.NewFlipHorizontal().Transform(sourceImage.Selection(1, 1).WithSize(3, 2), targetImage.WholeImageSelection())
+		// then
+		assertPixels(t, targetImage, []string{".fe", "..."})
+	})
+	t.Run("should use transparent color for source pixels before the image", func(t *testing.T) {
+		sourceImage := newImage(source)
+		targetImage := newImage([]string{"###", "###"})
+		// when
+		transform.NewFlipHorizontal().Transform(sourceImage.Selection(-1, -1).WithSize(3, 2), targetImage.WholeImageSelection())
+		// then
+		assertPixels(t, targetImage, []string{"...", "ba."})
+	})
+}
+
+func TestNewScale(t *testing.T) {
+	t.Run("should panic when factor is lower than 1", func(t *testing.T) {
+		assert.Panics(t, func() {
+			transform.NewScale(0)
+		})
+	})
+}
+
+func TestNewDownscale(t *testing.T) {
+	t.Run("should panic when factor is lower than 1", func(t *testing.T) {
+		assert.Panics(t, func() {
+			transform.NewDownscale(0)
+		})
+	})
+}
+
+func TestTool_TargetSize(t *testing.T) {
+	tests := map[string]struct {
+		tool                          *transform.Tool
+		expectedWidth, expectedHeight int
+	}{
+		"flip horizontal": {tool: transform.NewFlipHorizontal(), expectedWidth: 5, expectedHeight: 3},
+		"flip vertical":   {tool: transform.NewFlipVertical(), expectedWidth: 5, expectedHeight: 3},
+		"rotate 90":       {tool: transform.NewRotate90(), expectedWidth: 3, expectedHeight: 5},
+		"rotate 180":      {tool: transform.NewRotate180(), expectedWidth: 5, expectedHeight: 3},
+		"rotate 270":      {tool: transform.NewRotate270(), expectedWidth: 3, expectedHeight: 5},
+		"scale 3":         {tool: transform.NewScale(3), expectedWidth: 15, expectedHeight: 9},
+		"downscale 2":     {tool: transform.NewDownscale(2), expectedWidth: 2, expectedHeight: 1},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			width, height := test.tool.TargetSize(5, 3)
+			assert.Equal(t, test.expectedWidth, width)
+			assert.Equal(t, test.expectedHeight, height)
+		})
+	}
+}
+
+func newImage(pixels []string) *image.Image {
+	img := image.New(fake.NewAcceleratedImage(len(pixels[0]), len(pixels)))
+	selection := img.WholeImageSelection()
+	for y, line := range pixels {
+		for x := 0; x < len(line); x++ {
+			selection.SetColor(x, y, palette[line[x]])
+		}
+	}
+	return img
+}
+
+func assertPixels(t *testing.T, img *image.Image, expected []string) {
+	selection := img.WholeImageSelection()
+	for y := 0; y < img.Height(); y++ {
+		for x := 0; x < img.Width(); x++ {
+			expectedColor := palette[expected[y][x]]
+			assert.Equal(t, expectedColor, selection.Color(x, y), "position (%d,%d)", x, y)
+		}
+	}
+}